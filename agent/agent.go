@@ -2,6 +2,8 @@ package agent
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -13,9 +15,6 @@ import (
 	"github.com/standardcore/go-logging"
 
 	"github.com/standardcore/Matcha/essentials"
-
-	fnet "github.com/FeiniuBus/ecosystem/net"
-	"github.com/hashicorp/consul/api"
 )
 
 // consts
@@ -31,7 +30,19 @@ type Agent struct {
 	httpAddr  ProtoAddr
 	wgServers sync.WaitGroup
 
+	tlsCerts  *tlsCertificates
+	tlsConfig *tls.Config
+	verifier  *requestVerifier
+
+	consul *consulService
+
+	httpServerDown bool
+
 	shutdownLock sync.Mutex
+
+	loader     func() (*Config, error)
+	logLevel   *essentials.LogLevelFilter
+	reloadLock sync.Mutex
 }
 
 func New(c *Config, logger logging.Logger) (*Agent, error) {
@@ -40,6 +51,19 @@ func New(c *Config, logger logging.Logger) (*Agent, error) {
 		return nil, fmt.Errorf("Invalid HTTP bind address: %s", err)
 	}
 
+	var tlsCerts *tlsCertificates
+	var tlsConfig *tls.Config
+	if c.TLSEnabled() {
+		tlsCerts, err = newTLSCertificates(c)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig, err = tlsCerts.ServerConfig(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	sess, err := essentials.NewSession(c.Parameters, c.Declarations)
 
 	if err != nil {
@@ -48,12 +72,26 @@ func New(c *Config, logger logging.Logger) (*Agent, error) {
 
 	sess.SETLogger(logger)
 
+	err = sess.SETEnv(c.Env)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := essentials.NewSpanExporter(sess.LoadOrEmpty("trace_exporter"))
+	if err != nil {
+		return nil, err
+	}
+	sess.SETSpanExporter(exporter)
+
 	a := &Agent{
-		config:   c,
-		httpAddr: httpAddr,
-		sess:     sess,
-		msrv:     NewMServer(sess),
+		config:    c,
+		httpAddr:  httpAddr,
+		sess:      sess,
+		tlsCerts:  tlsCerts,
+		tlsConfig: tlsConfig,
+		verifier:  newRequestVerifier(c.Signing),
 	}
+	a.msrv = NewMServer(sess).Outboxes(c.Outboxes).Reloader(a.Reload).Verifier(a.verifier)
 
 	err = a.msrv.StartUp()
 	if err != nil {
@@ -71,56 +109,184 @@ func (a *Agent) GetSession() *essentials.Session {
 	return a.sess
 }
 
-// StartSync is
-func (a *Agent) StartSync() {
-	config := api.DefaultConfig()
-	config.Address = fmt.Sprintf("%s:%d", a.config.ConsulAddr, a.config.ConsulPort)
-	config.Datacenter = a.config.ConsulDatacenter
+// ConfigLoader sets how Reload reads the configuration again.
+func (a *Agent) ConfigLoader(loader func() (*Config, error)) *Agent {
+	a.loader = loader
+	return a
+}
 
-	client, err := api.NewClient(config)
+// LogLevel sets the filter Reload applies `log_level` to.
+func (a *Agent) LogLevel(filter *essentials.LogLevelFilter) *Agent {
+	a.logLevel = filter
+	return a
+}
+
+// SpanExporter exports the spans of the agent with exporter, in place of the
+// one of the `trace_exporter` parameter.
+func (a *Agent) SpanExporter(exporter essentials.SpanExporter) *Agent {
+	a.sess.SETSpanExporter(exporter)
+	return a
+}
+
+// Reload reads the configuration again and applies it to the running agent:
+// the session parameters and declarations, `log_level`, `shutdown_timeout`,
+// the `signing` clients and the TLS certificates, read again from their
+// files. The report lists the settings that changed but need a
+// restart.
+func (a *Agent) Reload() (*essentials.ReloadReport, error) {
+	if a.loader == nil {
+		return nil, errors.New("configuration reload is not enabled")
+	}
+	c, err := a.loader()
 	if err != nil {
-		a.sess.Logger().Errorf("agent: failed to sync start: %v\n", err)
-		return
+		return nil, essentials.WrapError("Agent.Reload", err)
 	}
 
-	ips, err := fnet.InterfaceIPV4Addrs()
+	a.reloadLock.Lock()
+	defer a.reloadLock.Unlock()
+	report := a.sess.Reload(c.Parameters, c.Declarations)
+
+	if c.LogLevel != a.config.LogLevel && a.logLevel != nil {
+		err = a.logLevel.SetLevel(c.LogLevel)
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+		} else {
+			report.Applied = append(report.Applied, "log_level")
+		}
+	}
+	if c.ShutdownTimeout != a.config.ShutdownTimeout {
+		report.Applied = append(report.Applied, "shutdown_timeout")
+	}
+	if !sameJSON(c.Signing, a.config.Signing) {
+		a.verifier.Configure(c.Signing)
+		report.Applied = append(report.Applied, "signing")
+	}
+	if a.tlsCerts != nil {
+		err = a.tlsCerts.Reload()
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+		} else {
+			report.Applied = append(report.Applied, "tls_certificates")
+		}
+	}
+	restart := []struct {
+		key     string
+		changed bool
+	}{
+		{"env", !strings.EqualFold(c.Env, a.config.Env)},
+		{"client_addr", c.Address != a.config.Address},
+		{"port", c.Port != a.config.Port},
+		{"consul_addr", c.ConsulAddr != a.config.ConsulAddr},
+		{"consul_port", c.ConsulPort != a.config.ConsulPort},
+		{"consul_dc", c.ConsulDatacenter != a.config.ConsulDatacenter},
+		{"consul", !sameJSON(c.Consul, a.config.Consul)},
+		{"outboxes", !sameJSON(c.Outboxes, a.config.Outboxes)},
+		{"cert_file", c.CertFile != a.config.CertFile},
+		{"key_file", c.KeyFile != a.config.KeyFile},
+		{"ca_file", c.CAFile != a.config.CAFile},
+		{"tls_min_version", c.TLSMinVersion != a.config.TLSMinVersion},
+		{"verify_incoming", c.VerifyIncoming != a.config.VerifyIncoming},
+		{"logging", !sameJSON(c.LogProviders(), a.config.LogProviders())},
+	}
+	for _, r := range restart {
+		if r.changed {
+			report.RestartRequired = append(report.RestartRequired, r.key)
+		}
+	}
+
+	config := *a.config
+	config.LogLevel = c.LogLevel
+	config.ShutdownTimeout = c.ShutdownTimeout
+	config.Signing = c.Signing
+	config.Parameters = c.Parameters
+	config.Declarations = c.Declarations
+	a.config = &config
+
+	a.sess.Logger().Infoln(fmt.Sprintf("agent: Configuration reloaded, applied: %s, restart required: %s",
+		strings.Join(report.Applied, ", "), strings.Join(report.RestartRequired, ", ")))
+	for _, e := range report.Errors {
+		a.sess.Logger().Errorln("agent: Reload failed: " + e)
+	}
+	return report, nil
+}
+
+func sameJSON(a interface{}, b interface{}) bool {
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && string(x) == string(y)
+}
+
+// StartSync registers the agent in Consul. Registration goes on in the
+// background until Consul accepts it, so an unavailable Consul does not
+// keep the agent from serving.
+func (a *Agent) StartSync() {
+	consul, err := newConsulService(a.config, a.httpAddr, a.sess.Logger())
 	if err != nil {
 		a.sess.Logger().Errorf("agent: failed to sync start: %v\n", err)
 		return
 	}
+	a.shutdownLock.Lock()
+	a.consul = consul
+	a.shutdownLock.Unlock()
+	consul.Start()
+}
+
+// Shutdown drains the agent: the service is put in Consul maintenance mode,
+// the HTTP server stops accepting requests and finishes the ones in flight,
+// the AMQP consumers are cancelled, the processors, the scheduler and the
+// outbox relay finish their current round, then the service is deregistered
+// from Consul and the session closed. It reports whether the drain completed
+// within `shutdown_timeout`.
+func (a *Agent) Shutdown() bool {
+	a.reloadLock.Lock()
+	timeout := time.Duration(a.config.ShutdownTimeout) * time.Second
+	a.reloadLock.Unlock()
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-	agent := client.Agent()
+	a.sess.Logger().Infof("agent: Shutting down, draining for up to %s\n", timeout)
+	a.shutdownLock.Lock()
+	msrv := a.msrv
+	consul := a.consul
+	a.consul = nil
+	a.shutdownLock.Unlock()
+	if consul != nil {
+		err := consul.Maintenance("matcha is shutting down")
+		if err != nil {
+			a.sess.Logger().Errorf("agent: failed to enable maintenance: [ServiceID: %s] (%s)\n", consul.reg.ID, err)
+		}
+	}
 
-	reg := &api.AgentServiceRegistration{
-		ID:   ServiceName + "-" + ips[0],
-		Name: ServiceName,
-		Tags: []string{
-			"matcha",
-		},
-		Address:           ips[0],
-		Port:              a.config.Port,
-		EnableTagOverride: false,
+	drained := a.ShutdownEndpoints(ctx)
+
+	if msrv != nil && !msrv.Shutdown(ctx) {
+		a.sess.Logger().Warnln("agent: Timeout draining consumers and workers")
+		drained = false
 	}
 
-	reg.Check = &api.AgentServiceCheck{
-		DeregisterCriticalServiceAfter: "10s",
-		Interval:                       "5s",
-		HTTP:                           fmt.Sprintf("http://%s:%d/v1/check", reg.Address, reg.Port),
-		Method:                         http.MethodGet,
-		Timeout:                        "1s",
+	if consul != nil {
+		err := consul.Deregister()
+		if err != nil {
+			a.sess.Logger().Errorf("agent: failed deregister service: [ServiceID: %s] (%s)\n", consul.reg.ID, err)
+		}
 	}
 
-	err = agent.ServiceRegister(reg)
+	err := a.sess.Close()
 	if err != nil {
-		a.sess.Logger().Errorf("agent: failed register service: [ServiceName: %s] (%s)\n", ServiceName, err)
+		a.sess.Logger().Errorln(essentials.WrapError("agent.Shutdown", err))
+	}
+	if drained {
+		a.sess.Logger().Infoln("agent: Shutdown complete")
 	}
+	return drained
 }
 
 // Start is
 func (a *Agent) Start() error {
 	var e error
-	//a.logger, e = log.NewLogstash(false, a.config.LogstashHost, a.config.LogstashPort)
-	//a.sess.Logger().TryAddProvider(essentials.NewLogstashProvider(false, a.config.LogstashHost, a.config.LogstashPort))
 	if e != nil {
 		panic(e)
 	}
@@ -142,8 +308,9 @@ func (a *Agent) Start() error {
 	return nil
 }
 
-// ShutdownEndpoints terminates the HTTP servers.
-func (a *Agent) ShutdownEndpoints() {
+// ShutdownEndpoints terminates the HTTP servers, waiting for the requests in
+// flight until ctx is done. It reports whether they all finished.
+func (a *Agent) ShutdownEndpoints(ctx context.Context) bool {
 	a.shutdownLock.Lock()
 	defer a.shutdownLock.Unlock()
 
@@ -151,8 +318,8 @@ func (a *Agent) ShutdownEndpoints() {
 	// 	return
 	// }
 
-	if a.msrv == nil {
-		return
+	if a.httpServerDown || a.msrv == nil {
+		return true
 	}
 
 	//a.logger.Printf("agent: Stopping %s server %s", strings.ToUpper(a.srv.httpProtocol), a.srv.httpAddress)
@@ -163,18 +330,18 @@ func (a *Agent) ShutdownEndpoints() {
 	// 	a.logger.Warnf("agent: Timeout stopping %s server %s", strings.ToUpper(a.srv.httpProtocol), a.srv.httpAddress)
 	// }
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-	a.msrv.HTTPServer().Shutdown(ctx)
-	if ctx.Err() == context.DeadlineExceeded {
+	err := a.msrv.HTTPServer().Shutdown(ctx)
+	if err == context.DeadlineExceeded {
 		a.GetSession().Logger().Warnf("agent: Timeout stopping %s server %s", strings.ToUpper(a.msrv.httpProtocol), a.msrv.httpAddress)
+		return false
 	}
 	//a.srv = nil
-	a.msrv = nil
+	a.httpServerDown = true
 
 	a.GetSession().Logger().Println("agent: Waiting for endpoings so shut down")
 	a.wgServers.Wait()
 	a.GetSession().Logger().Println("agent: Endpoings down")
+	return true
 }
 
 func (a *Agent) listenHTTP(addr ProtoAddr) (net.Listener, error) {
@@ -188,7 +355,13 @@ func (a *Agent) listenHTTP(addr ProtoAddr) (net.Listener, error) {
 			return nil, err
 		}
 	case addr.Net == "tcp" && addr.Proto == "https":
-		return nil, errors.New("Not support https protocol")
+		if a.tlsConfig == nil {
+			return nil, errors.New("https listener requires cert_file and key_file")
+		}
+		l, err = net.Listen("tcp", addr.Addr)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("%s:%s listener not supported", addr.Net, addr.Proto)
 	}
@@ -197,6 +370,10 @@ func (a *Agent) listenHTTP(addr ProtoAddr) (net.Listener, error) {
 		l = &tcpKeepAliveListener{tcpl}
 	}
 
+	if addr.Proto == "https" {
+		l = tls.NewListener(l, a.tlsConfig)
+	}
+
 	return l, nil
 
 }
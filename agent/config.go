@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/standardcore/Matcha/essentials"
+	"github.com/standardcore/Matcha/outbox"
 )
 
 type Config struct {
@@ -26,6 +27,23 @@ type Config struct {
 	LogstashHost     string `json:"logstash_host"`
 	LogstashPort     int    `json:"logstash_port"`
 	ServicePrefix    string `json:"service_prefix"`
+	// ShutdownTimeout bounds the drain on SIGINT/SIGTERM, in seconds.
+	ShutdownTimeout int `json:"shutdown_timeout"`
+
+	// CertFile and KeyFile make the HTTP listener serve HTTPS. CAFile
+	// verifies client certificates, which VerifyIncoming makes mandatory.
+	CertFile       string `json:"cert_file"`
+	KeyFile        string `json:"key_file"`
+	CAFile         string `json:"ca_file"`
+	TLSMinVersion  string `json:"tls_min_version"`
+	VerifyIncoming bool   `json:"verify_incoming"`
+	// ConsulCheckTLSSkipVerify lets the Consul HTTPS check accept a
+	// certificate that does not name the advertised address.
+	ConsulCheckTLSSkipVerify bool `json:"consul_check_tls_skip_verify"`
+	// Consul configures the registered service.
+	Consul *ConsulConfig `json:"consul"`
+
+	Signing *SigningConfig `json:"signing"`
 
 	// for sdk
 	ResourcePath     string `json:"resource_path"`
@@ -35,16 +53,21 @@ type Config struct {
 	Schema           string `json:"schema"`
 
 	ConsoleOutput string `json:"console_output"`
+	// Logging lists the log providers. Without it, console_output,
+	// enable_syslog and logstash_host choose them.
+	Logging []*essentials.LogProviderConfig `json:"logging"`
 
 	Parameters   map[string]string              `json:"parameters"`
 	Declarations *essentials.DeclarationsConfig `json:"declarations"`
+	Outboxes     []*outbox.Source               `json:"outboxes"`
 }
 
 type ProtoAddr struct {
 	Proto, Net, Addr string
 }
 
-// HTTPAddr returns the bind addresses for the HTTP server
+// HTTPAddr returns the bind addresses for the HTTP server, which serves
+// HTTPS when a certificate is configured.
 func (c *Config) HTTPAddr() (ProtoAddr, error) {
 	ip := net.ParseIP(c.Address)
 	if ip == nil {
@@ -52,10 +75,37 @@ func (c *Config) HTTPAddr() (ProtoAddr, error) {
 	}
 
 	a := &net.TCPAddr{IP: ip, Port: c.Port}
+	if c.TLSEnabled() {
+		return ProtoAddr{"https", a.Network(), a.String()}, nil
+	}
 	return ProtoAddr{"http", a.Network(), a.String()}, nil
+}
+
+// TLSEnabled reports whether the HTTP listener serves HTTPS.
+func (c *Config) TLSEnabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
 
 }
 
+// LogProviders returns the `logging` providers, or those of the legacy
+// console_output, enable_syslog and logstash_host settings.
+func (c *Config) LogProviders() []*essentials.LogProviderConfig {
+	if len(c.Logging) > 0 {
+		return c.Logging
+	}
+	providers := make([]*essentials.LogProviderConfig, 0)
+	if c.ConsoleOutput == "true" {
+		providers = append(providers, &essentials.LogProviderConfig{Type: "console"})
+	}
+	if c.EnableSyslog {
+		providers = append(providers, &essentials.LogProviderConfig{Type: "syslog"})
+	}
+	if c.LogstashHost != "" && c.LogstashPort != 0 {
+		providers = append(providers, &essentials.LogProviderConfig{Type: "logstash", Host: c.LogstashHost, Port: c.LogstashPort})
+	}
+	return providers
+}
+
 // DefaultConfig is used to return a default configuration
 func DefaultConfig() *Config {
 	return &Config{
@@ -65,6 +115,7 @@ func DefaultConfig() *Config {
 		LogLevel:         "INFO",
 		ConsulDatacenter: "dc1",
 		ConsulPort:       8500,
+		ShutdownTimeout:  30,
 		Parameters:       make(map[string]string),
 		Declarations:     essentials.NewDeclarationsConfig(),
 	}
@@ -105,6 +156,33 @@ func MergeConfig(a, b *Config) *Config {
 	if b.LogstashPort != 0 {
 		result.LogstashPort = b.LogstashPort
 	}
+	if b.ShutdownTimeout != 0 {
+		result.ShutdownTimeout = b.ShutdownTimeout
+	}
+	if b.CertFile != "" {
+		result.CertFile = b.CertFile
+	}
+	if b.KeyFile != "" {
+		result.KeyFile = b.KeyFile
+	}
+	if b.CAFile != "" {
+		result.CAFile = b.CAFile
+	}
+	if b.TLSMinVersion != "" {
+		result.TLSMinVersion = b.TLSMinVersion
+	}
+	if b.VerifyIncoming {
+		result.VerifyIncoming = true
+	}
+	if b.ConsulCheckTLSSkipVerify {
+		result.ConsulCheckTLSSkipVerify = true
+	}
+	if b.Consul != nil {
+		result.Consul = mergeConsulConfig(a.Consul, b.Consul)
+	}
+	if b.Signing != nil {
+		result.Signing = mergeSigningConfig(a.Signing, b.Signing)
+	}
 
 	if b.ResourcePath != "" {
 		result.ResourcePath = b.ResourcePath
@@ -126,6 +204,14 @@ func MergeConfig(a, b *Config) *Config {
 		result.Schema = b.Schema
 	}
 
+	if b.ConsoleOutput != "" {
+		result.ConsoleOutput = b.ConsoleOutput
+	}
+
+	if b.Logging != nil {
+		result.Logging = b.Logging
+	}
+
 	if b.Parameters != nil && len(b.Parameters) > 0 {
 		if result.Parameters == nil {
 			result.Parameters = make(map[string]string)
@@ -151,6 +237,10 @@ func MergeConfig(a, b *Config) *Config {
 		}
 	}
 
+	if b.Outboxes != nil && len(b.Outboxes) > 0 {
+		result.Outboxes = append(result.Outboxes, b.Outboxes...)
+	}
+
 	return &result
 }
 
@@ -0,0 +1,288 @@
+package agent
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	fnet "github.com/FeiniuBus/ecosystem/net"
+	"github.com/hashicorp/consul/api"
+	"github.com/standardcore/go-logging"
+)
+
+// ConsulConfig configures the service the agent registers in Consul. Every
+// field is optional.
+type ConsulConfig struct {
+	// ServiceID defaults to <service_name>-<advertise_addr>-<port>.
+	ServiceID string `json:"service_id"`
+	// ServiceName defaults to "matcha".
+	ServiceName string            `json:"service_name"`
+	Tags        []string          `json:"tags"`
+	Meta        map[string]string `json:"meta"`
+	// AdvertiseAddr is the address Consul checks and hands out. It defaults
+	// to client_addr when that is a routable address, else to the first
+	// non-loopback IPv4 address of the host, else to loopback.
+	AdvertiseAddr string `json:"advertise_addr"`
+	// DeregisterCriticalAfter removes a service whose check stays critical,
+	// such as one of a process that was killed, as a Consul duration
+	// (default "1m").
+	DeregisterCriticalAfter string `json:"deregister_critical_after"`
+	// RetryMaxInterval caps the wait between registration attempts while
+	// Consul is unavailable, in seconds (default 30).
+	RetryMaxInterval int `json:"retry_max_interval"`
+}
+
+func mergeConsulConfig(a, b *ConsulConfig) *ConsulConfig {
+	if a == nil {
+		a = &ConsulConfig{}
+	}
+	result := *a
+	if b.ServiceID != "" {
+		result.ServiceID = b.ServiceID
+	}
+	if b.ServiceName != "" {
+		result.ServiceName = b.ServiceName
+	}
+	if b.Tags != nil {
+		result.Tags = b.Tags
+	}
+	result.Meta = make(map[string]string)
+	for k, v := range a.Meta {
+		result.Meta[k] = v
+	}
+	for k, v := range b.Meta {
+		result.Meta[k] = v
+	}
+	if b.AdvertiseAddr != "" {
+		result.AdvertiseAddr = b.AdvertiseAddr
+	}
+	if b.DeregisterCriticalAfter != "" {
+		result.DeregisterCriticalAfter = b.DeregisterCriticalAfter
+	}
+	if b.RetryMaxInterval != 0 {
+		result.RetryMaxInterval = b.RetryMaxInterval
+	}
+	return &result
+}
+
+// serviceRegistration adds the Meta the vendored consul/api registration
+// lacks; it is sent to /v1/agent/service/register as is.
+type serviceRegistration struct {
+	api.AgentServiceRegistration
+	Meta map[string]string `json:",omitempty"`
+}
+
+// consulService registers the agent in Consul, retrying in the background
+// until Consul accepts it, and takes it out again on shutdown.
+type consulService struct {
+	client *api.Client
+	reg    *serviceRegistration
+	logger logging.Logger
+
+	retryMin time.Duration
+	retryMax time.Duration
+
+	mu         sync.Mutex
+	registered bool
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+func newConsulService(c *Config, httpAddr ProtoAddr, logger logging.Logger) (*consulService, error) {
+	config := api.DefaultConfig()
+	config.Address = fmt.Sprintf("%s:%d", c.ConsulAddr, c.ConsulPort)
+	config.Datacenter = c.ConsulDatacenter
+	client, err := api.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	consul := c.Consul
+	if consul == nil {
+		consul = &ConsulConfig{}
+	}
+	address, err := advertiseAddr(consul.AdvertiseAddr, c.Address)
+	if err != nil {
+		return nil, err
+	}
+	name := consul.ServiceName
+	if name == "" {
+		name = ServiceName
+	}
+	id := consul.ServiceID
+	if id == "" {
+		id = fmt.Sprintf("%s-%s-%d", name, address, c.Port)
+	}
+	tags := consul.Tags
+	if tags == nil {
+		tags = []string{"matcha"}
+	}
+	deregisterAfter := consul.DeregisterCriticalAfter
+	if deregisterAfter == "" {
+		deregisterAfter = "1m"
+	}
+	retryMax := time.Duration(consul.RetryMaxInterval) * time.Second
+	if retryMax <= 0 {
+		retryMax = 30 * time.Second
+	}
+
+	reg := &serviceRegistration{
+		AgentServiceRegistration: api.AgentServiceRegistration{
+			ID:                id,
+			Name:              name,
+			Tags:              tags,
+			Address:           address,
+			Port:              c.Port,
+			EnableTagOverride: false,
+		},
+		Meta: consul.Meta,
+	}
+	reg.Check = &api.AgentServiceCheck{
+		DeregisterCriticalServiceAfter: deregisterAfter,
+		Interval:                       "5s",
+		HTTP:                           fmt.Sprintf("%s://%s/v1/health/ready", httpAddr.Proto, net.JoinHostPort(address, strconv.Itoa(c.Port))),
+		Method:                         http.MethodGet,
+		Timeout:                        "3s",
+		TLSSkipVerify:                  c.ConsulCheckTLSSkipVerify,
+	}
+
+	return &consulService{
+		client:   client,
+		reg:      reg,
+		logger:   logger,
+		retryMin: time.Second,
+		retryMax: retryMax,
+	}, nil
+}
+
+// advertiseAddr returns configured, or else the address the agent is
+// reachable on.
+func advertiseAddr(configured string, bind string) (string, error) {
+	if configured != "" {
+		if net.ParseIP(configured) == nil {
+			return "", fmt.Errorf("consul advertise_addr '%s' is not an IP address", configured)
+		}
+		return configured, nil
+	}
+	ip := net.ParseIP(bind)
+	if ip != nil && !ip.IsLoopback() && !ip.IsUnspecified() {
+		return bind, nil
+	}
+	ips, err := fnet.InterfaceIPV4Addrs()
+	if err == nil && len(ips) > 0 {
+		return ips[0], nil
+	}
+	if ip != nil && ip.IsLoopback() {
+		return bind, nil
+	}
+	return "127.0.0.1", nil
+}
+
+// Start registers the service in the background. Failed attempts are
+// retried after 1 second, doubling up to retry_max_interval, until one
+// succeeds or Stop is called.
+func (s *consulService) Start() {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	stop, done := s.stop, s.done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		delay := s.retryMin
+		for {
+			err := s.register(stop)
+			if err == nil {
+				return
+			}
+			s.logger.Errorln(fmt.Sprintf("agent: failed register service: [ServiceID: %s] (%s), retrying in %s", s.reg.ID, err, delay))
+			select {
+			case <-time.After(delay):
+			case <-stop:
+				return
+			}
+			delay *= 2
+			if delay > s.retryMax {
+				delay = s.retryMax
+			}
+		}
+	}()
+}
+
+// register writes the registration. When Stop was called while the write was
+// in flight, the service is taken out again instead of staying registered
+// with nobody left to deregister it.
+func (s *consulService) register(stop chan struct{}) error {
+	_, err := s.client.Raw().Write("/v1/agent/service/register", s.reg, nil, nil)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	select {
+	case <-stop:
+		s.mu.Unlock()
+		err = s.client.Agent().ServiceDeregister(s.reg.ID)
+		if err != nil {
+			s.logger.Errorln(fmt.Sprintf("agent: failed deregister service stopped while registering: [ServiceID: %s] (%s)", s.reg.ID, err))
+		}
+		return nil
+	default:
+	}
+	s.registered = true
+	s.mu.Unlock()
+	s.logger.Infoln(fmt.Sprintf("agent: Registered service %s in Consul", s.reg.ID))
+	return nil
+}
+
+// Stop ends the registration attempts and reports whether the service was
+// registered.
+func (s *consulService) Stop() bool {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	if stop != nil {
+		select {
+		case <-stop:
+		default:
+			close(stop)
+		}
+	}
+	s.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.registered
+}
+
+// Maintenance stops the registration attempts and puts the service in
+// maintenance mode, so that Consul stops handing it out while it drains.
+func (s *consulService) Maintenance(reason string) error {
+	if !s.Stop() {
+		return nil
+	}
+	return s.client.Agent().EnableServiceMaintenance(s.reg.ID, reason)
+}
+
+// Deregister removes the service from Consul.
+func (s *consulService) Deregister() error {
+	if !s.Stop() {
+		return nil
+	}
+	err := s.client.Agent().ServiceDeregister(s.reg.ID)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.registered = false
+	s.mu.Unlock()
+	return nil
+}
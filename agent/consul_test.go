@@ -0,0 +1,181 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/standardcore/go-logging"
+)
+
+// fakeConsul serves the agent endpoints the consulService calls.
+type fakeConsul struct {
+	mu          sync.Mutex
+	failures    int
+	attempts    []time.Time
+	services    map[string]bool
+	maintenance map[string]string
+
+	// entered and release, when set, hold a registration in flight.
+	entered chan struct{}
+	release chan struct{}
+}
+
+func newFakeConsul() *fakeConsul {
+	return &fakeConsul{services: make(map[string]bool), maintenance: make(map[string]string)}
+}
+
+func (f *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	switch {
+	case r.URL.Path == "/v1/agent/service/register":
+		if f.entered != nil {
+			close(f.entered)
+			<-f.release
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.attempts = append(f.attempts, time.Now())
+		if len(f.attempts) <= f.failures {
+			http.Error(w, "no cluster leader", http.StatusInternalServerError)
+			return
+		}
+		f.services["matcha-1"] = true
+	case strings.HasPrefix(r.URL.Path, "/v1/agent/service/maintenance/"):
+		id := strings.TrimPrefix(r.URL.Path, "/v1/agent/service/maintenance/")
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if r.URL.Query().Get("enable") == "true" {
+			f.maintenance[id] = r.URL.Query().Get("reason")
+		} else {
+			delete(f.maintenance, id)
+		}
+	case strings.HasPrefix(r.URL.Path, "/v1/agent/service/deregister/"):
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.services, strings.TrimPrefix(r.URL.Path, "/v1/agent/service/deregister/"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeConsul) registered(id string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.services[id]
+}
+
+func newTestConsulService(t *testing.T, fake *fakeConsul) *consulService {
+	t.Helper()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	config := api.DefaultConfig()
+	config.Address = strings.TrimPrefix(server.URL, "http://")
+	client, err := api.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &consulService{
+		client:   client,
+		reg:      &serviceRegistration{AgentServiceRegistration: api.AgentServiceRegistration{ID: "matcha-1", Name: "matcha"}},
+		logger:   logging.NewLogger(),
+		retryMin: 5 * time.Millisecond,
+		retryMax: 20 * time.Millisecond,
+	}
+}
+
+func TestConsulServiceRetriesRegistration(t *testing.T) {
+	fake := newFakeConsul()
+	fake.failures = 4
+	s := newTestConsulService(t, fake)
+
+	s.Start()
+	<-s.done
+	if !fake.registered("matcha-1") {
+		t.Fatal("service not registered")
+	}
+	if len(fake.attempts) != 5 {
+		t.Fatalf("%d attempts, want 5", len(fake.attempts))
+	}
+	// The waits double from retryMin and are capped at retryMax.
+	for i, want := range []time.Duration{5, 10, 20, 20} {
+		if gap := fake.attempts[i+1].Sub(fake.attempts[i]); gap < want*time.Millisecond {
+			t.Errorf("wait %d was %s, want at least %dms", i+1, gap, want)
+		}
+	}
+	if !s.Stop() {
+		t.Error("Stop reported the service as not registered")
+	}
+}
+
+func TestConsulServiceMaintenanceAndDeregister(t *testing.T) {
+	fake := newFakeConsul()
+	s := newTestConsulService(t, fake)
+	s.Start()
+	<-s.done
+
+	err := s.Maintenance("draining")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reason, ok := fake.maintenance["matcha-1"]; !ok || reason != "draining" {
+		t.Errorf("maintenance %v %q, want enabled with reason draining", ok, reason)
+	}
+	err = s.Deregister()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.registered("matcha-1") {
+		t.Error("service still registered after Deregister")
+	}
+	if s.Stop() {
+		t.Error("Stop reported the service as registered after Deregister")
+	}
+}
+
+func TestConsulServiceStoppedBeforeRegistrationDoesNothing(t *testing.T) {
+	fake := newFakeConsul()
+	fake.failures = 1 << 30
+	s := newTestConsulService(t, fake)
+	s.Start()
+
+	err := s.Maintenance("draining")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Deregister()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.maintenance) != 0 {
+		t.Errorf("maintenance enabled on a service never registered: %v", fake.maintenance)
+	}
+}
+
+func TestConsulServiceStopDuringRegistration(t *testing.T) {
+	fake := newFakeConsul()
+	fake.entered = make(chan struct{})
+	fake.release = make(chan struct{})
+	s := newTestConsulService(t, fake)
+	s.Start()
+
+	<-fake.entered
+	stopped := make(chan bool)
+	go func() { stopped <- s.Stop() }()
+	<-s.stop
+	close(fake.release)
+
+	if <-stopped {
+		t.Error("Stop reported the service as registered")
+	}
+	if fake.registered("matcha-1") {
+		t.Error("service left registered after Stop")
+	}
+}
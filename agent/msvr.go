@@ -1,14 +1,23 @@
 package agent
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
 
 	"github.com/standardcore/Matcha/api"
 	"github.com/standardcore/Matcha/backgroundjob"
 	"github.com/standardcore/Matcha/essentials"
+	"github.com/standardcore/Matcha/outbox"
 	"github.com/standardcore/Matcha/rtevent"
 	"github.com/standardcore/go-collections"
 )
@@ -25,6 +34,11 @@ type MServer struct {
 	servicePrefix string
 
 	infiniteProcessor *essentials.InfiniteProcessor
+	scheduler         *backgroundjob.Scheduler
+	relay             *outbox.Relay
+
+	reloader func() (*essentials.ReloadReport, error)
+	verifier *requestVerifier
 }
 
 func NewMServer(sess *essentials.Session) *MServer {
@@ -33,6 +47,7 @@ func NewMServer(sess *essentials.Session) *MServer {
 		once:              collections.NewList(),
 		httpServer:        new(http.Server),
 		infiniteProcessor: essentials.NewInfiniteProcessor(sess),
+		scheduler:         backgroundjob.NewScheduler(sess),
 	}
 }
 
@@ -41,9 +56,51 @@ func (s *MServer) ServicePrefix(prefix string) *MServer {
 	return s
 }
 
+// Outboxes makes the server drain the outbox tables of sources into matcha.
+func (s *MServer) Outboxes(sources []*outbox.Source) *MServer {
+	s.relay = outbox.NewRelay(s.sess, sources)
+	return s
+}
+
+// Reloader serves POST /v1/admin/reload with reload.
+func (s *MServer) Reloader(reload func() (*essentials.ReloadReport, error)) *MServer {
+	s.reloader = reload
+	return s
+}
+
+// Verifier makes the endpoints that publish or change messages, jobs,
+// templates and the configuration check request signatures with verifier.
+func (s *MServer) Verifier(verifier *requestVerifier) *MServer {
+	s.verifier = verifier
+	return s
+}
+
+// signed verifies the signature of the requests to handler, which sees the
+// verified client through essentials.RequestClient.
+func (s *MServer) signed(handler http.HandlerFunc) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if s.verifier == nil {
+			handler(writer, request)
+			return
+		}
+		client, err := s.verifier.Verify(request)
+		if err != nil {
+			s.sess.Logger().Warnln(essentials.WrapError("MServer.signed:"+request.URL.Path, err))
+			writer.WriteHeader(401)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		if client != "" {
+			request = essentials.WithRequestClient(request, client)
+		}
+		handler(writer, request)
+	}
+}
+
 func (s *MServer) StartUp() error {
 	//RabbitMQ Middlewares
-	//s.once.Add(essentials.NewConfirmCallbackMiddleware(s.sess))
+	s.once.Add(essentials.NewConfirmMiddleware(s.sess))
+	s.once.Add(essentials.NewStateChangeMiddleware(s.sess))
 	s.once.Add(backgroundjob.NewFailSafeMiddleware(s.sess))
 	//HTTP
 	//s.http["/check"] = NewCheckMiddleware(s.sess)
@@ -71,12 +128,61 @@ func (s *MServer) Configure() error {
 	if err != nil {
 		return err
 	}
+	s.scheduler.Start()
+
+	if s.relay != nil {
+		err = s.relay.Start()
+		if err != nil {
+			return err
+		}
+	}
 
 	s.infiniteProcessor.Process()
 
 	return nil
 }
 
+// Shutdown cancels the AMQP consumers and stops the processors, the
+// scheduler and the outbox relay, waiting for the work in progress until ctx
+// is done. It reports whether everything stopped in time.
+func (s *MServer) Shutdown(ctx context.Context) bool {
+	stops := make([]func(), 0)
+	collections.ForEach(s.once, func(m interface{}) {
+		if stopper, ok := m.(essentials.Stopper); ok {
+			stops = append(stops, func() {
+				err := stopper.Stop(ctx)
+				if err != nil {
+					s.sess.Logger().Errorln(essentials.WrapError("MServer.Shutdown", err))
+				}
+			})
+		}
+	})
+	stops = append(stops, s.infiniteProcessor.Stop, s.scheduler.Stop)
+	if s.relay != nil {
+		stops = append(stops, s.relay.Stop)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(stops))
+	for _, stop := range stops {
+		go func(stop func()) {
+			defer wg.Done()
+			stop()
+		}(stop)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 func (s *MServer) HTTPServer() *http.Server {
 	return s.httpServer
 }
@@ -103,23 +209,47 @@ func (s *MServer) configureHTTP() error {
 		writer.WriteHeader(204)
 	}).Methods(http.MethodGet)
 
-	r.HandleFunc("/v1/changestate", func(writer http.ResponseWriter, request *http.Request) {
+	r.HandleFunc("/v1/health/live", func(writer http.ResponseWriter, request *http.Request) {
+		writeHealth(writer, s.Health(false))
+	}).Methods(http.MethodGet)
+
+	r.HandleFunc("/v1/health/ready", func(writer http.ResponseWriter, request *http.Request) {
+		writeHealth(writer, s.Health(true))
+	}).Methods(http.MethodGet)
+
+	r.HandleFunc("/v1/changestate", s.signed(func(writer http.ResponseWriter, request *http.Request) {
 		content, err := ioutil.ReadAll(request.Body)
 		if err != nil {
 			writer.WriteHeader(500)
 			writer.Write([]byte(err.Error()))
 			return
 		}
-		err = essentials.ExecuteChangeState(content, s.sess)
+		err = essentials.ExecuteClientChangeState(content, essentials.RequestClient(request), essentials.RequestEnv(request, ""), s.sess)
 		if err != nil {
 			writer.WriteHeader(500)
 			writer.Write([]byte(err.Error()))
 			return
 		}
 		writer.WriteHeader(204)
-	}).Methods(http.MethodPost)
+	})).Methods(http.MethodPost)
 
-	r.HandleFunc("/v1/job/create", func(writer http.ResponseWriter, request *http.Request) {
+	r.HandleFunc("/v1/message/replay", s.signed(func(writer http.ResponseWriter, request *http.Request) {
+		content, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		err = essentials.ExecuteReplayMessage(content, request, s.sess)
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		writer.WriteHeader(204)
+	})).Methods(http.MethodPost)
+
+	r.HandleFunc("/v1/job/create", s.signed(func(writer http.ResponseWriter, request *http.Request) {
 		content, err := ioutil.ReadAll(request.Body)
 		if err != nil {
 			writer.WriteHeader(500)
@@ -134,9 +264,57 @@ func (s *MServer) configureHTTP() error {
 		}
 		writer.WriteHeader(200)
 		writer.Write([]byte(msgid))
-	}).Methods(http.MethodPost)
+	})).Methods(http.MethodPost)
+
+	r.HandleFunc("/v1/job/cancel", s.signed(func(writer http.ResponseWriter, request *http.Request) {
+		content, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		err = backgroundjob.ExecuteCancelJob(content, request, s.sess)
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		writer.WriteHeader(204)
+	})).Methods(http.MethodPost)
+
+	r.HandleFunc("/v1/job/reschedule", s.signed(func(writer http.ResponseWriter, request *http.Request) {
+		content, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		err = backgroundjob.ExecuteRescheduleJob(content, request, s.sess)
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		writer.WriteHeader(204)
+	})).Methods(http.MethodPost)
 
-	r.HandleFunc("/v1/event/publish", func(writer http.ResponseWriter, request *http.Request) {
+	r.HandleFunc("/v1/job/replay", s.signed(func(writer http.ResponseWriter, request *http.Request) {
+		content, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		err = backgroundjob.ExecuteReplayDeadLetter(content, request, s.sess)
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		writer.WriteHeader(204)
+	})).Methods(http.MethodPost)
+
+	r.HandleFunc("/v1/event/publish", s.signed(func(writer http.ResponseWriter, request *http.Request) {
 		content, err := ioutil.ReadAll(request.Body)
 		if err != nil {
 			writer.WriteHeader(500)
@@ -150,7 +328,7 @@ func (s *MServer) configureHTTP() error {
 			return
 		}
 		writer.WriteHeader(204)
-	}).Methods(http.MethodPost)
+	})).Methods(http.MethodPost)
 
 	r.HandleFunc("/v1/api/listevents", func(writer http.ResponseWriter, request *http.Request) {
 		content, err := ioutil.ReadAll(request.Body)
@@ -210,10 +388,227 @@ func (s *MServer) configureHTTP() error {
 		}
 	}).Methods(http.MethodGet)
 
-	s.HTTPServer().Handler = r
+	r.HandleFunc("/v1/templates", func(writer http.ResponseWriter, request *http.Request) {
+		body, err := api.ExecuteListTemplates(s.sess)
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		if body == nil {
+			writer.WriteHeader(204)
+		} else {
+			writer.WriteHeader(200)
+			writer.Write(body)
+		}
+	}).Methods(http.MethodGet)
+
+	r.HandleFunc("/v1/templates", s.signed(func(writer http.ResponseWriter, request *http.Request) {
+		content, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		err = api.ExecuteCreateTemplate(content, s.sess)
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		writer.WriteHeader(204)
+	})).Methods(http.MethodPost).Headers("Content-Type", "application/json")
+
+	r.HandleFunc("/v1/templates/{name}", func(writer http.ResponseWriter, request *http.Request) {
+		body, err := api.ExecuteGetTemplate(mux.Vars(request)["name"], s.sess)
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		if body == nil {
+			writer.WriteHeader(404)
+		} else {
+			writer.WriteHeader(200)
+			writer.Write(body)
+		}
+	}).Methods(http.MethodGet)
+
+	r.HandleFunc("/v1/templates/{name}", s.signed(func(writer http.ResponseWriter, request *http.Request) {
+		content, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		found, err := api.ExecuteUpdateTemplate(mux.Vars(request)["name"], content, s.sess)
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		if !found {
+			writer.WriteHeader(404)
+			return
+		}
+		writer.WriteHeader(204)
+	})).Methods(http.MethodPut).Headers("Content-Type", "application/json")
+
+	r.HandleFunc("/v1/templates/{name}", s.signed(func(writer http.ResponseWriter, request *http.Request) {
+		found, err := api.ExecuteDeleteTemplate(mux.Vars(request)["name"], s.sess)
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		if !found {
+			writer.WriteHeader(404)
+			return
+		}
+		writer.WriteHeader(204)
+	})).Methods(http.MethodDelete)
+
+	r.HandleFunc("/v1/scripts/reload", s.signed(func(writer http.ResponseWriter, request *http.Request) {
+		overrides, err := s.sess.ReloadScripts()
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		body, err := json.Marshal(overrides)
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(200)
+		writer.Write(body)
+	})).Methods(http.MethodPost)
+
+	r.HandleFunc("/v1/admin/reload", s.signed(func(writer http.ResponseWriter, request *http.Request) {
+		if s.reloader == nil {
+			writer.WriteHeader(404)
+			return
+		}
+		report, err := s.reloader()
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		body, err := json.Marshal(report)
+		if err != nil {
+			writer.WriteHeader(500)
+			writer.Write([]byte(err.Error()))
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(200)
+		writer.Write(body)
+	})).Methods(http.MethodPost)
+
+	r.HandleFunc("/metrics", func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writer.WriteHeader(200)
+		essentials.Metrics.WriteText(writer)
+	}).Methods(http.MethodGet)
+
+	s.HTTPServer().Handler = instrument(r)
 	return nil
 }
 
+// Health checks the processor loops and the scheduler, which only fail when
+// the process needs a restart. Ready also checks the database, the broker
+// connection and the AMQP consumers, without which matcha cannot serve.
+func (s *MServer) Health(ready bool) *essentials.HealthReport {
+	report := essentials.NewHealthReport()
+	processors, err := s.infiniteProcessor.Health()
+	report.Add("processors", processors, err)
+	scheduler, err := s.scheduler.Health()
+	report.Add("scheduler", scheduler, err)
+	if !ready {
+		return report
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	defer cancel()
+	report.Add("database", nil, s.sess.PingDatabase(ctx))
+	broker := s.sess.AMQP().Health()
+	report.Add("amqp", broker, essentials.AMQPHealthError(broker))
+
+	consumers := make(map[string]bool)
+	var stopped []string
+	collections.ForEach(s.once, func(m interface{}) {
+		if consumer, ok := m.(interface {
+			Running() bool
+		}); ok {
+			name := fmt.Sprintf("%T", m)
+			name = name[strings.LastIndex(name, ".")+1:]
+			consumers[name] = consumer.Running()
+			if !consumers[name] {
+				stopped = append(stopped, name)
+			}
+		}
+	})
+	err = nil
+	if len(stopped) > 0 {
+		sort.Strings(stopped)
+		err = fmt.Errorf("not consuming: %s", strings.Join(stopped, ", "))
+	}
+	report.Add("consumers", consumers, err)
+	return report
+}
+
+// healthTimeout bounds the database ping of the readiness check.
+const healthTimeout = 2 * time.Second
+
+// writeHealth writes report with 200 when it is up and 503 otherwise.
+func writeHealth(writer http.ResponseWriter, report *essentials.HealthReport) {
+	body, err := json.Marshal(report)
+	if err != nil {
+		writer.WriteHeader(500)
+		writer.Write([]byte(err.Error()))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	if report.Up() {
+		writer.WriteHeader(200)
+	} else {
+		writer.WriteHeader(503)
+	}
+	writer.Write(body)
+}
+
+// instrument records the duration of the requests to r per route template,
+// so `/v1/templates/{name}` is one route whatever the name.
+func instrument(r *mux.Router) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		start := time.Now()
+		route := "unmatched"
+		var match mux.RouteMatch
+		if r.Match(request, &match) && match.Route != nil {
+			if template, err := match.Route.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+		recorder := &statusRecorder{ResponseWriter: writer, status: 200}
+		r.ServeHTTP(recorder, request)
+		essentials.HTTPRequestDuration.Observe(time.Since(start).Seconds(), route, request.Method, strconv.Itoa(recorder.status))
+	})
+}
+
+// statusRecorder remembers the status code written to a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
 func (s *MServer) ParseHTTPFunc(httpHandler func(*essentials.MatchaContext, http.ResponseWriter, *http.Request) error) essentials.RoutedMiddleware {
 	return essentials.NewCommonHTTPMiddleware(s.sess, &httpHandler)
 }
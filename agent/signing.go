@@ -0,0 +1,207 @@
+package agent
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FeiniuBus/signer"
+)
+
+const (
+	signingDateHeader   = "X-FeiniuBus-Date"
+	signingDateFormat   = "20060102T150405Z"
+	signingCredential   = "Credential="
+	signingSignature    = "Signature="
+	defaultReplayWindow = 300
+)
+
+// SigningConfig configures the HMAC request signing of the endpoints that
+// publish or change messages, jobs, templates and the configuration. Requests
+// are signed with FeiniuBus/signer by a client of Clients, mapping client
+// names to shared secrets.
+type SigningConfig struct {
+	// Required rejects unsigned requests. It defaults to true as soon as
+	// Clients is not empty; set it to false to accept unsigned requests while
+	// clients migrate.
+	Required *bool             `json:"required"`
+	Clients  map[string]string `json:"clients"`
+	// ReplayWindow is how far the request time may be from the agent clock,
+	// in seconds (default 300). A signature is accepted once within it.
+	ReplayWindow int `json:"replay_window"`
+}
+
+// required reports whether unsigned requests are rejected.
+func (c *SigningConfig) required() bool {
+	if c.Required != nil {
+		return *c.Required
+	}
+	return len(c.Clients) > 0
+}
+
+func mergeSigningConfig(a, b *SigningConfig) *SigningConfig {
+	if a == nil {
+		a = &SigningConfig{}
+	}
+	result := *a
+	result.Clients = make(map[string]string)
+	for k, v := range a.Clients {
+		result.Clients[k] = v
+	}
+	if b.Required != nil {
+		result.Required = b.Required
+	}
+	for k, v := range b.Clients {
+		result.Clients[k] = v
+	}
+	if b.ReplayWindow != 0 {
+		result.ReplayWindow = b.ReplayWindow
+	}
+	return &result
+}
+
+// requestVerifier checks the signature, the time and the uniqueness of
+// signed requests and tells the client that signed them.
+type requestVerifier struct {
+	mu       sync.RWMutex
+	required bool
+	clients  map[string]string
+	window   time.Duration
+
+	seenMu    sync.Mutex
+	seen      map[string]time.Time
+	lastPrune time.Time
+}
+
+func newRequestVerifier(c *SigningConfig) *requestVerifier {
+	v := &requestVerifier{seen: make(map[string]time.Time)}
+	v.Configure(c)
+	return v
+}
+
+// Configure replaces the clients and settings, e.g. to rotate secrets.
+func (v *requestVerifier) Configure(c *SigningConfig) {
+	if c == nil {
+		c = &SigningConfig{}
+	}
+	clients := make(map[string]string, len(c.Clients))
+	for k, secret := range c.Clients {
+		clients[k] = secret
+	}
+	window := c.ReplayWindow
+	if window <= 0 {
+		window = defaultReplayWindow
+	}
+	v.mu.Lock()
+	v.required = c.required()
+	v.clients = clients
+	v.window = time.Duration(window) * time.Second
+	v.mu.Unlock()
+}
+
+func (v *requestVerifier) secret(client string) (string, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	secret, ok := v.clients[client]
+	if !ok || secret == "" {
+		return "", fmt.Errorf("unknown client %s", client)
+	}
+	return secret, nil
+}
+
+// Verify returns the client that signed request, or "" for an unsigned
+// request when signing is not required. The body is read and put back.
+func (v *requestVerifier) Verify(request *http.Request) (string, error) {
+	v.mu.RLock()
+	required, window := v.required, v.window
+	v.mu.RUnlock()
+
+	auth := request.Header.Get("Authorization")
+	if auth == "" {
+		if required {
+			return "", errors.New("request is not signed")
+		}
+		return "", nil
+	}
+	client, signature := parseAuthorization(auth)
+	if client == "" || signature == "" {
+		return "", errors.New("malformed Authorization header")
+	}
+
+	signTime, err := time.Parse(signingDateFormat, request.Header.Get(signingDateHeader))
+	if err != nil {
+		return "", fmt.Errorf("invalid %s header", signingDateHeader)
+	}
+	now := time.Now()
+	if signTime.Before(now.Add(-window)) || signTime.After(now.Add(window)) {
+		return "", fmt.Errorf("request time %s is outside the replay window", signTime.Format(time.RFC3339))
+	}
+
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		return "", err
+	}
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	u := *request.URL
+	u.Host = request.Host
+	valid := signer.NewHMACValidatorV1(v.secret).Verify(&signer.Request{
+		Body:   bytes.NewReader(body),
+		URL:    &u,
+		Header: request.Header,
+		Method: request.Method,
+	})
+	if !valid {
+		return "", errors.New("invalid request signature")
+	}
+
+	if !v.firstSeen(signature, now, window) {
+		return "", errors.New("request signature already used")
+	}
+	return client, nil
+}
+
+// firstSeen records signature and reports whether it was not seen within
+// the replay window.
+func (v *requestVerifier) firstSeen(signature string, now time.Time, window time.Duration) bool {
+	v.seenMu.Lock()
+	defer v.seenMu.Unlock()
+	if now.Sub(v.lastPrune) > window {
+		for k, t := range v.seen {
+			if now.Sub(t) > 2*window {
+				delete(v.seen, k)
+			}
+		}
+		v.lastPrune = now
+	}
+	if _, ok := v.seen[signature]; ok {
+		return false
+	}
+	v.seen[signature] = now
+	return true
+}
+
+// parseAuthorization reads the client and the signature of an
+// `FNBUS1-HMAC-SHA256 Credential=<client>/<date>/<scope>,SignedHeaders=...,Signature=<hex>`
+// header.
+func parseAuthorization(auth string) (string, string) {
+	var client, signature string
+	for _, part := range strings.Split(auth, ",") {
+		part = strings.TrimSpace(part)
+		if i := strings.Index(part, signingCredential); i >= 0 {
+			client = part[i+len(signingCredential):]
+			if j := strings.Index(client, "/"); j >= 0 {
+				client = client[:j]
+			}
+		}
+		if strings.HasPrefix(part, signingSignature) {
+			signature = part[len(signingSignature):]
+		}
+	}
+	return client, signature
+}
@@ -0,0 +1,188 @@
+package agent
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FeiniuBus/signer"
+)
+
+const signingTestURL = "http://matcha.local/v1/event/publish?env=dev"
+
+// signedRequest returns a POST of body to signingTestURL signed by client
+// with secret.
+func signedRequest(t *testing.T, client string, secret string, body string) *http.Request {
+	t.Helper()
+	u, err := url.Parse(signingTestURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	result := signer.NewHMACSignerV1(client, secret).Sign(&signer.Request{
+		Body:   strings.NewReader(body),
+		URL:    u,
+		Header: header,
+		Method: http.MethodPost,
+	}, 0)
+	request := httptest.NewRequest(http.MethodPost, signingTestURL, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	for k, v := range result.Header {
+		request.Header[k] = v
+	}
+	return request
+}
+
+func newTestVerifier() *requestVerifier {
+	return newRequestVerifier(&SigningConfig{Clients: map[string]string{"shop": "s3cret"}, ReplayWindow: 60})
+}
+
+func TestVerifyAcceptsValidSignature(t *testing.T) {
+	v := newTestVerifier()
+	request := signedRequest(t, "shop", "s3cret", `{"type":"order.created"}`)
+	client, err := v.Verify(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client != "shop" {
+		t.Errorf("client %q, want shop", client)
+	}
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != `{"type":"order.created"}` {
+		t.Errorf("body %q was not put back", body)
+	}
+}
+
+func TestVerifyRejectsInvalidRequests(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		request func() *http.Request
+		err     string
+	}{
+		{"unsigned", func() *http.Request {
+			return httptest.NewRequest(http.MethodPost, signingTestURL, strings.NewReader("{}"))
+		}, "not signed"},
+		{"tampered body", func() *http.Request {
+			request := signedRequest(t, "shop", "s3cret", `{"amount":1}`)
+			request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"amount":1000}`)))
+			return request
+		}, "invalid request signature"},
+		{"tampered query", func() *http.Request {
+			request := signedRequest(t, "shop", "s3cret", "{}")
+			request.URL.RawQuery = "env=pro"
+			return request
+		}, "invalid request signature"},
+		{"wrong secret", func() *http.Request {
+			return signedRequest(t, "shop", "guess", "{}")
+		}, "invalid request signature"},
+		{"unknown client", func() *http.Request {
+			return signedRequest(t, "ghost", "s3cret", "{}")
+		}, "invalid request signature"},
+		{"date too old", func() *http.Request {
+			request := signedRequest(t, "shop", "s3cret", "{}")
+			request.Header.Set(signingDateHeader, time.Now().Add(-2*time.Minute).UTC().Format(signingDateFormat))
+			return request
+		}, "replay window"},
+		{"date in the future", func() *http.Request {
+			request := signedRequest(t, "shop", "s3cret", "{}")
+			request.Header.Set(signingDateHeader, time.Now().Add(2*time.Minute).UTC().Format(signingDateFormat))
+			return request
+		}, "replay window"},
+		{"unparsable date", func() *http.Request {
+			request := signedRequest(t, "shop", "s3cret", "{}")
+			request.Header.Set(signingDateHeader, "yesterday")
+			return request
+		}, "invalid X-FeiniuBus-Date"},
+		{"bearer token", func() *http.Request {
+			request := signedRequest(t, "shop", "s3cret", "{}")
+			request.Header.Set("Authorization", "Bearer abc")
+			return request
+		}, "malformed Authorization"},
+		{"no signature", func() *http.Request {
+			request := signedRequest(t, "shop", "s3cret", "{}")
+			request.Header.Set("Authorization", "FNBUS1-HMAC-SHA256 Credential=shop/20260101/feiniubus_request,SignedHeaders=host")
+			return request
+		}, "malformed Authorization"},
+	} {
+		_, err := newTestVerifier().Verify(c.request())
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s: err %v, want %q", c.name, err, c.err)
+		}
+	}
+}
+
+func TestVerifyRejectsReplayedSignature(t *testing.T) {
+	v := newTestVerifier()
+	request := signedRequest(t, "shop", "s3cret", "{}")
+	replay := httptest.NewRequest(http.MethodPost, signingTestURL, strings.NewReader("{}"))
+	replay.Header = request.Header.Clone()
+
+	_, err := v.Verify(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = v.Verify(replay)
+	if err == nil || !strings.Contains(err.Error(), "already used") {
+		t.Errorf("replay: err %v", err)
+	}
+}
+
+func TestVerifyAcceptsUnsignedRequestsUnlessRequired(t *testing.T) {
+	required := false
+	v := newRequestVerifier(&SigningConfig{Required: &required, Clients: map[string]string{"shop": "s3cret"}})
+	client, err := v.Verify(httptest.NewRequest(http.MethodPost, signingTestURL, strings.NewReader("{}")))
+	if client != "" || err != nil {
+		t.Errorf("client %q, err %v", client, err)
+	}
+	_, err = newRequestVerifier(nil).Verify(httptest.NewRequest(http.MethodPost, signingTestURL, strings.NewReader("{}")))
+	if err != nil {
+		t.Errorf("without clients: %v", err)
+	}
+}
+
+func TestFirstSeen(t *testing.T) {
+	v := newTestVerifier()
+	window := time.Minute
+	now := time.Now()
+	if !v.firstSeen("a", now, window) {
+		t.Fatal("new signature seen")
+	}
+	if v.firstSeen("a", now.Add(window), window) {
+		t.Error("signature accepted twice within the window")
+	}
+	if !v.firstSeen("b", now.Add(window), window) {
+		t.Error("other signature rejected")
+	}
+	if !v.firstSeen("a", now.Add(3*window), window) {
+		t.Error("signature not forgotten long after the window")
+	}
+}
+
+func TestParseAuthorization(t *testing.T) {
+	for _, c := range []struct {
+		auth      string
+		client    string
+		signature string
+	}{
+		{"FNBUS1-HMAC-SHA256 Credential=shop/20260101/feiniubus_request,SignedHeaders=content-type;host,Signature=0a1b", "shop", "0a1b"},
+		{"FNBUS1-HMAC-SHA256 Credential=shop/20260101/feiniubus_request, SignedHeaders=host, Signature=0a1b", "shop", "0a1b"},
+		{"FNBUS1-HMAC-SHA256 Credential=shop", "shop", ""},
+		{"FNBUS1-HMAC-SHA256 SignedHeaders=host,Signature=0a1b", "", "0a1b"},
+		{"Bearer abc", "", ""},
+		{"", "", ""},
+	} {
+		client, signature := parseAuthorization(c.auth)
+		if client != c.client || signature != c.signature {
+			t.Errorf("%q: %q %q, want %q %q", c.auth, client, signature, c.client, c.signature)
+		}
+	}
+}
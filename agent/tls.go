@@ -0,0 +1,114 @@
+package agent
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+)
+
+// TLSVersions maps the `tls_min_version` values to TLS versions.
+var TLSVersions = map[string]uint16{
+	"tls10": tls.VersionTLS10,
+	"tls11": tls.VersionTLS11,
+	"tls12": tls.VersionTLS12,
+	"tls13": tls.VersionTLS13,
+}
+
+// tlsCertificates holds the server certificate and the client CA pool of the
+// HTTPS listener. They are read from their files again on reload, and new
+// handshakes use them without restarting the listener.
+type tlsCertificates struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+}
+
+func newTLSCertificates(c *Config) (*tlsCertificates, error) {
+	t := &tlsCertificates{
+		certFile: c.CertFile,
+		keyFile:  c.KeyFile,
+		caFile:   c.CAFile,
+	}
+	return t, t.Reload()
+}
+
+// Reload reads the certificate, its key and the client CA. The certificates
+// in use are kept when one of them fails to load.
+func (t *tlsCertificates) Reload() error {
+	cert, err := tls.LoadX509KeyPair(t.certFile, t.keyFile)
+	if err != nil {
+		return fmt.Errorf("Failed to load cert/key pair: %v", err)
+	}
+	var pool *x509.CertPool
+	if t.caFile != "" {
+		data, err := ioutil.ReadFile(t.caFile)
+		if err != nil {
+			return fmt.Errorf("Failed to read CA file: %v", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("Failed to parse any CA certificates from %s", t.caFile)
+		}
+	}
+	t.mu.Lock()
+	t.cert = &cert
+	t.clientCA = pool
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *tlsCertificates) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.cert, nil
+}
+
+func (t *tlsCertificates) getClientCA() *x509.CertPool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.clientCA
+}
+
+// ServerConfig returns the TLS configuration of the HTTPS listener. With a
+// `ca_file`, client certificates are verified against it, and required when
+// `verify_incoming` is set.
+func (t *tlsCertificates) ServerConfig(c *Config) (*tls.Config, error) {
+	minVersion := uint16(tls.VersionTLS12)
+	if c.TLSMinVersion != "" {
+		v, ok := TLSVersions[strings.ToLower(c.TLSMinVersion)]
+		if !ok {
+			return nil, fmt.Errorf("Invalid tls_min_version '%s', expected one of tls10, tls11, tls12, tls13", c.TLSMinVersion)
+		}
+		minVersion = v
+	}
+	if c.VerifyIncoming && t.caFile == "" {
+		return nil, fmt.Errorf("verify_incoming requires ca_file")
+	}
+
+	base := &tls.Config{
+		MinVersion:     minVersion,
+		GetCertificate: t.getCertificate,
+	}
+	if t.caFile == "" {
+		return base, nil
+	}
+	clientAuth := tls.VerifyClientCertIfGiven
+	if c.VerifyIncoming {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		config := base.Clone()
+		config.GetConfigForClient = nil
+		config.ClientAuth = clientAuth
+		config.ClientCAs = t.getClientCA()
+		return config, nil
+	}
+	return base, nil
+}
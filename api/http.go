@@ -17,6 +17,7 @@ func ExecuteListEvents(content []byte, sess *essentials.Session) ([]byte, error)
 	if err != nil {
 		return nil, err
 	}
+	defer conn.Close()
 	result, err := ListEventMessages(&parameter, conn, sess)
 	if err != nil {
 		return nil, err
@@ -41,6 +42,7 @@ func ExecuteListJobs(content []byte, sess *essentials.Session) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer conn.Close()
 	result, err := ListJobMessages(&parameter, conn, sess)
 	if err != nil {
 		return nil, err
@@ -60,18 +62,12 @@ func ExecuteGetMessageContent(messageID string, sess *essentials.Session) ([]byt
 	if err != nil {
 		return nil, err
 	}
-	row, err := conn.QueryScriptRow("FindOneMessage", messageID)
+	defer conn.Close()
+	message, err := essentials.FindOneMessage(messageID, false, conn)
 	if sql.ErrNoRows == err {
 		return nil, nil
 	} else if err != nil {
 		return nil, err
 	}
-	var message essentials.Message
-	err = row.Scan(&message.ID, &message.MessageType, &message.Content, &message.State,
-		&message.StateName, &message.Retry, &message.CreationTime, &message.CreationTimeString,
-		&message.Env)
-	if err != nil {
-		return nil, err
-	}
 	return []byte(message.Content), nil
 }
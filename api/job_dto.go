@@ -53,6 +53,7 @@ type JobParameters struct {
 	PublishTimeStart *int64  `json:"start"`
 	PublishTimeEnd   *int64  `json:"end"`
 	MessageState     *string `json:"state"`
+	Env              *string `json:"env"`
 	Pager            bool    `json:"pager"`
 	Skip             int32   `json:"skip"`
 	Take             int32   `json:"take"`
@@ -104,6 +105,19 @@ func ListJobMessages(p *JobParameters, executor essentials.DbExecutor, sess *ess
 		whereClauses = append(whereClauses, fmt.Sprintf("msg.\"State\"=$%d", index))
 		parameters = append(parameters, (state))
 	}
+	var env string
+	if p.Env != nil {
+		env = *p.Env
+	}
+	env, err = sess.ResolveEnv(env)
+	if err != nil {
+		return nil, err
+	}
+	if env != "" {
+		index++
+		whereClauses = append(whereClauses, fmt.Sprintf("msg.\"Env\"=$%d", index))
+		parameters = append(parameters, env)
+	}
 	if len(whereClauses) > 1 {
 		sql += "AND" + strings.Join(whereClauses, " AND ")
 	} else if len(whereClauses) == 1 {
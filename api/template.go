@@ -0,0 +1,224 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/standardcore/Matcha/essentials"
+)
+
+// TemplateDto is a subscription template and the subscribers it stands for,
+// in the format of the `subs` of a publish payload.
+type TemplateDto struct {
+	Name         string                            `json:"name"`
+	Description  string                            `json:"description"`
+	CreationTime string                            `json:"creation_time,omitempty"`
+	Subs         []*essentials.SubscriptionPayload `json:"subs"`
+}
+
+func makeTemplateDto(template *essentials.SubTemplate, executor essentials.DbExecutor) (*TemplateDto, error) {
+	details, err := template.Details(executor)
+	if err != nil {
+		return nil, err
+	}
+	result := &TemplateDto{
+		Name:         template.Name,
+		Description:  template.Description,
+		CreationTime: template.CreationTimeString,
+		Subs:         make([]*essentials.SubscriptionPayload, 0, len(details)),
+	}
+	for _, item := range details {
+		result.Subs = append(result.Subs, &essentials.SubscriptionPayload{
+			Tag:      item.ReceiverTag,
+			Exchange: item.Exchange,
+			RouteKey: item.RouteKey,
+		})
+	}
+	return result, nil
+}
+
+func templateDetails(subs []*essentials.SubscriptionPayload) ([]*essentials.SubTemplateDetails, error) {
+	details := make([]*essentials.SubTemplateDetails, 0, len(subs))
+	seen := make(map[string]bool, len(subs))
+	for _, sub := range subs {
+		if sub.Tag == "" || sub.Exchange == "" {
+			return nil, errors.New("fields `tag` and `exchange` of subs could not be null or empty")
+		}
+		if seen[sub.Tag] {
+			return nil, fmt.Errorf("tag %s appears more than once in subs", sub.Tag)
+		}
+		seen[sub.Tag] = true
+		details = append(details, &essentials.SubTemplateDetails{
+			ReceiverTag: sub.Tag,
+			Exchange:    sub.Exchange,
+			RouteKey:    sub.RouteKey,
+		})
+	}
+	return details, nil
+}
+
+func ExecuteListTemplates(sess *essentials.Session) ([]byte, error) {
+	conn, err := sess.CreateConnectionFactory().Database()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	templates, err := essentials.ListTemplates(conn)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return nil, nil
+	}
+	result := make([]*TemplateDto, 0, len(templates))
+	for _, template := range templates {
+		dto, err := makeTemplateDto(template, conn)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, dto)
+	}
+	return json.Marshal(result)
+}
+
+// ExecuteGetTemplate returns the template named name, or nil when there is
+// none.
+func ExecuteGetTemplate(name string, sess *essentials.Session) ([]byte, error) {
+	conn, err := sess.CreateConnectionFactory().Database()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	template, err := essentials.FindOneTemplate("UNKNOWN", name, conn)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, nil
+	}
+	dto, err := makeTemplateDto(template, conn)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(dto)
+}
+
+// ExecuteCreateTemplate creates the template of content, whose name must not
+// be taken.
+func ExecuteCreateTemplate(content []byte, sess *essentials.Session) error {
+	var payload TemplateDto
+	err := json.Unmarshal(content, &payload)
+	if err != nil {
+		return err
+	}
+	if payload.Name == "" {
+		return fmt.Errorf("field `%s` could not be null or empty", "name")
+	}
+	details, err := templateDetails(payload.Subs)
+	if err != nil {
+		return err
+	}
+
+	conn, err := sess.CreateConnectionFactory().Database()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	transact, err := conn.BeginTx(sql.LevelReadCommitted)
+	if err != nil {
+		return err
+	}
+	defer transact.Rollback()
+	template, err := essentials.FindOneTemplate("UNKNOWN", payload.Name, transact)
+	if err != nil {
+		return err
+	}
+	if template != nil {
+		return fmt.Errorf("template %s already exists", payload.Name)
+	}
+	template = &essentials.SubTemplate{
+		Name:        payload.Name,
+		Description: payload.Description,
+	}
+	_, err = template.Append(transact)
+	if err != nil {
+		return err
+	}
+	err = template.ReplaceDetails(details, transact)
+	if err != nil {
+		return err
+	}
+	return transact.Commit()
+}
+
+// ExecuteUpdateTemplate replaces the description and the subscribers of the
+// template named name. It reports false when there is no such template.
+func ExecuteUpdateTemplate(name string, content []byte, sess *essentials.Session) (bool, error) {
+	var payload TemplateDto
+	err := json.Unmarshal(content, &payload)
+	if err != nil {
+		return false, err
+	}
+	details, err := templateDetails(payload.Subs)
+	if err != nil {
+		return false, err
+	}
+
+	conn, err := sess.CreateConnectionFactory().Database()
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	transact, err := conn.BeginTx(sql.LevelReadCommitted)
+	if err != nil {
+		return false, err
+	}
+	defer transact.Rollback()
+	template, err := essentials.FindOneTemplate("UNKNOWN", name, transact)
+	if err != nil {
+		return false, err
+	}
+	if template == nil {
+		return false, nil
+	}
+	template.Description = payload.Description
+	err = template.Update(transact)
+	if err != nil {
+		return false, err
+	}
+	err = template.ReplaceDetails(details, transact)
+	if err != nil {
+		return false, err
+	}
+	return true, transact.Commit()
+}
+
+// ExecuteDeleteTemplate deletes the template named name. It reports false
+// when there is no such template. Messages already published keep their
+// subscriptions.
+func ExecuteDeleteTemplate(name string, sess *essentials.Session) (bool, error) {
+	conn, err := sess.CreateConnectionFactory().Database()
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	transact, err := conn.BeginTx(sql.LevelReadCommitted)
+	if err != nil {
+		return false, err
+	}
+	defer transact.Rollback()
+	template, err := essentials.FindOneTemplate("UNKNOWN", name, transact)
+	if err != nil {
+		return false, err
+	}
+	if template == nil {
+		return false, nil
+	}
+	err = template.Delete(transact)
+	if err != nil {
+		return false, err
+	}
+	return true, transact.Commit()
+}
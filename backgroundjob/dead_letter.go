@@ -0,0 +1,217 @@
+package backgroundjob
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/standardcore/Matcha/essentials"
+	"github.com/streadway/amqp"
+)
+
+// DeadLetterMessage is published to the dead-letter exchange once a job has
+// exhausted its retries. It carries the message as it would have been
+// delivered plus the flow history of every subscription it failed on.
+type DeadLetterMessage struct {
+	Message       *essentials.DeliveryMessage `json:"message"`
+	Subscriptions []*DeadLetterSubscription   `json:"subs"`
+	Retry         int32                       `json:"retry"`
+	Reason        string                      `json:"reason"`
+	DeadTime      int64                       `json:"dead_time"`
+}
+
+type DeadLetterSubscription struct {
+	Tag      string            `json:"tag"`
+	Exchange string            `json:"exchange"`
+	RouteKey string            `json:"key"`
+	Flows    []*DeadLetterFlow `json:"flows"`
+}
+
+type DeadLetterFlow struct {
+	StateName    string `json:"state"`
+	Remark       string `json:"remark"`
+	CreationTime string `json:"creation_time"`
+}
+
+const defaultDeadLetterRouteKey = "deadletter@matcha.backgroundjob"
+
+// publishDeadLetter sends msg to `deadletter_exchange` with the
+// `deadletter_routekey` parameter (default deadletter@matcha.backgroundjob).
+// Nothing is published while no dead-letter exchange is configured.
+func publishDeadLetter(sess *essentials.Session, msg *essentials.Message, subs []*essentials.Subscription, exts map[string]string, reason string, executor essentials.DbExecutor) error {
+	exchange := sess.LoadOrEmpty("deadletter_exchange")
+	if exchange == "" {
+		sess.Logger().Debugln(fmt.Sprintf("no dead-letter exchange configured, message %s dropped", msg.ID))
+		return nil
+	}
+	routeKey := sess.LoadOrEmpty("deadletter_routekey")
+	if routeKey == "" {
+		routeKey = defaultDeadLetterRouteKey
+	}
+
+	span := sess.StartSpan("publish deadletter", msg.Trace())
+	span.SetAttribute("message_id", msg.ID).SetAttribute("exchange", exchange).SetAttribute("route_key", routeKey)
+	err := publishDeadLetterMessage(sess, msg, subs, exts, reason, exchange, routeKey, span.Context(), executor)
+	span.Finish(err)
+	return err
+}
+
+func publishDeadLetterMessage(sess *essentials.Session, msg *essentials.Message, subs []*essentials.Subscription, exts map[string]string, reason string, exchange string, routeKey string, trace essentials.TraceContext, executor essentials.DbExecutor) error {
+	headers := make(amqp.Table)
+	deliveryMsg := newDeliveryMessage(msg, exts)
+	trace.Inject(deliveryMsg.Extensions, headers)
+	deadLetter := &DeadLetterMessage{
+		Message:       deliveryMsg,
+		Subscriptions: make([]*DeadLetterSubscription, 0, len(subs)),
+		Retry:         msg.Retry,
+		Reason:        reason,
+		DeadTime:      time.Now().Unix(),
+	}
+	for _, sub := range subs {
+		flows, err := sub.FetchFlows(executor)
+		if err != nil {
+			return err
+		}
+		item := &DeadLetterSubscription{
+			Tag:      sub.ReceiverTag,
+			Exchange: sub.Exchange,
+			RouteKey: sub.RouteKey,
+			Flows:    make([]*DeadLetterFlow, 0, len(flows)),
+		}
+		for _, flow := range flows {
+			item.Flows = append(item.Flows, &DeadLetterFlow{
+				StateName:    flow.StateName,
+				Remark:       flow.Remark,
+				CreationTime: flow.CreationTimeString,
+			})
+		}
+		deadLetter.Subscriptions = append(deadLetter.Subscriptions, item)
+	}
+
+	body, err := json.Marshal(deadLetter)
+	if err != nil {
+		return err
+	}
+
+	channel, err := sess.AMQP().AcquireChannel()
+	if err != nil {
+		return err
+	}
+	defer channel.Close()
+
+	essentials.LogWith(sess.Logger(), essentials.Fields{"message_id": msg.ID}).Infoln(fmt.Sprintf("DeadLetter : %s ; Exchange : %s ; Key : %s ", msg.ID, exchange, routeKey))
+	return channel.Publish(exchange, routeKey, amqp.Publishing{
+		Headers:      headers,
+		DeliveryMode: amqp.Persistent,
+		Body:         body,
+	})
+}
+
+// ExecuteReplayDeadLetter puts a dead-lettered job back into the normal flow:
+// its retry counter is cleared, the failed subscriptions are scheduled again
+// and the job fires after the requested delay. Replaying a cron job fires one
+// extra occurrence. The env and client checks are those of ExecuteCancelJob.
+func ExecuteReplayDeadLetter(content []byte, request *http.Request, sess *essentials.Session) error {
+	var payload ReplayDeadLetterPayload
+	err := json.Unmarshal(content, &payload)
+	if err != nil {
+		return err
+	}
+	if payload.MessageID == "" {
+		return fmt.Errorf("field `%s` could not be null or empty", "message_id")
+	}
+	env, err := sess.RequireEnv(essentials.RequestEnv(request, payload.Env))
+	if err != nil {
+		return err
+	}
+	next := time.Now()
+	if payload.Delay != nil && *payload.Delay > 0 {
+		next = next.Add(time.Duration(*payload.Delay) * time.Second)
+	}
+	remark := "replayed from dead letter"
+	if payload.Remark != "" {
+		remark = fmt.Sprintf("%s : %s", remark, payload.Remark)
+	}
+
+	dbConn, err := sess.CreateConnectionFactory().Database()
+	if err != nil {
+		return err
+	}
+	defer dbConn.Close()
+	transact, err := dbConn.BeginTx(sql.LevelReadCommitted)
+	if err != nil {
+		return err
+	}
+	defer transact.Rollback()
+
+	job, err := FindOneLockedJob(payload.MessageID, transact)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("job %s not found", payload.MessageID)
+	} else if err != nil {
+		return err
+	}
+	msg, err := essentials.FindOneMessage(payload.MessageID, false, transact)
+	if err != nil {
+		return err
+	}
+	err = checkJobOwner(msg, env, essentials.RequestClient(request))
+	if err != nil {
+		return err
+	}
+
+	if job.Kind == CronJob {
+		if msg.State != essentials.MessageProcessing {
+			return fmt.Errorf("job %s is %s and could not be replayed", payload.MessageID, msg.StateName)
+		}
+		err = job.Reschedule(next, transact)
+		if err != nil {
+			return err
+		}
+		err = msg.AppendLog(remark, transact)
+		if err != nil {
+			return err
+		}
+		return transact.Commit()
+	}
+
+	if msg.State != essentials.MessageFailed || job.NextFireTime != 0 {
+		return fmt.Errorf("job %s is %s and was not dead-lettered", payload.MessageID, msg.StateName)
+	}
+	subs, err := msg.FetchSubscriptions(transact)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		if sub.StateName != "Failed" {
+			continue
+		}
+		err = sub.Reset("Scheduled", transact)
+		if err != nil {
+			return err
+		}
+		flow := &essentials.Flow{
+			SubscriptionID: sub.ID,
+			StateName:      "Scheduled",
+			Remark:         remark,
+		}
+		_, err = flow.Append(transact)
+		if err != nil {
+			return err
+		}
+	}
+	err = msg.ResetRetry(transact)
+	if err != nil {
+		return err
+	}
+	err = msg.ChangeStateWithRemark(essentials.MessageProcessing, remark, transact)
+	if err != nil {
+		return err
+	}
+	err = job.Reschedule(next, transact)
+	if err != nil {
+		return err
+	}
+	return transact.Commit()
+}
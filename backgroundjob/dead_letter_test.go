@@ -0,0 +1,55 @@
+//go:build cgo
+// +build cgo
+
+package backgroundjob
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/standardcore/Matcha/essentials"
+)
+
+func TestReplayDeadLetterChecksEnvAndPublisher(t *testing.T) {
+	sess := newSQLiteSession(t)
+	tx := beginTx(t, sess)
+	msg := appendProcessingMessage(t, "dev", tx)
+	job := &Job{MessageID: msg.ID, Kind: DelayJob, KindName: DelayJob.String()}
+	_, err := job.Append(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = msg.ChangeState(essentials.MessageFailed, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{"message_id":"` + msg.ID + `"}`
+	for _, c := range []struct {
+		env    string
+		client string
+		ok     bool
+	}{
+		{"pro", "", false},
+		{"", "", false},
+		{"dev", "billing", false},
+		{"dev", "shop", true},
+	} {
+		request := httptest.NewRequest("POST", "/v1/job/replay", strings.NewReader(body))
+		if c.env != "" {
+			request.Header.Set(essentials.EnvHeader, c.env)
+		}
+		if c.client != "" {
+			request = essentials.WithRequestClient(request, c.client)
+		}
+		err = ExecuteReplayDeadLetter([]byte(body), request, sess)
+		if (err == nil) != c.ok {
+			t.Errorf("env %q client %q: err %v", c.env, c.client, err)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package backgroundjob
 import (
 	"database/sql"
 	"encoding/json"
-	"fmt"
 	"strconv"
 
 	"github.com/standardcore/Matcha/essentials"
@@ -27,14 +26,7 @@ func (m *FailSafeMiddleware) OnConsume(ctx *essentials.MatchaContext, channel *a
 	}
 	ctx.GetSession().TryExchangeDeclare("backgroundjob_exchange")
 	ctx.GetSession().TryQueueDeclare("backgroundjob_failsafe")
-	d, err := channel.Consume(resolve, "matcha backgroundjob failsafe", false, false, false, false, make(amqp.Table))
-	if err != nil {
-		return err
-	}
-
-	go m.ListenDelivery(d)
-
-	return nil
+	return m.Consume(channel, resolve, "matcha backgroundjob failsafe")
 }
 
 func (m *FailSafeMiddleware) OnDelivery(ctx *essentials.MatchaContext, channel *amqp.Channel, args *essentials.ConsumerDeliverEventArgs) error {
@@ -44,16 +36,33 @@ func (m *FailSafeMiddleware) OnDelivery(ctx *essentials.MatchaContext, channel *
 		_ = channel.Nack(args.DeliveryTag, false, false)
 		return nil
 	}
-	delay, ok := payload.Extensions["delay"]
-	if ok == false {
+	payload.Env, err = ctx.GetSession().RequireEnv(payload.Env)
+	if err != nil {
 		_ = channel.Nack(args.DeliveryTag, false, false)
 		return nil
 	}
-	delaySeconds, err := strconv.ParseInt(delay, 10, 32)
+	_, err = ParseRetryPolicy(payload.Extensions)
 	if err != nil {
 		_ = channel.Nack(args.DeliveryTag, false, false)
 		return nil
 	}
+	recurring, err := IsRecurring(payload.Extensions)
+	if err != nil {
+		_ = channel.Nack(args.DeliveryTag, false, false)
+		return nil
+	}
+	if !recurring {
+		delay, ok := payload.Extensions["delay"]
+		if ok == false {
+			_ = channel.Nack(args.DeliveryTag, false, false)
+			return nil
+		}
+		_, err = strconv.ParseInt(delay, 10, 32)
+		if err != nil {
+			_ = channel.Nack(args.DeliveryTag, false, false)
+			return nil
+		}
+	}
 	dbConn, err := ctx.GetSession().CreateConnectionFactory().Database()
 	if err != nil {
 		_ = channel.Nack(args.DeliveryTag, false, true)
@@ -65,29 +74,12 @@ func (m *FailSafeMiddleware) OnDelivery(ctx *essentials.MatchaContext, channel *
 		_ = channel.Nack(args.DeliveryTag, false, true)
 		return err
 	}
+	defer transact.Rollback()
 
-	msg, err := essentials.AppendMessage(&payload, HandlePayloadExtension, transact)
-	if err != nil {
-		_ = channel.Nack(args.DeliveryTag, false, true)
-		return err
-	}
-	atJob := ctx.GetSession().CreateAtJobFactory().New(msg.ID)
-	spec := fmt.Sprintf("now + %d seconds", delaySeconds)
-
-	if delaySeconds > 0 {
-		err = msg.ChangeState(essentials.MessageProcessing, transact)
-		if err != nil {
-			_ = channel.Nack(args.DeliveryTag, false, true)
-			return nil
-		}
-		err = GetScheduler().AddJob(spec, atJob)
-		if err != nil {
-			_ = channel.Nack(args.DeliveryTag, false, false)
-			return nil
-		}
-	}
-
-	err = transact.Commit()
+	span := ctx.GetSession().StartPayloadSpan("persist job", &payload)
+	msgid, err := addJob(&payload, transact)
+	span.SetAttribute("message_id", msgid)
+	span.Finish(err)
 	if err != nil {
 		_ = channel.Nack(args.DeliveryTag, false, true)
 		return err
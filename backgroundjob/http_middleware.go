@@ -5,11 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/standardcore/Matcha/essentials"
-	"github.com/streadway/amqp"
 )
 
 func ExecuteAddJob(content []byte, request *http.Request, sess *essentials.Session) (string, error) {
@@ -37,15 +35,26 @@ func ExecuteAddJob(content []byte, request *http.Request, sess *essentials.Sessi
 	// 	err = fmt.Errorf("HTTP header `%s` should be one of `%s`, `%s`, `%s`", "X-matcha-Env", "dev", "staging", "pro")
 	// 	return "", err
 	// }
+	if client := essentials.RequestClient(request); client != "" {
+		payload.SetPublisher(client)
+	}
+	payload.Env, err = sess.RequireEnv(essentials.RequestEnv(request, payload.Env))
+	if err != nil {
+		return "", err
+	}
 
-	msgid, err := addJobWriteDb(sess, &payload)
+	span := sess.StartRequestSpan("ingest job", request, &payload)
+	msgid, err := AddJob(sess, &payload)
+	span.SetAttribute("message_id", msgid)
+	span.Finish(err)
 	if err != nil {
 		return "", err
 	}
 	return msgid, nil
 }
 
-func addJobWriteDb(sess *essentials.Session, payload *essentials.Payload) (string, error) {
+// AddJob stores payload as a background job and hands it to the Scheduler.
+func AddJob(sess *essentials.Session, payload *essentials.Payload) (string, error) {
 	dbConn, err := sess.CreateConnectionFactory().Database()
 	if err != nil {
 		return "", err
@@ -55,92 +64,188 @@ func addJobWriteDb(sess *essentials.Session, payload *essentials.Payload) (strin
 	if err != nil {
 		return "", err
 	}
+	defer transact.Rollback()
+	span := sess.StartPayloadSpan("persist job", payload)
+	msgid, err := addJob(payload, transact)
+	span.SetAttribute("message_id", msgid)
+	span.Finish(err)
+	return msgid, err
+}
+
+func addJob(payload *essentials.Payload, transact *essentials.DbTransaction) (string, error) {
 	msg, err := essentials.AppendMessage(payload, HandlePayloadExtension, transact)
 	if err != nil {
 		return "", err
 	}
-	err = transact.Commit()
+	err = msg.ChangeState(essentials.MessageProcessing, transact)
 	if err != nil {
 		return "", err
 	}
-	delay, _ := payload.Extensions["delay"]
-	delaySeconds, err := strconv.ParseInt(delay, 10, 32)
+	err = transact.Commit()
 	if err != nil {
 		return "", err
 	}
-	atJob := sess.CreateAtJobFactory().New(msg.ID)
-	spec := fmt.Sprintf("now + %d seconds", delaySeconds)
-	if delaySeconds > 0 {
-		err = GetScheduler().AddJob(spec, atJob)
-		if err != nil {
-			return "", err
-		}
-		err = msg.ChangeState(essentials.MessageProcessing, dbConn)
-		if err != nil {
-			return "", err
-		}
-	}
 	return msg.ID, nil
 }
 
-func addJobTryPublish(sess *essentials.Session, body []byte) {
-
-	publish := &amqp.Publishing{
-		Body: body,
+// ExecuteCancelJob cancels a pending job and those of its subscriptions that
+// were not published yet. The `X-matcha-Env` header, or the `env` of the body,
+// must be the env of the job, and a signed client can only cancel the jobs it
+// published.
+func ExecuteCancelJob(content []byte, request *http.Request, sess *essentials.Session) error {
+	var payload CancelJobPayload
+	err := json.Unmarshal(content, &payload)
+	if err != nil {
+		return err
+	}
+	if payload.MessageID == "" {
+		return fmt.Errorf("field `%s` could not be null or empty", "message_id")
+	}
+	env, err := sess.RequireEnv(essentials.RequestEnv(request, payload.Env))
+	if err != nil {
+		return err
 	}
 
-	exchange, ok := sess.Load("backgroundjob_exchange")
-	if ok == false {
-		return
+	dbConn, err := sess.CreateConnectionFactory().Database()
+	if err != nil {
+		return err
 	}
-	failsafe, ok := sess.Load("backgroundjob_failsafe")
-	if ok == false {
-		return
+	defer dbConn.Close()
+	transact, err := dbConn.BeginTx(sql.LevelReadCommitted)
+	if err != nil {
+		return err
 	}
+	defer transact.Rollback()
 
-	forFunc := func() error {
-		conn, err := sess.CreateConnectionFactory().RabbitMQ()
-		if err != nil {
-			return err
-		}
-		defer conn.Close()
-		channel, err := conn.Channel()
-		if err != nil {
-			return err
+	job, msg, err := findPendingJob(payload.MessageID, env, essentials.RequestClient(request), transact)
+	if err != nil {
+		return err
+	}
+
+	subs, err := msg.FetchSubscriptions(transact)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		// Subscriptions a partly failed firing already published keep
+		// their state, only the deliveries still due are cancelled.
+		if sub.StateName != "Scheduled" {
+			continue
 		}
-		return channel.Close()
-		err = sess.ExchangeDeclare("backgroundjob_exchange")
+		err = sub.ChangeState("Cancelled", transact)
 		if err != nil {
 			return err
 		}
-		_, err = sess.QueueDeclare("backgroundjob_failsafe")
-		//_, err = channel.QueueDeclare(failsafe.(string), true, false, false, false, make(amqp.Table))
-		if err != nil {
-			return err
+		flow := &essentials.Flow{
+			SubscriptionID: sub.ID,
+			StateName:      "Cancelled",
+			Remark:         payload.Remark,
 		}
-		//err = channel.QueueBind(failsafe.(string), failsafe.(string), exchange.(string), false, make(amqp.Table))
-		//if err != nil {
-		//	goto WaitNextRound
-		//}
-		err = channel.Publish(exchange, failsafe, false, false, *publish)
+		_, err = flow.Append(transact)
 		if err != nil {
 			return err
 		}
-		return nil
 	}
-	sess.Logger().Infoln("backgroundjob.serverhandler : failsafe : try publishing")
-	for {
-		err := forFunc()
-		if err != nil {
-			sess.Logger().Errorln(essentials.WrapError("backgroundjob.serverhandler : failsafe : ", err))
-			goto WaitNextRound
-		}
-		goto ForEnd
 
-	WaitNextRound:
-		sess.Logger().Infoln("backgroundjob.serverhandler : failsafe : waiting")
-		time.Sleep(time.Second * 30)
+	err = job.Reschedule(time.Time{}, transact)
+	if err != nil {
+		return err
+	}
+	err = msg.ChangeStateWithRemark(essentials.MessageCancelled, payload.Remark, transact)
+	if err != nil {
+		return err
+	}
+	return transact.Commit()
+}
+
+// ExecuteRescheduleJob changes the fire time of a pending job, with the same
+// env and client checks as ExecuteCancelJob.
+func ExecuteRescheduleJob(content []byte, request *http.Request, sess *essentials.Session) error {
+	var payload RescheduleJobPayload
+	err := json.Unmarshal(content, &payload)
+	if err != nil {
+		return err
+	}
+	if payload.MessageID == "" {
+		return fmt.Errorf("field `%s` could not be null or empty", "message_id")
+	}
+	env, err := sess.RequireEnv(essentials.RequestEnv(request, payload.Env))
+	if err != nil {
+		return err
+	}
+
+	var next time.Time
+	if payload.Time != nil {
+		next = time.Unix(*payload.Time, 0)
+	} else if payload.Delay != nil && *payload.Delay >= 0 {
+		next = time.Now().Add(time.Duration(*payload.Delay) * time.Second)
+	} else {
+		return fmt.Errorf("one of the fields `%s`, `%s` was required", "delay", "time")
+	}
+
+	dbConn, err := sess.CreateConnectionFactory().Database()
+	if err != nil {
+		return err
+	}
+	defer dbConn.Close()
+	transact, err := dbConn.BeginTx(sql.LevelReadCommitted)
+	if err != nil {
+		return err
+	}
+	defer transact.Rollback()
+
+	job, msg, err := findPendingJob(payload.MessageID, env, essentials.RequestClient(request), transact)
+	if err != nil {
+		return err
+	}
+
+	remark := fmt.Sprintf("rescheduled from %s to %s", essentials.FormatTime(time.Unix(job.NextFireTime, 0)), essentials.FormatTime(next))
+	if payload.Remark != "" {
+		remark = fmt.Sprintf("%s : %s", remark, payload.Remark)
+	}
+	err = job.Reschedule(next, transact)
+	if err != nil {
+		return err
+	}
+	err = msg.AppendLog(remark, transact)
+	if err != nil {
+		return err
+	}
+	return transact.Commit()
+}
+
+// findPendingJob locks the job of a message of env that has not fired yet, or
+// is recurring, and has not been cancelled or given up. A non-empty client
+// must be the publisher of the message.
+func findPendingJob(messageID string, env string, client string, executor essentials.DbExecutor) (*Job, *essentials.Message, error) {
+	job, err := FindOneLockedJob(messageID, executor)
+	if err == sql.ErrNoRows {
+		return nil, nil, fmt.Errorf("job %s not found", messageID)
+	} else if err != nil {
+		return nil, nil, err
+	}
+	msg, err := essentials.FindOneMessage(messageID, false, executor)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = checkJobOwner(msg, env, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.NextFireTime == 0 || (msg.State != essentials.MessageScheduled && msg.State != essentials.MessageProcessing) {
+		return nil, nil, fmt.Errorf("job %s is %s and no longer pending", messageID, msg.StateName)
+	}
+	return job, msg, nil
+}
+
+// checkJobOwner rejects a job message of another env than env, or, for a
+// non-empty client, of another publisher.
+func checkJobOwner(msg *essentials.Message, env string, client string) error {
+	if msg.Env != "" && msg.Env != env {
+		return fmt.Errorf("job %s belongs to env `%s`, not `%s`", msg.ID, msg.Env, env)
+	}
+	if client != "" && msg.Publisher != client {
+		return fmt.Errorf("client %s can not change job %s of publisher %s", client, msg.ID, msg.Publisher)
 	}
-ForEnd:
-	sess.Logger().Infoln("backgroundjob.serverhandler : failsafe : succeeded")
+	return nil
 }
@@ -0,0 +1,84 @@
+//go:build cgo
+// +build cgo
+
+package backgroundjob
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standardcore/Matcha/essentials"
+)
+
+func TestCancelJobKeepsPublishedSubscriptions(t *testing.T) {
+	sess := newSQLiteSession(t)
+	tx := beginTx(t, sess)
+	msg, err := essentials.AppendMessage(&essentials.Payload{
+		Env:         "dev",
+		MessageType: "invoice.send",
+		Content:     "{}",
+		Subscriptions: []*essentials.SubscriptionPayload{
+			{Tag: "billing", Exchange: "matcha", RouteKey: "billing"},
+			{Tag: "mail", Exchange: "matcha", RouteKey: "mail"},
+		},
+	}, nil, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = msg.Published(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := &Job{MessageID: msg.ID, Kind: DelayJob, KindName: DelayJob.String(), NextFireTime: time.Now().Unix() + 60}
+	_, err = job.Append(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The last firing only reached billing.
+	subs, err := msg.FetchSubscriptions(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	published := subs[0]
+	err = published.ChangeState("Published", tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{"message_id":"` + msg.ID + `","remark":"order refunded"}`
+	request := httptest.NewRequest("POST", "/v1/job/cancel", strings.NewReader(body))
+	request.Header.Set(essentials.EnvHeader, "dev")
+	err = ExecuteCancelJob([]byte(body), request, sess)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx = beginTx(t, sess)
+	defer tx.Rollback()
+	subs, err = msg.FetchSubscriptions(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sub := range subs {
+		want := "Cancelled"
+		if sub.ID == published.ID {
+			want = "Published"
+		}
+		if sub.StateName != want {
+			t.Errorf("subscription %s is %s, want %s", sub.ReceiverTag, sub.StateName, want)
+		}
+	}
+	stored, err := essentials.FindOneMessage(msg.ID, false, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.StateName != essentials.MessageCancelled.String() {
+		t.Errorf("message is %s, want Cancelled", stored.StateName)
+	}
+}
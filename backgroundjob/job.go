@@ -1,9 +1,12 @@
 package backgroundjob
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/standardcore/Matcha/essentials"
 )
@@ -15,6 +18,11 @@ type Job struct {
 	Kind         JobKind
 	KindName     string
 	DelaySeconds int32
+	NextFireTime int64
+	RetryPolicy  RetryPolicy
+	// OccurrenceTime is the scheduled time of the cron occurrence being
+	// retried, 0 while no occurrence is.
+	OccurrenceTime int64
 }
 
 func (job *Job) Append(executor essentials.DbExecutor) (string, error) {
@@ -24,18 +32,107 @@ func (job *Job) Append(executor essentials.DbExecutor) (string, error) {
 	if job.ID == "" {
 		job.ID = essentials.NewOrderedUUID()
 	}
-	_, err := executor.ExecScript("InsertBackgroundJob", job.ID, job.MessageID, job.Expression, job.Kind, job.KindName, job.DelaySeconds)
+	retryPolicy, err := job.RetryPolicy.marshal()
+	if err != nil {
+		return "", err
+	}
+	_, err = executor.ExecScript("InsertBackgroundJob", job.ID, job.MessageID, job.Expression, job.Kind, job.KindName, job.DelaySeconds, job.NextFireTime, retryPolicy)
 	if err != nil {
 		return "", err
 	}
 	return job.ID, nil
 }
 
+// Reschedule stores the time the scheduler fires the job next; a zero time
+// leaves the job unscheduled.
+func (job *Job) Reschedule(next time.Time, executor essentials.DbExecutor) error {
+	var fireTime interface{}
+	job.NextFireTime = 0
+	if !next.IsZero() {
+		job.NextFireTime = next.Unix()
+		fireTime = job.NextFireTime
+	}
+	_, err := executor.ExecScript("ChangeJobFireTime", fireTime, job.ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ChangeOccurrence stores the scheduled time of the cron occurrence being
+// retried; a zero time clears it.
+func (job *Job) ChangeOccurrence(occurrence time.Time, executor essentials.DbExecutor) error {
+	var occurrenceTime interface{}
+	job.OccurrenceTime = 0
+	if !occurrence.IsZero() {
+		job.OccurrenceTime = occurrence.Unix()
+		occurrenceTime = job.OccurrenceTime
+	}
+	_, err := executor.ExecScript("ChangeJobOccurrence", occurrenceTime, job.ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+const jobKindKey = "kind"
+
+// IsRecurring reports whether the extensions of a job ask for a recurring job
+// rather than a one-shot delayed job: `kind` is "cron", or, without a `kind`,
+// `expression` parses as a cron expression. Before cron jobs `expression` was
+// required but never read, so clients sending some other expression along
+// with `delay` keep getting delayed jobs.
+func IsRecurring(exts map[string]string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(exts[jobKindKey])) {
+	case "cron":
+		return true, nil
+	case "delay":
+		return false, nil
+	case "":
+	default:
+		return false, fmt.Errorf("extension item '%s' should be one of `cron`, `delay`", jobKindKey)
+	}
+	expression := strings.TrimSpace(exts["expression"])
+	if expression == "" {
+		return false, nil
+	}
+	_, err := essentials.ParseCronExpression(expression)
+	return err == nil, nil
+}
+
 func HandlePayloadExtension(e *essentials.ExtensionsEventArgs, executor essentials.DbExecutor) error {
 	expression, ok := e.Extensions["expression"]
 	if ok == false {
 		return fmt.Errorf("extension item '%s' was required", "expression")
 	}
+	retryPolicy, err := ParseRetryPolicy(e.Extensions)
+	if err != nil {
+		return err
+	}
+	recurring, err := IsRecurring(e.Extensions)
+	if err != nil {
+		return err
+	}
+	if recurring {
+		schedule, err := essentials.ParseCronExpression(expression)
+		if err != nil {
+			return err
+		}
+		next := schedule.Next(time.Now())
+		if next.IsZero() {
+			return fmt.Errorf("expression '%s' never fires", expression)
+		}
+		job := &Job{
+			MessageID:    e.MessageID,
+			Expression:   expression,
+			Kind:         CronJob,
+			KindName:     CronJob.String(),
+			NextFireTime: next.Unix(),
+			RetryPolicy:  retryPolicy,
+		}
+		_, err = job.Append(executor)
+		return err
+	}
 	delay, ok := e.Extensions["delay"]
 	if ok == false {
 		return fmt.Errorf("extension item '%s' was required", "delay")
@@ -50,6 +147,8 @@ func HandlePayloadExtension(e *essentials.ExtensionsEventArgs, executor essentia
 		DelaySeconds: int32(delaySeconds),
 		Kind:         BackgroundJob,
 		KindName:     BackgroundJob.String(),
+		NextFireTime: time.Now().Unix() + delaySeconds,
+		RetryPolicy:  retryPolicy,
 	}
 	if delay != "0" {
 		job.Kind = DelayJob
@@ -62,6 +161,73 @@ func HandlePayloadExtension(e *essentials.ExtensionsEventArgs, executor essentia
 	return nil
 }
 
+func scanJob(row interface {
+	Scan(dest ...interface{}) error
+}) (*Job, error) {
+	var job Job
+	var nextFireTime *int64
+	var retryPolicy *string
+	var occurrenceTime *int64
+	err := row.Scan(&job.ID, &job.MessageID, &job.Expression, &job.Kind, &job.KindName, &job.DelaySeconds, &nextFireTime, &retryPolicy, &occurrenceTime)
+	if err != nil {
+		return nil, err
+	}
+	if nextFireTime != nil {
+		job.NextFireTime = *nextFireTime
+	}
+	if occurrenceTime != nil {
+		job.OccurrenceTime = *occurrenceTime
+	}
+	if retryPolicy != nil && *retryPolicy != "" {
+		err = json.Unmarshal([]byte(*retryPolicy), &job.RetryPolicy)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &job, nil
+}
+
+// FindOneDueJob locks the earliest job of a Processing message of env whose
+// fire time is not after now. Rows locked by another instance are skipped. An
+// empty env finds jobs of every env.
+func FindOneDueJob(now int64, env string, executor essentials.DbExecutor) (*Job, error) {
+	row, err := executor.QueryScriptRow("FindOneDueJob", now, env)
+	if err != nil {
+		return nil, err
+	}
+	return scanJob(row)
+}
+
+// FindOneLockedJob locks the job of a message, waiting for the Scheduler if
+// it is firing the job right now.
+func FindOneLockedJob(messageID string, executor essentials.DbExecutor) (*Job, error) {
+	row, err := executor.QueryScriptRow("FindOneLockedJob", messageID)
+	if err != nil {
+		return nil, err
+	}
+	return scanJob(row)
+}
+
+// FindUnscheduledJobs returns the jobs of Processing messages that still have
+// work to do but no fire time, i.e. jobs created by the in-memory scheduler.
+// NextFireTime is filled with the message creation time plus the delay.
+func FindUnscheduledJobs(env string, executor essentials.DbExecutor) ([]*Job, error) {
+	rows, err := executor.QueryScript("FindUnscheduledJobs", env)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make([]*Job, 0)
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, job)
+	}
+	return result, nil
+}
+
 type AtJobDescriptor struct {
 	MessageId    string
 	DelaySeconds int64
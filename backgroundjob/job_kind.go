@@ -6,12 +6,16 @@ const (
 	_ JobKind = iota
 	BackgroundJob
 	DelayJob
+	CronJob
 )
 
 func ParseJobKind(kind string) JobKind {
 	if kind == "BackgroundJob" {
 		return BackgroundJob
 	}
+	if kind == "CronJob" {
+		return CronJob
+	}
 	return DelayJob
 }
 
@@ -19,5 +23,8 @@ func (kind JobKind) String() string {
 	if kind == BackgroundJob {
 		return "BackgroundJob"
 	}
+	if kind == CronJob {
+		return "CronJob"
+	}
 	return "DelayJob"
 }
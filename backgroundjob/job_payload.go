@@ -0,0 +1,29 @@
+package backgroundjob
+
+// CancelJobPayload is the body of /v1/job/cancel. Env is used when the
+// request has no `X-matcha-Env` header.
+type CancelJobPayload struct {
+	MessageID string `json:"message_id"`
+	Env       string `json:"env"`
+	Remark    string `json:"remark"`
+}
+
+// RescheduleJobPayload is the body of /v1/job/reschedule. Either Delay, in
+// seconds from now, or Time, as a unix timestamp, is required.
+type RescheduleJobPayload struct {
+	MessageID string `json:"message_id"`
+	Env       string `json:"env"`
+	Delay     *int64 `json:"delay"`
+	Time      *int64 `json:"time"`
+	Remark    string `json:"remark"`
+}
+
+// ReplayDeadLetterPayload is the body of /v1/job/replay. Delay, in seconds
+// from now, defaults to firing on the next poll. Env is used when the request
+// has no `X-matcha-Env` header.
+type ReplayDeadLetterPayload struct {
+	MessageID string `json:"message_id"`
+	Env       string `json:"env"`
+	Delay     *int64 `json:"delay"`
+	Remark    string `json:"remark"`
+}
@@ -0,0 +1,229 @@
+//go:build cgo
+// +build cgo
+
+package backgroundjob
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/standardcore/Matcha/essentials"
+)
+
+// newSQLiteSession returns a session on a fresh SQLite database with every
+// migration applied.
+func newSQLiteSession(t *testing.T) *essentials.Session {
+	t.Helper()
+	sess, err := essentials.NewSession(map[string]string{
+		"dbprefix":       "matcha",
+		"db_driver_name": "sqlite3",
+		"datasource":     "file:" + filepath.Join(t.TempDir(), "matcha.db"),
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sess.Close() })
+	migrator, err := essentials.NewMigrator(sess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = migrator.Up()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sess
+}
+
+func beginTx(t *testing.T, sess *essentials.Session) *essentials.DbTransaction {
+	t.Helper()
+	conn, err := sess.CreateConnectionFactory().Database()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := conn.BeginTx(sql.LevelDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// appendProcessingMessage stores a Processing message of env, published by
+// "shop", with one Scheduled subscription.
+func appendProcessingMessage(t *testing.T, env string, tx essentials.DbExecutor) *essentials.Message {
+	t.Helper()
+	msg, err := essentials.AppendMessage(&essentials.Payload{
+		Env:           env,
+		MessageType:   "order.created",
+		Content:       "{}",
+		Subscriptions: []*essentials.SubscriptionPayload{{Tag: "billing", Exchange: "matcha", RouteKey: "billing"}},
+		Headers:       map[string]interface{}{"x-matcha-client": "shop"},
+	}, nil, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = msg.Published(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestSQLiteJobScripts(t *testing.T) {
+	sess := newSQLiteSession(t)
+	tx := beginTx(t, sess)
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+	msg := appendProcessingMessage(t, "test", tx)
+	job := &Job{
+		MessageID:    msg.ID,
+		Kind:         DelayJob,
+		KindName:     DelayJob.String(),
+		DelaySeconds: 60,
+		NextFireTime: now + 60,
+	}
+	_, err := job.Append(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = FindOneDueJob(now, "test", tx)
+	if err != sql.ErrNoRows {
+		t.Fatalf("job due before its fire time: %v", err)
+	}
+	due, err := FindOneDueJob(now+60, "test", tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if due.ID != job.ID || due.Kind != DelayJob || due.DelaySeconds != 60 {
+		t.Errorf("unexpected due job %+v", due)
+	}
+	_, err = FindOneDueJob(now+60, "other", tx)
+	if err != sql.ErrNoRows {
+		t.Fatalf("job of env test due in env other: %v", err)
+	}
+
+	err = job.Reschedule(time.Unix(now+120, 0), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	locked, err := FindOneLockedJob(msg.ID, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if locked.NextFireTime != now+120 {
+		t.Errorf("fire time %d, want %d", locked.NextFireTime, now+120)
+	}
+
+	err = job.Reschedule(time.Time{}, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unscheduled, err := FindUnscheduledJobs("test", tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unscheduled) != 1 || unscheduled[0].ID != job.ID {
+		t.Fatalf("unexpected unscheduled jobs %+v", unscheduled)
+	}
+	if unscheduled[0].NextFireTime < now+60 {
+		t.Errorf("unscheduled fire time %d, want creation time + 60", unscheduled[0].NextFireTime)
+	}
+}
+
+func TestIsRecurring(t *testing.T) {
+	for _, c := range []struct {
+		exts      map[string]string
+		recurring bool
+		err       bool
+	}{
+		{map[string]string{"expression": "", "delay": "30"}, false, false},
+		{map[string]string{"expression": "order-42", "delay": "30"}, false, false},
+		{map[string]string{"expression": "*/5 * * * *"}, true, false},
+		{map[string]string{"expression": "@every 1m"}, true, false},
+		{map[string]string{"expression": "*/5 * * * *", "kind": "delay", "delay": "30"}, false, false},
+		{map[string]string{"expression": "not cron", "kind": "cron"}, true, false},
+		{map[string]string{"expression": "*/5 * * * *", "kind": "CRON"}, true, false},
+		{map[string]string{"expression": "", "kind": "weekly"}, false, true},
+	} {
+		recurring, err := IsRecurring(c.exts)
+		if recurring != c.recurring || (err != nil) != c.err {
+			t.Errorf("%v: recurring %v, err %v", c.exts, recurring, err)
+		}
+	}
+}
+
+func TestHandlePayloadExtensionKeepsRetryPolicy(t *testing.T) {
+	sess := newSQLiteSession(t)
+	tx := beginTx(t, sess)
+	defer tx.Rollback()
+
+	want := RetryPolicy{MaxAttempts: 3, InitialDelay: 5, Multiplier: 2, MaxDelay: 60, Jitter: 0.5}
+	exts := map[string]string{
+		retryMaxAttemptsKey:  "3",
+		retryInitialDelayKey: "5",
+		retryMultiplierKey:   "2",
+		retryMaxDelayKey:     "60",
+		retryJitterKey:       "0.5",
+	}
+	for _, c := range []struct {
+		name string
+		exts map[string]string
+		kind JobKind
+	}{
+		{"background", map[string]string{"expression": "", "delay": "0"}, BackgroundJob},
+		{"delay", map[string]string{"expression": "", "delay": "30"}, DelayJob},
+		{"cron", map[string]string{"expression": "*/5 * * * *"}, CronJob},
+	} {
+		for k, v := range exts {
+			c.exts[k] = v
+		}
+		msg := appendProcessingMessage(t, "", tx)
+		err := HandlePayloadExtension(&essentials.ExtensionsEventArgs{Extensions: c.exts, MessageID: msg.ID}, tx)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		job, err := FindOneLockedJob(msg.ID, tx)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if job.Kind != c.kind {
+			t.Errorf("%s: kind %s, want %s", c.name, job.Kind, c.kind)
+		}
+		if job.RetryPolicy != want {
+			t.Errorf("%s: retry policy %+v, want %+v", c.name, job.RetryPolicy, want)
+		}
+	}
+}
+
+func TestFindPendingJobChecksEnvAndPublisher(t *testing.T) {
+	sess := newSQLiteSession(t)
+	tx := beginTx(t, sess)
+	defer tx.Rollback()
+
+	msg := appendProcessingMessage(t, "dev", tx)
+	job := &Job{MessageID: msg.ID, Kind: DelayJob, KindName: DelayJob.String(), NextFireTime: time.Now().Unix() + 60}
+	_, err := job.Append(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		env    string
+		client string
+		ok     bool
+	}{
+		{"dev", "", true},
+		{"dev", "shop", true},
+		{"pro", "", false},
+		{"dev", "billing", false},
+	} {
+		_, _, err = findPendingJob(msg.ID, c.env, c.client, tx)
+		if (err == nil) != c.ok {
+			t.Errorf("env %s client %q: err %v", c.env, c.client, err)
+		}
+	}
+}
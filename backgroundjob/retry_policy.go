@@ -0,0 +1,146 @@
+package backgroundjob
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/standardcore/Matcha/essentials"
+)
+
+// RetryPolicy decides how often and how fast a job is retried after its
+// message could not be published. A zero field falls back to the default
+// policy, see DefaultRetryPolicy.
+type RetryPolicy struct {
+	MaxAttempts  int32   `json:"max_attempts,omitempty"`
+	InitialDelay int64   `json:"initial_delay,omitempty"`
+	Multiplier   float64 `json:"multiplier,omitempty"`
+	MaxDelay     int64   `json:"max_delay,omitempty"`
+	Jitter       float64 `json:"jitter,omitempty"`
+}
+
+const (
+	retryMaxAttemptsKey  = "retry_max_attempts"
+	retryInitialDelayKey = "retry_initial_delay"
+	retryMultiplierKey   = "retry_multiplier"
+	retryMaxDelayKey     = "retry_max_delay"
+	retryJitterKey       = "retry_jitter"
+)
+
+// DefaultRetryPolicy reads the global policy from parameters. Missing
+// parameters keep the former behaviour: 10 attempts, 15 seconds apart.
+func DefaultRetryPolicy(sess *essentials.Session) RetryPolicy {
+	policy := RetryPolicy{
+		MaxAttempts:  10,
+		InitialDelay: 15,
+		Multiplier:   1,
+		MaxDelay:     3600,
+	}
+	parsed, err := parseRetryPolicy(sess.LoadOrEmpty)
+	if err != nil {
+		sess.Logger().Errorln(essentials.WrapError("DefaultRetryPolicy", err))
+		return policy
+	}
+	return parsed.Merge(policy)
+}
+
+// ParseRetryPolicy reads the `retry_*` payload extensions of a job.
+func ParseRetryPolicy(exts map[string]string) (RetryPolicy, error) {
+	return parseRetryPolicy(func(key string) string {
+		return exts[key]
+	})
+}
+
+func parseRetryPolicy(load func(string) string) (RetryPolicy, error) {
+	var policy RetryPolicy
+	if v := load(retryMaxAttemptsKey); v != "" {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || n <= 0 {
+			return policy, fmt.Errorf("'%s' must be a positive integer, found '%s'", retryMaxAttemptsKey, v)
+		}
+		policy.MaxAttempts = int32(n)
+	}
+	if v := load(retryInitialDelayKey); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			return policy, fmt.Errorf("'%s' must be a positive number of seconds, found '%s'", retryInitialDelayKey, v)
+		}
+		policy.InitialDelay = n
+	}
+	if v := load(retryMultiplierKey); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 1 {
+			return policy, fmt.Errorf("'%s' must be a number not less than 1, found '%s'", retryMultiplierKey, v)
+		}
+		policy.Multiplier = f
+	}
+	if v := load(retryMaxDelayKey); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			return policy, fmt.Errorf("'%s' must be a positive number of seconds, found '%s'", retryMaxDelayKey, v)
+		}
+		policy.MaxDelay = n
+	}
+	if v := load(retryJitterKey); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 || f > 1 {
+			return policy, fmt.Errorf("'%s' must be a fraction between 0 and 1, found '%s'", retryJitterKey, v)
+		}
+		policy.Jitter = f
+	}
+	return policy, nil
+}
+
+// Merge fills the zero fields of p from defaults.
+func (p RetryPolicy) Merge(defaults RetryPolicy) RetryPolicy {
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+	if p.InitialDelay == 0 {
+		p.InitialDelay = defaults.InitialDelay
+	}
+	if p.Multiplier == 0 {
+		p.Multiplier = defaults.Multiplier
+	}
+	if p.MaxDelay == 0 {
+		p.MaxDelay = defaults.MaxDelay
+	}
+	if p.Jitter == 0 {
+		p.Jitter = defaults.Jitter
+	}
+	return p
+}
+
+// Backoff returns the wait before the next attempt once `attempt` attempts
+// have failed: InitialDelay * Multiplier^(attempt-1), capped at MaxDelay and
+// spread by up to ±Jitter of itself.
+func (p RetryPolicy) Backoff(attempt int32) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	seconds := float64(p.InitialDelay) * math.Pow(p.Multiplier, float64(attempt-1))
+	if p.MaxDelay > 0 && seconds > float64(p.MaxDelay) {
+		seconds = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		seconds += seconds * p.Jitter * (2*rand.Float64() - 1)
+	}
+	if seconds < 1 {
+		seconds = 1
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func (p RetryPolicy) marshal() (interface{}, error) {
+	if p == (RetryPolicy{}) {
+		return nil, nil
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,480 @@
+package backgroundjob
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/standardcore/Matcha/essentials"
+	"github.com/streadway/amqp"
+)
+
+// Scheduler fires background jobs once the due time stored in the
+// "NextFireTime" column of citadel.jobs has passed. Due rows are claimed with
+// FOR UPDATE SKIP LOCKED, so delayed jobs survive restarts and several matcha
+// instances can poll the same table without double-firing. A job that fails
+// to fire is postponed by its retry backoff rather than claimed again at once.
+type Scheduler struct {
+	sess     *essentials.Session
+	interval time.Duration
+	retry    RetryPolicy
+	wg       sync.WaitGroup
+	running  bool
+	mu       sync.Mutex
+	// lastRound is when the poll loop last went round.
+	lastRound time.Time
+}
+
+// NewScheduler creates a scheduler polling every `scheduler_interval`
+// seconds (default 1) while no job is due. Jobs without their own retry
+// policy use the `retry_*` parameters.
+func NewScheduler(sess *essentials.Session) *Scheduler {
+	interval := time.Second
+	if v := sess.LoadOrEmpty("scheduler_interval"); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err == nil && seconds > 0 {
+			interval = time.Duration(seconds) * time.Second
+		}
+	}
+	return &Scheduler{
+		sess:     sess,
+		interval: interval,
+		retry:    DefaultRetryPolicy(sess),
+	}
+}
+
+func (s *Scheduler) Start() {
+	if s.getRunning() {
+		return
+	}
+	s.setRunning(true)
+	s.sess.TryExchangeDeclare("deadletter_exchange")
+	s.sess.TryQueueDeclare("deadletter_queue")
+	s.wg.Add(1)
+	go s.poll()
+}
+
+func (s *Scheduler) Stop() {
+	s.setRunning(false)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) setRunning(v bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = v
+}
+
+func (s *Scheduler) getRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// Health reports whether the poll loop runs and goes round.
+func (s *Scheduler) Health() (essentials.LoopHealth, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return essentials.CheckLoop(s.running, s.lastRound, s.interval+2*time.Minute)
+}
+
+func (s *Scheduler) poll() {
+	defer s.wg.Done()
+	for s.getRunning() {
+		s.mu.Lock()
+		s.lastRound = time.Now()
+		s.mu.Unlock()
+		fired, err := s.fireNext(time.Now())
+		if err != nil {
+			s.sess.Logger().Errorln(essentials.WrapError("Scheduler", err))
+		}
+		if !fired || err != nil {
+			time.Sleep(s.interval)
+		}
+	}
+}
+
+// fireNext fires at most one due job and reports whether one was found.
+func (s *Scheduler) fireNext(now time.Time) (bool, error) {
+	conn, err := s.sess.CreateConnectionFactory().Database()
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	transact, err := conn.BeginTx(sql.LevelReadCommitted)
+	if err != nil {
+		return false, err
+	}
+	defer transact.Rollback()
+
+	job, err := FindOneDueJob(now.Unix(), s.sess.Env(), transact)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	policy := job.RetryPolicy.Merge(s.retry)
+	err = job.fire(s.sess, policy, now, transact)
+	if err != nil {
+		transact.Rollback()
+		err = essentials.WrapError("Scheduler:"+job.MessageID, err)
+		postponeErr := s.postpone(job, policy, err, now)
+		if postponeErr != nil {
+			essentials.LogWith(s.sess.Logger(), essentials.Fields{"message_id": job.MessageID}).Errorln(essentials.WrapError("Scheduler:"+job.MessageID, postponeErr))
+		}
+		return true, err
+	}
+	err = transact.Commit()
+	if err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// postpone records a failed fire of job in a transaction of its own and moves
+// its fire time by the policy backoff. The fire itself was rolled back, so
+// without this the job would stay the earliest due one and be claimed again
+// on every round, starving the jobs due after it.
+func (s *Scheduler) postpone(job *Job, policy RetryPolicy, cause error, now time.Time) error {
+	conn, err := s.sess.CreateConnectionFactory().Database()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	transact, err := conn.BeginTx(sql.LevelReadCommitted)
+	if err != nil {
+		return err
+	}
+	defer transact.Rollback()
+
+	locked, err := FindOneLockedJob(job.MessageID, transact)
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if locked.NextFireTime != job.NextFireTime {
+		// Cancelled or rescheduled in the meantime.
+		return nil
+	}
+	msg, err := essentials.FindOneMessage(job.MessageID, false, transact)
+	if err != nil {
+		return err
+	}
+	attempt, err := msg.IncreaseRetry(transact)
+	if err != nil {
+		return err
+	}
+	err = msg.AppendLog(fmt.Sprintf("attempt %d could not fire : %s", attempt, cause.Error()), transact)
+	if err != nil {
+		return err
+	}
+	essentials.JobRetries.Inc(job.KindName)
+	if locked.Kind == CronJob && locked.OccurrenceTime == 0 {
+		err = locked.ChangeOccurrence(locked.occurrence(), transact)
+		if err != nil {
+			return err
+		}
+	}
+	err = locked.Reschedule(now.Add(policy.Backoff(attempt)), transact)
+	if err != nil {
+		return err
+	}
+	return transact.Commit()
+}
+
+// fire publishes the job message to its subscriptions. One-shot jobs only
+// deliver to subscriptions still Scheduled and are then unscheduled; cron jobs
+// deliver each occurrence to every subscription, record a flow per delivery
+// and move on to the next occurrence of their expression. A failed publish is
+// retried according to policy; the retries of a cron occurrence only deliver
+// to the subscriptions it did not reach yet.
+func (job *Job) fire(sess *essentials.Session, policy RetryPolicy, now time.Time, executor essentials.DbExecutor) error {
+	msg, err := essentials.FindOneMessage(job.MessageID, false, executor)
+	if err != nil {
+		return err
+	}
+	subs, err := msg.FetchSubscriptions(executor)
+	if err != nil {
+		return err
+	}
+
+	occurrence := job.occurrence()
+	exts := make(map[string]string)
+	targets := make([]*essentials.Subscription, 0, len(subs))
+	if job.Kind == CronJob {
+		exts["x-matcha-fire-time"] = essentials.FormatTime(occurrence)
+		delivered := make(map[string]bool)
+		if job.OccurrenceTime != 0 {
+			delivered, err = fetchOccurrenceDeliveries(msg.ID, occurrence, executor)
+			if err != nil {
+				return err
+			}
+		}
+		for _, sub := range subs {
+			if !delivered[sub.ID] {
+				targets = append(targets, sub)
+			}
+		}
+	} else {
+		for _, sub := range subs {
+			if sub.StateName == "Scheduled" {
+				targets = append(targets, sub)
+			}
+		}
+	}
+
+	delivered, publishErr := publishJobMessage(sess, msg, targets, exts)
+	for _, sub := range delivered {
+		err = sub.ChangeState("Published", executor)
+		if err != nil {
+			return err
+		}
+		if job.Kind != CronJob {
+			continue
+		}
+		flow := &essentials.Flow{
+			SubscriptionID: sub.ID,
+			StateName:      "Published",
+			Remark:         occurrenceRemark(occurrence),
+		}
+		_, err = flow.Append(executor)
+		if err != nil {
+			return err
+		}
+	}
+	if publishErr != nil {
+		essentials.LogWith(sess.Logger(), essentials.Fields{"message_id": job.MessageID}).Errorln(essentials.WrapError("Scheduler:"+job.MessageID, publishErr))
+		return job.retry(sess, msg, targets[len(delivered):], exts, policy, publishErr, now, executor)
+	}
+
+	if job.Kind != CronJob {
+		err = msg.Published(executor)
+		if err != nil {
+			return err
+		}
+		return job.Reschedule(time.Time{}, executor)
+	}
+
+	if msg.Retry > 0 {
+		err = msg.ResetRetry(executor)
+		if err != nil {
+			return err
+		}
+	}
+	return job.nextOccurrence(now, executor)
+}
+
+// occurrence returns the scheduled time of the cron occurrence the job fires:
+// the one being retried, else the fire time.
+func (job *Job) occurrence() time.Time {
+	if job.OccurrenceTime != 0 {
+		return time.Unix(job.OccurrenceTime, 0)
+	}
+	return time.Unix(job.NextFireTime, 0)
+}
+
+func occurrenceRemark(occurrence time.Time) string {
+	return fmt.Sprintf("occurrence %s", essentials.FormatTime(occurrence))
+}
+
+// fetchOccurrenceDeliveries returns the IDs of the subscriptions of a message
+// the cron occurrence was delivered to, from their Published flows.
+func fetchOccurrenceDeliveries(messageID string, occurrence time.Time, executor essentials.DbExecutor) (map[string]bool, error) {
+	rows, err := executor.QueryScript("FetchOccurrenceDeliveries", messageID, occurrenceRemark(occurrence))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		result[id] = true
+	}
+	return result, rows.Err()
+}
+
+// retry records the failed attempt on every target subscription and fires the
+// job again after the policy backoff. Once the attempts are exhausted the
+// message is dead-lettered; a one-shot job then fails its message, while a
+// cron job skips to its next occurrence.
+func (job *Job) retry(sess *essentials.Session, msg *essentials.Message, targets []*essentials.Subscription, exts map[string]string, policy RetryPolicy, cause error, now time.Time, executor essentials.DbExecutor) error {
+	attempt, err := msg.IncreaseRetry(executor)
+	if err != nil {
+		return err
+	}
+	msg.Retry = attempt
+	exhausted := attempt >= policy.MaxAttempts
+
+	stateName := "Retrying"
+	remark := fmt.Sprintf("attempt %d/%d failed : %s", attempt, policy.MaxAttempts, cause.Error())
+	if exhausted {
+		stateName = "Failed"
+		remark = fmt.Sprintf("%s ; giving up", remark)
+	}
+	for _, sub := range targets {
+		flow := &essentials.Flow{
+			SubscriptionID: sub.ID,
+			StateName:      stateName,
+			Remark:         remark,
+		}
+		_, err = flow.Append(executor)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !exhausted {
+		essentials.JobRetries.Inc(job.KindName)
+		if job.Kind == CronJob && job.OccurrenceTime == 0 {
+			err = job.ChangeOccurrence(job.occurrence(), executor)
+			if err != nil {
+				return err
+			}
+		}
+		return job.Reschedule(now.Add(policy.Backoff(attempt)), executor)
+	}
+	essentials.JobGiveUps.Inc(job.KindName)
+	err = publishDeadLetter(sess, msg, targets, exts, remark, executor)
+	if err != nil {
+		return err
+	}
+	if job.Kind == CronJob {
+		err = msg.ResetRetry(executor)
+		if err != nil {
+			return err
+		}
+		return job.nextOccurrence(now, executor)
+	}
+	for _, sub := range targets {
+		err = sub.ChangeState("Failed", executor)
+		if err != nil {
+			return err
+		}
+	}
+	err = msg.ChangeStateWithRemark(essentials.MessageFailed, remark, executor)
+	if err != nil {
+		return err
+	}
+	return job.Reschedule(time.Time{}, executor)
+}
+
+// nextOccurrence ends the occurrence being fired and schedules the next one
+// after now.
+func (job *Job) nextOccurrence(now time.Time, executor essentials.DbExecutor) error {
+	if job.OccurrenceTime != 0 {
+		err := job.ChangeOccurrence(time.Time{}, executor)
+		if err != nil {
+			return err
+		}
+	}
+	schedule, err := essentials.ParseCronExpression(job.Expression)
+	if err != nil {
+		return err
+	}
+	return job.Reschedule(schedule.Next(now), executor)
+}
+
+// publishJobMessage publishes msg to subs in order, each one confirmed by the
+// broker, and returns the subscriptions delivered before the first failure.
+// Every delivery is a span of the trace of the message.
+func publishJobMessage(sess *essentials.Session, msg *essentials.Message, subs []*essentials.Subscription, exts map[string]string) ([]*essentials.Subscription, error) {
+	if len(subs) == 0 {
+		return subs, nil
+	}
+
+	channel, err := sess.AMQP().AcquireChannel()
+	if err != nil {
+		return nil, err
+	}
+	defer channel.Close()
+
+	deliveryMsg := newDeliveryMessage(msg, exts)
+	for i, sub := range subs {
+		exchange := sess.EnvExchange(msg.Env, sub.Exchange)
+		essentials.LogWith(sess.Logger(), essentials.Fields{"message_id": msg.ID, "receiver_tag": sub.ReceiverTag}).Infoln(fmt.Sprintf("Exchange : %s ; Key : %s ", exchange, sub.RouteKey))
+		span := sess.StartSpan("publish job", msg.Trace())
+		span.SetAttribute("message_id", msg.ID).SetAttribute("exchange", exchange).SetAttribute("route_key", sub.RouteKey)
+		span.SetAttribute("attempt", strconv.Itoa(int(msg.Retry)+1))
+		err = publishDelivery(sess, channel, deliveryMsg, exchange, sub.RouteKey, span.Context())
+		span.Finish(err)
+		if err != nil {
+			return subs[:i], err
+		}
+	}
+	return subs, nil
+}
+
+// publishDelivery publishes deliveryMsg carrying the trace context trace.
+func publishDelivery(sess *essentials.Session, channel *essentials.ConfirmChannel, deliveryMsg *essentials.DeliveryMessage, exchange string, routeKey string, trace essentials.TraceContext) error {
+	headers := make(amqp.Table)
+	trace.Inject(deliveryMsg.Extensions, headers)
+	body, err := json.Marshal(deliveryMsg)
+	if err != nil {
+		return err
+	}
+	p := amqp.Publishing{
+		Headers: headers,
+		Body:    body,
+	}
+	if ttl := sess.LoadOrEmpty("message_ttl"); ttl != "" {
+		p.Expiration = ttl
+	}
+	return channel.Publish(exchange, routeKey, p)
+}
+
+func newDeliveryMessage(msg *essentials.Message, exts map[string]string) *essentials.DeliveryMessage {
+	deliveryMsg := &essentials.DeliveryMessage{
+		MessageID:   msg.ID,
+		MessageType: msg.MessageType,
+		Content:     msg.Content,
+		PublishTime: time.Now().Unix(),
+		Extensions:  make(map[string]string),
+	}
+	deliveryMsg.Extensions["x-matcha-tag"] = msg.Publisher
+	deliveryMsg.Extensions["x-matcha-env"] = msg.Env
+	for k, v := range exts {
+		deliveryMsg.Extensions[k] = v
+	}
+	return deliveryMsg
+}
+
+// RebuildScheduler gives a fire time to jobs created before due times were
+// persisted, so they are picked up by the Scheduler.
+func RebuildScheduler(sess *essentials.Session) error {
+	conn, err := sess.CreateConnectionFactory().Database()
+	if err != nil {
+		return essentials.WrapError("RebuildScheduler", err)
+	}
+	defer conn.Close()
+
+	jobs, err := FindUnscheduledJobs(sess.Env(), conn)
+	if err != nil {
+		return essentials.WrapError("RebuildScheduler", err)
+	}
+	now := time.Now()
+	for _, job := range jobs {
+		next := time.Unix(job.NextFireTime, 0)
+		if job.Kind == CronJob {
+			schedule, err := essentials.ParseCronExpression(job.Expression)
+			if err != nil {
+				essentials.LogWith(sess.Logger(), essentials.Fields{"message_id": job.MessageID}).Errorln(essentials.WrapError("RebuildScheduler:"+job.MessageID, err))
+				continue
+			}
+			next = schedule.Next(now)
+		}
+		err = job.Reschedule(next, conn)
+		if err != nil {
+			return essentials.WrapError("RebuildScheduler", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+//go:build cgo
+// +build cgo
+
+package backgroundjob
+
+import (
+	"testing"
+	"time"
+
+	"github.com/standardcore/Matcha/essentials"
+)
+
+func TestSchedulerPostponesJobThatFailsToFire(t *testing.T) {
+	sess := newSQLiteSession(t)
+	scheduler := NewScheduler(sess)
+	now := time.Now()
+
+	// A cron job whose expression no longer parses fails after it was
+	// claimed; it is due before the healthy job.
+	tx := beginTx(t, sess)
+	broken, err := essentials.AppendMessage(&essentials.Payload{MessageType: "broken", Content: "{}"}, nil, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = broken.Published(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	brokenJob := &Job{MessageID: broken.ID, Expression: "bogus", Kind: CronJob, KindName: CronJob.String(), NextFireTime: now.Unix() - 10}
+	_, err = brokenJob.Append(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	healthy, err := essentials.AppendMessage(&essentials.Payload{MessageType: "healthy", Content: "{}"}, nil, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = healthy.Published(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	healthyJob := &Job{MessageID: healthy.ID, Kind: DelayJob, KindName: DelayJob.String(), NextFireTime: now.Unix() - 5}
+	_, err = healthyJob.Append(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fired, err := scheduler.fireNext(now)
+	if !fired || err == nil {
+		t.Fatalf("broken job: fired %v, err %v", fired, err)
+	}
+	conn, err := sess.CreateConnectionFactory().Database()
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := FindOneLockedJob(broken.ID, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.NextFireTime <= now.Unix() {
+		t.Errorf("broken job still due at %d", job.NextFireTime)
+	}
+	msg, err := essentials.FindOneMessage(broken.ID, false, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Retry != 1 {
+		t.Errorf("broken job retry %d, want 1", msg.Retry)
+	}
+
+	fired, err = scheduler.fireNext(now)
+	if !fired || err != nil {
+		t.Fatalf("healthy job: fired %v, err %v", fired, err)
+	}
+	job, err = FindOneLockedJob(healthy.ID, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.NextFireTime != 0 {
+		t.Errorf("healthy job not fired, due at %d", job.NextFireTime)
+	}
+}
+
+func TestCronRetryOnlyDeliversUndeliveredSubscriptions(t *testing.T) {
+	// Without a `rabbitmq` parameter every publish fails.
+	sess := newSQLiteSession(t)
+	tx := beginTx(t, sess)
+	defer tx.Rollback()
+
+	msg, err := essentials.AppendMessage(&essentials.Payload{
+		MessageType: "report",
+		Content:     "{}",
+		Subscriptions: []*essentials.SubscriptionPayload{
+			{Tag: "billing", Exchange: "matcha", RouteKey: "billing"},
+			{Tag: "shipping", Exchange: "matcha", RouteKey: "shipping"},
+		},
+	}, nil, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = msg.Published(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheduled := time.Now().Add(-time.Minute).Truncate(time.Second)
+	job := &Job{MessageID: msg.ID, Expression: "0 * * * * *", Kind: CronJob, KindName: CronJob.String(), NextFireTime: scheduled.Unix()}
+	_, err = job.Append(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	err = job.fire(sess, DefaultRetryPolicy(sess), now, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err = FindOneLockedJob(msg.ID, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.OccurrenceTime != scheduled.Unix() {
+		t.Errorf("occurrence %d, want the scheduled time %d", job.OccurrenceTime, scheduled.Unix())
+	}
+	if job.NextFireTime <= now.Unix() {
+		t.Errorf("retry not scheduled, fire time %d", job.NextFireTime)
+	}
+
+	// The first attempt reached billing.
+	subs, err := msg.FetchSubscriptions(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bySub := make(map[string]*essentials.Subscription)
+	for _, sub := range subs {
+		bySub[sub.ReceiverTag] = sub
+	}
+	_, err = (&essentials.Flow{SubscriptionID: bySub["billing"].ID, StateName: "Published", Remark: occurrenceRemark(scheduled)}).Append(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = job.fire(sess, DefaultRetryPolicy(sess), now, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	retried := func(tag string) int {
+		flows, err := bySub[tag].FetchFlows(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := 0
+		for _, flow := range flows {
+			if flow.StateName == "Retrying" {
+				n++
+			}
+		}
+		return n
+	}
+	if n := retried("shipping"); n != 2 {
+		t.Errorf("shipping retried %d times, want 2", n)
+	}
+	if n := retried("billing"); n != 1 {
+		t.Errorf("billing retried %d times, want only the first attempt", n)
+	}
+	job, err = FindOneLockedJob(msg.ID, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.OccurrenceTime != scheduled.Unix() {
+		t.Errorf("occurrence %d after the retry, want %d", job.OccurrenceTime, scheduled.Unix())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 
@@ -35,21 +36,32 @@ func (s *AppendSliceValue) Set(value string) error {
 // Command runs a FeiniuPay agent.
 type Command struct {
 	args []string
+	// rest holds the arguments left after the flags, e.g. `up` in
+	// `matcha migrate -config-file=appsettings.json up`.
+	rest []string
+	// cfgFiles and cmdCfg are kept to read the configuration again on
+	// SIGHUP.
+	cfgFiles []string
+	cmdCfg   agent.Config
 	//logger *log.Logger
-	logger logging.Logger
+	logger   logging.Logger
+	logLevel *essentials.LogLevelFilter
 }
 
 // Run returns
 func (cmd *Command) Run(args []string) int {
 	code := cmd.run(args)
 	if cmd.logger != nil {
-		//defer cmd.logger.Sync()
 		cmd.logger.Println("Exit code: ", code)
+		_ = cmd.logger.Sync()
 	}
 	return code
 }
 
 func (cmd *Command) run(args []string) int {
+	if len(args) > 0 && args[0] == "migrate" {
+		return cmd.migrate(args[1:])
+	}
 	cmd.args = args
 	config := cmd.readConfig()
 
@@ -57,14 +69,6 @@ func (cmd *Command) run(args []string) int {
 		return 1
 	}
 
-	if cmd.logger == nil {
-		cmd.logger = logging.NewLogger()
-	}
-	cmd.logger.TryAddProvider(essentials.NewLogstashProvider(false, "office.feelbus.cn", 7789))
-
-	//cmd.logger, _ = log.NewLogstash(false, "office.feelbus.cn", 7789)
-	//defer cmd.logger.Sync()
-
 	cmd.logger.Println("Starting matcha agent...")
 	agent, err := agent.New(config, cmd.logger)
 	if err != nil {
@@ -77,11 +81,10 @@ func (cmd *Command) run(args []string) int {
 		return 1
 	}
 
+	agent.ConfigLoader(cmd.loadConfig).LogLevel(cmd.logLevel)
 	agent.StartSync()
-	defer agent.ShutdownEndpoints()
 
 	cmd.logger.Println("matcha agent running!")
-	//cmd.logger.Sync()
 
 	signalCh := make(chan os.Signal, 4)
 	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGPIPE)
@@ -98,12 +101,55 @@ func (cmd *Command) run(args []string) int {
 		case syscall.SIGPIPE:
 			continue
 
+		case syscall.SIGHUP:
+			cmd.reload(agent)
+
 		default:
+			return cmd.shutdown(agent, signalCh)
+		}
+	}
+}
+
+// shutdown drains the agent and returns 0 once everything stopped in time,
+// 1 when the drain timed out or a second signal cut it short.
+func (cmd *Command) shutdown(a *agent.Agent, signalCh <-chan os.Signal) int {
+	cmd.logger.Println("Gracefully shutting down matcha agent...")
+	done := make(chan bool, 1)
+	go func() {
+		done <- a.Shutdown()
+	}()
+
+	for {
+		select {
+		case drained := <-done:
+			if !drained {
+				cmd.logger.Println("Shutdown deadline exceeded, work may have been abandoned")
+				return 1
+			}
 			return 0
+		case sig := <-signalCh:
+			if sig == syscall.SIGPIPE || sig == syscall.SIGHUP {
+				continue
+			}
+			cmd.logger.Println("Caught second signal, exiting without waiting for the drain")
+			return 1
 		}
 	}
 }
 
+// reload applies the configuration files again to the running agent.
+func (cmd *Command) reload(a *agent.Agent) {
+	cmd.logger.Println("Caught SIGHUP, reloading configuration...")
+	report, err := a.Reload()
+	if err != nil {
+		cmd.logger.Errorln(err.Error())
+		return
+	}
+	if len(report.RestartRequired) > 0 {
+		cmd.logger.Warnln("Restart required to apply: " + strings.Join(report.RestartRequired, ", "))
+	}
+}
+
 func (cmd *Command) readConfig() *agent.Config {
 	var cmdCfg agent.Config
 	var cfgFiles []string
@@ -112,7 +158,7 @@ func (cmd *Command) readConfig() *agent.Config {
 	f.Var((*AppendSliceValue)(&cfgFiles), "config-file", "Path to a JSON file to read configuration from. This can be specified multiple times.")
 	f.Var((*AppendSliceValue)(&cfgFiles), "config-dir", "Path to a directory to read configuration files from.")
 
-	f.StringVar(&cmdCfg.ConsoleOutput, "console_output", "false", "Show log in console.")
+	f.StringVar(&cmdCfg.ConsoleOutput, "console_output", "", "Show log in console.")
 	f.StringVar(&cmdCfg.Address, "client", "", "Sets the address to bind for client access. This includes HTTP and HTTPS (if configured).")
 	f.IntVar(&cmdCfg.Port, "http-port", 0, "Sets the HTTP API port to listen on.")
 	f.StringVar(&cmdCfg.LogLevel, "log-level", "", "Log level of the system.")
@@ -122,34 +168,132 @@ func (cmd *Command) readConfig() *agent.Config {
 	f.StringVar(&cmdCfg.ConsulDatacenter, "consul_dc", "", "consul datacenter")
 	f.StringVar(&cmdCfg.LogstashHost, "logstash_host", "", "Logstash host address")
 	f.IntVar(&cmdCfg.LogstashPort, "logstash_port", 0, "Logstash port")
+	f.IntVar(&cmdCfg.ShutdownTimeout, "shutdown_timeout", 0, "Seconds to wait for in-flight work on shutdown.")
 	//f.StringVar(&cmdCfg.ServicePrefix, "service_prefix", "", "Service Prefix")
 
 	if err := f.Parse(cmd.args); err != nil {
 		fmt.Println(err.Error())
 		return nil
 	}
+	cmd.rest = f.Args()
+	cmd.cfgFiles = cfgFiles
+	cmd.cmdCfg = cmdCfg
 
-	if cmd.logger == nil {
-		cmd.logger = logging.NewLogger()
+	cfg, err := cmd.loadConfig()
+	if err != nil {
+		fmt.Println(err.Error())
+		return nil
+	}
+
+	cmd.logLevel, err = essentials.NewLogLevelFilter(cfg.LogLevel)
+	if err != nil {
+		fmt.Println(err.Error())
+		return nil
 	}
 
-	if cmdCfg.ConsoleOutput == "true" {
-		cmd.logger.TryAddProvider(logging.NewConsoleProvider())
+	logger, err := essentials.NewLogger(cfg.LogProviders(), cmd.logLevel)
+	if err != nil {
+		fmt.Println(err.Error())
+		return nil
 	}
+	cmd.logger = logger
+
+	return cfg
+}
 
+// loadConfig merges the defaults, the configuration files and the flags, in
+// that order.
+func (cmd *Command) loadConfig() (*agent.Config, error) {
 	cfg := agent.DefaultConfig()
 
-	if len(cfgFiles) > 0 {
-		fileConfig, err := agent.ReadConfigPaths(cfgFiles)
+	if len(cmd.cfgFiles) > 0 {
+		fileConfig, err := agent.ReadConfigPaths(cmd.cfgFiles)
 		if err != nil {
-			fmt.Println(err.Error())
-			return nil
+			return nil, err
 		}
 
 		cfg = agent.MergeConfig(cfg, fileConfig)
 	}
 
-	cfg = agent.MergeConfig(cfg, &cmdCfg)
+	flagConfig := cmd.cmdCfg
+	return agent.MergeConfig(cfg, &flagConfig), nil
+}
+
+const migrateUsage = "usage: matcha migrate [-config-file=<path>] up | down [steps] | status"
+
+// migrate runs `matcha migrate up|down [steps]|status` against the database
+// and `dbprefix` schema of the configuration. down reverts one migration
+// unless told otherwise.
+func (cmd *Command) migrate(args []string) int {
+	cmd.args = args
+	config := cmd.readConfig()
+	if config == nil {
+		return 1
+	}
+	if len(cmd.rest) == 0 {
+		fmt.Println(migrateUsage)
+		return 1
+	}
+
+	sess, err := essentials.NewSession(config.Parameters, config.Declarations)
+	if err != nil {
+		fmt.Printf("Error creating session: %s\n", err)
+		return 1
+	}
+	defer sess.Close()
+	sess.SETLogger(cmd.logger)
+	migrator, err := essentials.NewMigrator(sess)
+	if err != nil {
+		fmt.Printf("Error loading migrations: %s\n", err)
+		return 1
+	}
 
-	return cfg
+	switch cmd.rest[0] {
+	case "up":
+		applied, err := migrator.Up()
+		for _, m := range applied {
+			fmt.Printf("applied  %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fmt.Println(err.Error())
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+	case "down":
+		steps := 1
+		if len(cmd.rest) > 1 {
+			steps, err = strconv.Atoi(cmd.rest[1])
+			if err != nil || steps <= 0 {
+				fmt.Println(migrateUsage)
+				return 1
+			}
+		}
+		reverted, err := migrator.Down(steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fmt.Println(err.Error())
+			return 1
+		}
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			fmt.Println(err.Error())
+			return 1
+		}
+		for _, s := range status {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedTime
+			}
+			fmt.Printf("%04d_%s\t%s\n", s.Version, s.Name, state)
+		}
+	default:
+		fmt.Println(migrateUsage)
+		return 1
+	}
+	return 0
 }
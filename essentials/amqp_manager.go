@@ -0,0 +1,349 @@
+package essentials
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+var (
+	ErrAmqpNotConnected       = errors.New("amqp: not connected, reconnecting")
+	ErrAmqpManagerClosed      = errors.New("amqp: connection manager closed")
+	ErrAmqpChannelPoolTimeout = errors.New("amqp: timed out waiting for a free channel")
+)
+
+// AmqpConnectionManager owns the single RabbitMQ connection of a session. It
+// hands out confirm channels from a bounded pool, reconnects with exponential
+// backoff when the broker drops the connection and reports its health.
+//
+// Parameters: `amqp_channel_pool_size` (default 16) bounds the channels open
+// at once, idle ones included, `amqp_channel_acquire_timeout` (seconds,
+// default 30) bounds the wait for a free one and `amqp_reconnect_max_delay`
+// (seconds, default 30) caps the backoff between reconnect attempts.
+type AmqpConnectionManager struct {
+	sess *Session
+
+	slots          chan struct{}
+	idle           chan *ConfirmChannel
+	acquireTimeout time.Duration
+	maxDelay       time.Duration
+
+	mu            sync.Mutex
+	conn          *amqp.Connection
+	reconnecting  bool
+	closed        bool
+	reconnects    int
+	lastError     error
+	lastConnected time.Time
+}
+
+// AmqpHealth is a snapshot of the state of an AmqpConnectionManager.
+type AmqpHealth struct {
+	Connected     bool   `json:"connected"`
+	Reconnecting  bool   `json:"reconnecting"`
+	Reconnects    int    `json:"reconnects"`
+	LastError     string `json:"last_error,omitempty"`
+	LastConnected string `json:"last_connected,omitempty"`
+	PoolSize      int    `json:"pool_size"`
+	OpenChannels  int    `json:"open_channels"`
+	IdleChannels  int    `json:"idle_channels"`
+}
+
+func NewAmqpConnectionManager(sess *Session) *AmqpConnectionManager {
+	size := loadPositiveInt(sess, "amqp_channel_pool_size", 16)
+	return &AmqpConnectionManager{
+		sess:           sess,
+		slots:          make(chan struct{}, size),
+		idle:           make(chan *ConfirmChannel, size),
+		acquireTimeout: time.Duration(loadPositiveInt(sess, "amqp_channel_acquire_timeout", 30)) * time.Second,
+		maxDelay:       time.Duration(loadPositiveInt(sess, "amqp_reconnect_max_delay", 30)) * time.Second,
+	}
+}
+
+func loadPositiveInt(sess *Session, key string, def int) int {
+	if v := sess.LoadOrEmpty(key); v != "" {
+		n, err := strconv.Atoi(v)
+		if err == nil && n > 0 {
+			return n
+		}
+	}
+	return def
+}
+
+// Connection returns the shared connection, dialing it on first use. While the
+// manager is reconnecting ErrAmqpNotConnected is returned. Callers must not
+// close the connection.
+func (m *AmqpConnectionManager) Connection() (*amqp.Connection, error) {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil, ErrAmqpManagerClosed
+	}
+	if m.conn != nil {
+		conn := m.conn
+		m.mu.Unlock()
+		return conn, nil
+	}
+	if m.reconnecting {
+		m.mu.Unlock()
+		return nil, ErrAmqpNotConnected
+	}
+	m.mu.Unlock()
+
+	// The dial runs without mu so that Health, release and the other
+	// callers are not held up by a slow or unreachable broker.
+	conn, err := m.dial()
+	m.mu.Lock()
+	if err != nil {
+		m.lastError = err
+		if !m.closed && m.conn == nil && !m.reconnecting {
+			m.reconnecting = true
+			go m.reconnect()
+		}
+		m.mu.Unlock()
+		return nil, WrapError("AmqpConnectionManager", err)
+	}
+	current, err := m.install(conn)
+	m.mu.Unlock()
+	if current != conn {
+		conn.Close()
+	}
+	return current, err
+}
+
+// dial opens a connection to the `rabbitmq` broker. It must be called
+// without mu held.
+func (m *AmqpConnectionManager) dial() (*amqp.Connection, error) {
+	uri, err := m.sess.Require("rabbitmq")
+	if err != nil {
+		return nil, err
+	}
+	conn, err := amqp.Dial(uri)
+	if err != nil {
+		AmqpConnectionErrors.Inc("dial")
+		return nil, err
+	}
+	return conn, nil
+}
+
+// install makes conn the shared connection and returns it, unless the
+// manager was closed or another caller installed a connection while conn was
+// dialed; the caller then closes conn. install must be called with mu held.
+func (m *AmqpConnectionManager) install(conn *amqp.Connection) (*amqp.Connection, error) {
+	if m.closed {
+		return nil, ErrAmqpManagerClosed
+	}
+	if m.conn != nil {
+		return m.conn, nil
+	}
+	m.conn = conn
+	m.lastConnected = time.Now()
+	go m.watch(conn, conn.NotifyClose(make(chan *amqp.Error, 1)))
+	return conn, nil
+}
+
+func (m *AmqpConnectionManager) watch(conn *amqp.Connection, closed chan *amqp.Error) {
+	err, ok := <-closed
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.conn == conn {
+		m.conn = nil
+	}
+	if m.closed || !ok || err == nil {
+		return
+	}
+	m.lastError = err
+	AmqpConnectionErrors.Inc("closed")
+	m.sess.Logger().Errorln(WrapError("AmqpConnectionManager", err))
+	if !m.reconnecting {
+		m.reconnecting = true
+		go m.reconnect()
+	}
+}
+
+func (m *AmqpConnectionManager) reconnect() {
+	delay := time.Second
+	for {
+		time.Sleep(delay)
+		m.mu.Lock()
+		if m.closed || m.conn != nil {
+			m.reconnecting = false
+			m.mu.Unlock()
+			return
+		}
+		m.mu.Unlock()
+
+		conn, err := m.dial()
+		m.mu.Lock()
+		if err == nil {
+			current, err := m.install(conn)
+			m.reconnecting = false
+			if current == conn {
+				m.reconnects++
+			}
+			m.mu.Unlock()
+			if current != conn {
+				conn.Close()
+			}
+			if err == nil {
+				m.sess.Logger().Infoln("AmqpConnectionManager : reconnected")
+			}
+			return
+		}
+		m.lastError = err
+		m.mu.Unlock()
+		m.sess.Logger().Errorln(fmt.Sprintf("AmqpConnectionManager : reconnect failed, retrying in %s : %s", delay, err.Error()))
+		delay *= 2
+		if delay > m.maxDelay {
+			delay = m.maxDelay
+		}
+	}
+}
+
+// AcquireChannel takes a confirm channel from the pool, opening one on the
+// shared connection if none is idle. Close the channel to give it back.
+func (m *AmqpConnectionManager) AcquireChannel() (*ConfirmChannel, error) {
+	c, err := m.acquire()
+	if err != nil {
+		return nil, err
+	}
+	if c != nil && !c.IsClosed() {
+		return c, nil
+	}
+	conn, err := m.Connection()
+	if err != nil {
+		<-m.slots
+		return nil, err
+	}
+	c, err = NewConfirmChannel(m.sess, conn)
+	if err != nil {
+		<-m.slots
+		return nil, err
+	}
+	c.manager = m
+	return c, nil
+}
+
+// acquire takes an idle channel, which keeps the slot it holds, or a free
+// slot for a new channel, in which case the channel is nil. Idle channels
+// count against the pool size, so it waits for whichever comes first.
+func (m *AmqpConnectionManager) acquire() (*ConfirmChannel, error) {
+	select {
+	case c := <-m.idle:
+		return c, nil
+	default:
+	}
+	select {
+	case c := <-m.idle:
+		return c, nil
+	case m.slots <- struct{}{}:
+		return nil, nil
+	case <-time.After(m.acquireTimeout):
+		return nil, ErrAmqpChannelPoolTimeout
+	}
+}
+
+// release puts c back in the idle pool, or closes it and frees its slot when
+// it is closed already or the manager is.
+func (m *AmqpConnectionManager) release(c *ConfirmChannel) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.closed && !c.IsClosed() {
+		select {
+		case m.idle <- c:
+			return
+		default:
+		}
+	}
+	c.channel.Close()
+	<-m.slots
+}
+
+// WithChannel runs fn on a plain channel of the shared connection. The
+// channel is closed afterwards rather than pooled, so fn may put it in
+// transaction mode or have it closed by a failed declaration.
+func (m *AmqpConnectionManager) WithChannel(fn func(channel *amqp.Channel) error) error {
+	idle, err := m.acquire()
+	if err != nil {
+		return err
+	}
+	if idle != nil {
+		// The plain channel takes the slot of the idle one.
+		idle.channel.Close()
+	}
+	defer func() { <-m.slots }()
+	conn, err := m.Connection()
+	if err != nil {
+		return err
+	}
+	channel, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer channel.Close()
+	return fn(channel)
+}
+
+func (m *AmqpConnectionManager) Health() AmqpHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h := AmqpHealth{
+		Connected:    m.conn != nil,
+		Reconnecting: m.reconnecting,
+		Reconnects:   m.reconnects,
+		PoolSize:     cap(m.slots),
+		OpenChannels: len(m.slots),
+		IdleChannels: len(m.idle),
+	}
+	if m.lastError != nil {
+		h.LastError = m.lastError.Error()
+	}
+	if !m.lastConnected.IsZero() {
+		h.LastConnected = FormatTime(m.lastConnected)
+	}
+	return h
+}
+
+// Reset closes the shared connection so that the next use dials it again
+// with the current `rabbitmq` parameter. Consumers revive on the new
+// connection and pooled channels of the old one are discarded.
+func (m *AmqpConnectionManager) Reset() {
+	m.mu.Lock()
+	conn := m.conn
+	m.conn = nil
+	m.mu.Unlock()
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+// Close closes the pooled channels and the shared connection and stops
+// reconnecting.
+func (m *AmqpConnectionManager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	for {
+		select {
+		case c := <-m.idle:
+			c.channel.Close()
+			<-m.slots
+			continue
+		default:
+		}
+		break
+	}
+	if m.conn == nil {
+		return nil
+	}
+	conn := m.conn
+	m.conn = nil
+	return conn.Close()
+}
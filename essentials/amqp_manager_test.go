@@ -0,0 +1,75 @@
+package essentials
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestAmqpConnectionDialsWithoutLock(t *testing.T) {
+	// The broker accepts the connection but never answers the handshake.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+
+	sess, err := NewSession(map[string]string{
+		"dbprefix":       "matcha",
+		"db_driver_name": "postgres",
+		"rabbitmq":       "amqp://guest:guest@" + listener.Addr().String() + "/",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := sess.AMQP()
+	defer m.Close()
+
+	dialed := make(chan error, 1)
+	go func() {
+		_, err := m.Connection()
+		dialed <- err
+	}()
+	var conn net.Conn
+	select {
+	case conn = <-accepted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the manager never dialed")
+	}
+
+	health := make(chan AmqpHealth, 1)
+	go func() { health <- m.Health() }()
+	select {
+	case h := <-health:
+		if h.Connected {
+			t.Error("connected before the handshake")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Health blocked by the dial in progress")
+	}
+
+	conn.Close()
+	select {
+	case err = <-dialed:
+		if err == nil {
+			t.Fatal("dial succeeded without a broker")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("dial did not fail after the broker hung up")
+	}
+	h := m.Health()
+	if h.Connected || !h.Reconnecting || h.LastError == "" {
+		t.Errorf("after a failed dial: %+v", h)
+	}
+	_, err = m.Connection()
+	if err != ErrAmqpNotConnected {
+		t.Errorf("while reconnecting: %v, want %v", err, ErrAmqpNotConnected)
+	}
+}
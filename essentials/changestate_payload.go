@@ -5,5 +5,6 @@ type ChangeStatePayload struct {
 	NewState   string            `json:"state"`
 	ClientTag  string            `json:"tag"`
 	Remark     string            `json:"remark"`
+	Env        string            `json:"env"`
 	Extensions map[string]string `json:"exts"`
 }
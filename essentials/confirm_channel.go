@@ -0,0 +1,150 @@
+package essentials
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+var (
+	ErrPublishNacked  = errors.New("publish: message was nacked by the broker")
+	ErrPublishTimeout = errors.New("publish: timed out waiting for the broker confirmation")
+)
+
+// UnroutableError reports a mandatory message the broker could not route to
+// any queue.
+type UnroutableError struct {
+	Exchange  string
+	RouteKey  string
+	ReplyCode uint16
+	ReplyText string
+}
+
+func (e *UnroutableError) Error() string {
+	return fmt.Sprintf("publish: unroutable message (%d %s) ; Exchange : %s ; Key : %s", e.ReplyCode, e.ReplyText, e.Exchange, e.RouteKey)
+}
+
+// ConfirmChannel is a channel in confirm mode. Publish only returns nil once
+// the broker acknowledged the message and did not return it as unroutable.
+type ConfirmChannel struct {
+	channel  *amqp.Channel
+	confirms chan amqp.Confirmation
+	returns  chan amqp.Return
+	closed   chan *amqp.Error
+	timeout  time.Duration
+	manager  *AmqpConnectionManager
+}
+
+// NewConfirmChannel opens a channel on conn and puts it in confirm mode.
+// Confirmations are awaited for `publish_confirm_timeout` seconds (default 5).
+func NewConfirmChannel(sess *Session, conn *amqp.Connection) (*ConfirmChannel, error) {
+	channel, err := conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+	err = channel.Confirm(false)
+	if err != nil {
+		channel.Close()
+		return nil, err
+	}
+	c := &ConfirmChannel{
+		channel:  channel,
+		confirms: channel.NotifyPublish(make(chan amqp.Confirmation, 1)),
+		returns:  channel.NotifyReturn(make(chan amqp.Return, 1)),
+		closed:   channel.NotifyClose(make(chan *amqp.Error, 1)),
+		timeout:  5 * time.Second,
+	}
+	if v := sess.LoadOrEmpty("publish_confirm_timeout"); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err == nil && seconds > 0 {
+			c.timeout = time.Duration(seconds) * time.Second
+		}
+	}
+	return c, nil
+}
+
+// Channel returns the underlying channel, e.g. to declare exchanges.
+func (c *ConfirmChannel) Channel() *amqp.Channel {
+	return c.channel
+}
+
+// Publish publishes msg with the mandatory flag and waits for its
+// confirmation. The broker sends a basic.return before the ack of the same
+// message, so a return is always seen by the time the ack arrives. After a
+// timeout the channel is closed, since a late confirmation could no longer be
+// told apart from the next one.
+func (c *ConfirmChannel) Publish(exchange string, routeKey string, msg amqp.Publishing) error {
+	err := c.publish(exchange, routeKey, msg)
+	Publishes.Inc(exchange, publishResult(err))
+	return err
+}
+
+func (c *ConfirmChannel) publish(exchange string, routeKey string, msg amqp.Publishing) error {
+	err := c.channel.Publish(exchange, routeKey, true, false, msg)
+	if err != nil {
+		return err
+	}
+	select {
+	case confirm, ok := <-c.confirms:
+		if !ok {
+			return amqp.ErrClosed
+		}
+		select {
+		case ret := <-c.returns:
+			return &UnroutableError{
+				Exchange:  ret.Exchange,
+				RouteKey:  ret.RoutingKey,
+				ReplyCode: ret.ReplyCode,
+				ReplyText: ret.ReplyText,
+			}
+		default:
+		}
+		if !confirm.Ack {
+			return ErrPublishNacked
+		}
+		return nil
+	case <-time.After(c.timeout):
+		c.channel.Close()
+		return ErrPublishTimeout
+	}
+}
+
+// publishResult is the `result` label of the publish metrics.
+func publishResult(err error) string {
+	switch err.(type) {
+	case nil:
+		return "confirmed"
+	case *UnroutableError:
+		return "unroutable"
+	}
+	switch err {
+	case ErrPublishNacked:
+		return "nacked"
+	case ErrPublishTimeout:
+		return "timeout"
+	}
+	return "error"
+}
+
+// IsClosed reports whether the channel, or its connection, was closed.
+func (c *ConfirmChannel) IsClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close gives a pooled channel back to its AmqpConnectionManager and closes
+// any other.
+func (c *ConfirmChannel) Close() error {
+	if c.manager != nil {
+		c.manager.release(c)
+		return nil
+	}
+	return c.channel.Close()
+}
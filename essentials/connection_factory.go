@@ -1,8 +1,6 @@
 package essentials
 
 import (
-	"database/sql"
-
 	"github.com/streadway/amqp"
 )
 
@@ -10,30 +8,27 @@ type ConnectionFactory struct {
 	sess *Session
 }
 
+// Database returns a handle on the session connection pool. Closing the
+// handle does not close the pool.
 func (factory *ConnectionFactory) Database() (*DbConnection, error) {
-	dbDriverName, err := factory.sess.Require("db_driver_name")
+	db, err := factory.sess.database()
 	if err != nil {
+		DbConnectionErrors.Inc()
 		return nil, WrapError("ConnectionFactory.Database", err)
 	}
-	dbConnStr, err := factory.sess.Require("datasource")
-	if err != nil {
-		return nil, WrapError("ConnectionFactory.Database", err)
-	}
-	conn, err := sql.Open(dbDriverName, dbConnStr)
-	if err != nil {
-		return nil, WrapError("ConnectionFactory.Database", err)
-	}
-	return &DbConnection{internalConnection: conn, sess: factory.sess}, nil
+	return &DbConnection{internalConnection: db, sess: factory.sess}, nil
 }
 
+// RabbitMQ dials a dedicated connection owned by the caller. Publishers and
+// consumers inside matcha share Session.AMQP instead.
 func (factory *ConnectionFactory) RabbitMQ() (*amqp.Connection, error) {
 	rabbitMQURI, err := factory.sess.Require("rabbitmq")
 	if err != nil {
-		return nil, WrapError("ConnectionFactory.Database", err)
+		return nil, WrapError("ConnectionFactory.RabbitMQ", err)
 	}
 	conn, err := amqp.Dial(rabbitMQURI)
 	if err != nil {
-		return nil, WrapError("ConnectionFactory.Database", err)
+		return nil, WrapError("ConnectionFactory.RabbitMQ", err)
 	}
 	return conn, nil
 }
@@ -0,0 +1,119 @@
+package essentials
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/streadway/amqp"
+)
+
+// StateChangeMiddleware consumes the ChangeStatePayload messages of the
+// `statechange_queue` and applies them like `/v1/changestate`.
+type StateChangeMiddleware struct {
+	*RabbitConsumeMiddleware
+}
+
+func NewStateChangeMiddleware(sess *Session) Middleware {
+	r := &StateChangeMiddleware{}
+	r.RabbitConsumeMiddleware = NewRabbitConsumeMiddleware(sess, r)
+	return r
+}
+
+func (m *StateChangeMiddleware) OnConsume(ctx *MatchaContext, channel *amqp.Channel) error {
+	return consumeStateQueue(m.RabbitConsumeMiddleware, ctx.GetSession(), channel, "statechange_queue", "direct_exchange", "matcha statechange")
+}
+
+func (m *StateChangeMiddleware) OnDelivery(ctx *MatchaContext, channel *amqp.Channel, args *ConsumerDeliverEventArgs) error {
+	var payload ChangeStatePayload
+	err := json.Unmarshal(args.Body, &payload)
+	if err != nil {
+		_ = channel.Nack(args.DeliveryTag, false, false)
+		return err
+	}
+	if payload.MessageID == "" || payload.NewState == "" || payload.ClientTag == "" {
+		_ = channel.Nack(args.DeliveryTag, false, false)
+		return errors.New("fields `message_id`, `state` and `tag` could not be null or empty")
+	}
+	return applyDeliveredChange(ctx.GetSession(), channel, args, &payload)
+}
+
+func (m *StateChangeMiddleware) OnError(ctx *MatchaContext, args *ConsumerDeliverEventArgs, err error) {
+	ctx.GetSession().Logger().Errorln(WrapError("StateChangeMiddleware", err))
+}
+
+// ConfirmMiddleware consumes the ConfirmPayload messages of the
+// `confirm_queue`. A confirmation marks the subscription of its tag
+// Succeeded, as a `/v1/changestate` to that state would.
+type ConfirmMiddleware struct {
+	*RabbitConsumeMiddleware
+}
+
+func NewConfirmMiddleware(sess *Session) Middleware {
+	r := &ConfirmMiddleware{}
+	r.RabbitConsumeMiddleware = NewRabbitConsumeMiddleware(sess, r)
+	return r
+}
+
+func (m *ConfirmMiddleware) OnConsume(ctx *MatchaContext, channel *amqp.Channel) error {
+	return consumeStateQueue(m.RabbitConsumeMiddleware, ctx.GetSession(), channel, "confirm_queue", "unified_exchange", "matcha confirm")
+}
+
+func (m *ConfirmMiddleware) OnDelivery(ctx *MatchaContext, channel *amqp.Channel, args *ConsumerDeliverEventArgs) error {
+	var confirm ConfirmPayload
+	err := json.Unmarshal(args.Body, &confirm)
+	if err != nil {
+		_ = channel.Nack(args.DeliveryTag, false, false)
+		return err
+	}
+	if confirm.MessageID == "" || confirm.Tag == "" {
+		_ = channel.Nack(args.DeliveryTag, false, false)
+		return errors.New("fields `message_id` and `tag` could not be null or empty")
+	}
+	payload := &ChangeStatePayload{
+		MessageID: confirm.MessageID,
+		NewState:  "Succeeded",
+		ClientTag: confirm.Tag,
+	}
+	if confirm.ConfirmTime != "" {
+		payload.Remark = fmt.Sprintf("confirmed at %s", confirm.ConfirmTime)
+	}
+	return applyDeliveredChange(ctx.GetSession(), channel, args, payload)
+}
+
+func (m *ConfirmMiddleware) OnError(ctx *MatchaContext, args *ConsumerDeliverEventArgs, err error) {
+	ctx.GetSession().Logger().Errorln(WrapError("ConfirmMiddleware", err))
+}
+
+// consumeStateQueue declares and consumes the queue of the queueKey
+// parameter. Nothing is consumed when the parameter is not set.
+func consumeStateQueue(m *RabbitConsumeMiddleware, sess *Session, channel *amqp.Channel, queueKey string, exchangeKey string, consumer string) error {
+	if sess.LoadOrEmpty(queueKey) == "" {
+		return nil
+	}
+	queue, err := sess.ResolveRef("$" + queueKey)
+	if err != nil {
+		return err
+	}
+	sess.TryExchangeDeclare(exchangeKey)
+	sess.TryQueueDeclare(queueKey)
+	return m.Consume(channel, queue, consumer)
+}
+
+// applyDeliveredChange applies payload in the env of the `x-matcha-env`
+// header of the delivery, if any. Rejected changes are dropped; changes that
+// failed otherwise are requeued.
+func applyDeliveredChange(sess *Session, channel *amqp.Channel, args *ConsumerDeliverEventArgs, payload *ChangeStatePayload) error {
+	env, _ := args.Headers["x-matcha-env"].(string)
+	err := ApplyChangeState(payload, "", env, sess)
+	if err != nil {
+		if _, ok := err.(*RejectedChangeError); ok {
+			_ = channel.Nack(args.DeliveryTag, false, false)
+		} else {
+			_ = channel.Nack(args.DeliveryTag, false, true)
+		}
+		return err
+	}
+	_ = channel.Ack(args.DeliveryTag, false)
+	return nil
+}
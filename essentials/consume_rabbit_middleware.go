@@ -1,6 +1,7 @@
 package essentials
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -12,13 +13,12 @@ type RabbitConsumeMiddleware struct {
 
 	sess *Session
 
-	running bool
+	running  bool
+	stopping bool
 
-	connection *amqp.Connection
-	channel    *amqp.Channel
-
-	connCloseReceiver    chan *amqp.Error
-	channelCloseReceiver chan *amqp.Error
+	channel   *amqp.Channel
+	consumers []string
+	listeners sync.WaitGroup
 
 	mu sync.Mutex
 }
@@ -39,13 +39,68 @@ func (m *RabbitConsumeMiddleware) Execute(ctx *MatchaContext) error {
 	return nil
 }
 
+// Consume starts consuming queue on channel under the consumer tag and hands
+// the deliveries to OnDelivery. Consumers started this way are cancelled by
+// Stop.
+func (m *RabbitConsumeMiddleware) Consume(channel *amqp.Channel, queue string, consumer string) error {
+	d, err := channel.Consume(queue, consumer, false, false, false, false, make(amqp.Table))
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.consumers = append(m.consumers, consumer)
+	m.mu.Unlock()
+	m.listeners.Add(1)
+	go func() {
+		defer m.listeners.Done()
+		m.ListenDelivery(d)
+	}()
+	return nil
+}
+
+// Stop cancels the consumers, so the broker sends no more deliveries, and
+// waits for the deliveries being handled to finish before closing the
+// channel. Prefetched deliveries that were not handled yet are requeued by
+// the broker. It returns ctx.Err() when ctx is done first.
+func (m *RabbitConsumeMiddleware) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	m.stopping = true
+	m.running = false
+	channel := m.channel
+	consumers := m.consumers
+	m.consumers = nil
+	m.mu.Unlock()
+	if channel == nil {
+		return nil
+	}
+	for _, consumer := range consumers {
+		err := channel.Cancel(consumer, false)
+		if err != nil {
+			m.sess.Logger().Errorln(WrapError("RabbitConsumeMiddleware.Stop", err))
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		m.listeners.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return channel.Close()
+	case <-ctx.Done():
+		channel.Close()
+		return ctx.Err()
+	}
+}
+
 func (m *RabbitConsumeMiddleware) ListenDelivery(c <-chan amqp.Delivery) {
 	for delivery := range c {
 		args := &ConsumerDeliverEventArgs{
 			Delivery: &delivery,
 		}
 		ctx := m.getContext()
-		err := m.ConsumeMiddleware.OnDelivery(ctx, m.channel, args)
+		err := m.ConsumeMiddleware.OnDelivery(ctx, m.getChannel(), args)
 		if err != nil {
 			m.ConsumeMiddleware.OnError(ctx, args, err)
 		}
@@ -58,26 +113,29 @@ func (m *RabbitConsumeMiddleware) getContext() *MatchaContext {
 	}
 }
 
-func (m *RabbitConsumeMiddleware) ensureConnection() error {
-	connection, err := m.sess.CreateConnectionFactory().RabbitMQ()
+// ensureChannel opens the consumer channel on the shared session connection.
+// Consumer channels stay out of the publish pool.
+func (m *RabbitConsumeMiddleware) ensureChannel() error {
+	connection, err := m.sess.AMQP().Connection()
 	if err != nil {
 		return err
 	}
-	m.connection = connection
-	m.connection.NotifyClose(m.connCloseReceiver)
-	return nil
-}
-
-func (m *RabbitConsumeMiddleware) ensureChannel() error {
-	channel, err := m.connection.Channel()
+	channel, err := connection.Channel()
 	if err != nil {
 		return err
 	}
+	m.mu.Lock()
 	m.channel = channel
-	m.channel.NotifyClose(m.channelCloseReceiver)
+	m.mu.Unlock()
 	return nil
 }
 
+func (m *RabbitConsumeMiddleware) getChannel() *amqp.Channel {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.channel
+}
+
 func (m *RabbitConsumeMiddleware) setRunning(v bool) {
 	m.mu.Lock()
 	m.running = v
@@ -91,29 +149,42 @@ func (m *RabbitConsumeMiddleware) getRunning() bool {
 	return running
 }
 
+// Running reports whether the consumers are consuming. It is false while
+// they are revived after the broker closed their channel.
+func (m *RabbitConsumeMiddleware) Running() bool {
+	return m.getRunning()
+}
+
+func (m *RabbitConsumeMiddleware) getStopping() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stopping
+}
+
 func (m *RabbitConsumeMiddleware) ensureClose() {
 	m.setRunning(false)
-	if m.channel != nil {
-		m.channel.Close()
-	}
-	if m.connection != nil {
-		m.connection.Close()
+	m.mu.Lock()
+	m.consumers = nil
+	channel := m.channel
+	m.mu.Unlock()
+	if channel != nil {
+		channel.Close()
 	}
 }
 
 func (m *RabbitConsumeMiddleware) revive() error {
+	if m.getStopping() {
+		return nil
+	}
 	m.ensureClose()
 
-	err := m.ensureConnection()
+	err := m.ensureChannel()
 	if err != nil {
 		return err
 	}
-	err = m.ensureChannel()
-	if err != nil {
-		return err
-	}
-	m.listenClose()
-	err = m.ConsumeMiddleware.OnConsume(m.getContext(), m.channel)
+	channel := m.getChannel()
+	m.listenClose(channel)
+	err = m.ConsumeMiddleware.OnConsume(m.getContext(), channel)
 	if err != nil {
 		return err
 	}
@@ -130,7 +201,7 @@ func (m *RabbitConsumeMiddleware) infiniteRevive() {
 	m.setRunning(false)
 	for {
 		err := m.revive()
-		if err == nil {
+		if err == nil || m.getStopping() {
 			goto ForEnd
 		}
 		time.Sleep(time.Second * 15)
@@ -138,21 +209,14 @@ func (m *RabbitConsumeMiddleware) infiniteRevive() {
 ForEnd:
 }
 
-func (m *RabbitConsumeMiddleware) listenClose() {
-	go func() {
-		for {
-			select {
-			case <-m.connCloseReceiver:
-				m.infiniteRevive()
-			}
-		}
-	}()
+// listenClose revives the consumer once channel, or the connection under
+// it, is closed by the broker. Channels replaced by a later revive are ignored.
+func (m *RabbitConsumeMiddleware) listenClose(channel *amqp.Channel) {
+	closed := channel.NotifyClose(make(chan *amqp.Error, 1))
 	go func() {
-		for {
-			select {
-			case <-m.channelCloseReceiver:
-				m.infiniteRevive()
-			}
+		<-closed
+		if m.getChannel() == channel {
+			m.infiniteRevive()
 		}
 	}()
 }
@@ -0,0 +1,241 @@
+package essentials
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule describes a recurring schedule. The Scheduler stores the Next
+// occurrence of a cron job as its fire time.
+type CronSchedule interface {
+	Next(t time.Time) time.Time
+}
+
+type cronBounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronSeconds  = cronBounds{0, 59, nil}
+	cronMinutes  = cronBounds{0, 59, nil}
+	cronHours    = cronBounds{0, 23, nil}
+	cronDom      = cronBounds{1, 31, nil}
+	cronMonths   = cronBounds{1, 12, map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}}
+	cronWeekdays = cronBounds{0, 7, map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}}
+)
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// ParseCronExpression parses a standard 5-field cron expression, a 6-field
+// expression with a leading seconds field, one of the @yearly/@monthly/
+// @weekly/@daily/@hourly descriptors, or `@every <duration>`.
+func ParseCronExpression(expr string) (CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("cron: empty expression")
+	}
+
+	if strings.HasPrefix(expr, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(expr[len("@every "):]))
+		if err != nil {
+			return nil, fmt.Errorf("cron: invalid expression '%s' : %s", expr, err.Error())
+		}
+		if d < time.Second {
+			return nil, fmt.Errorf("cron: invalid expression '%s' : interval must be at least 1s", expr)
+		}
+		return &everySchedule{interval: d}, nil
+	}
+
+	if v, ok := cronDescriptors[strings.ToLower(expr)]; ok {
+		expr = v
+	}
+
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("cron: invalid expression '%s' : expected 5 or 6 fields, found %d", expr, len(fields))
+	}
+
+	s := &specSchedule{}
+	var err error
+	if s.second, _, err = parseCronField(fields[0], cronSeconds); err != nil {
+		return nil, fmt.Errorf("cron: invalid expression '%s' : %s", expr, err.Error())
+	}
+	if s.minute, _, err = parseCronField(fields[1], cronMinutes); err != nil {
+		return nil, fmt.Errorf("cron: invalid expression '%s' : %s", expr, err.Error())
+	}
+	if s.hour, _, err = parseCronField(fields[2], cronHours); err != nil {
+		return nil, fmt.Errorf("cron: invalid expression '%s' : %s", expr, err.Error())
+	}
+	if s.dom, s.domStar, err = parseCronField(fields[3], cronDom); err != nil {
+		return nil, fmt.Errorf("cron: invalid expression '%s' : %s", expr, err.Error())
+	}
+	if s.month, _, err = parseCronField(fields[4], cronMonths); err != nil {
+		return nil, fmt.Errorf("cron: invalid expression '%s' : %s", expr, err.Error())
+	}
+	if s.dow, s.dowStar, err = parseCronField(fields[5], cronWeekdays); err != nil {
+		return nil, fmt.Errorf("cron: invalid expression '%s' : %s", expr, err.Error())
+	}
+	// both 0 and 7 mean Sunday
+	if s.dow&(1<<7) > 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parseCronField returns the set of values matched by a comma separated list
+// of `*`, `?`, `n`, `a-b` and `x/step` terms, and whether the field was a wildcard.
+func parseCronField(field string, r cronBounds) (uint64, bool, error) {
+	var bits uint64
+	star := false
+	for _, term := range strings.Split(field, ",") {
+		if term == "" {
+			return 0, false, fmt.Errorf("empty term in field '%s'", field)
+		}
+		rangePart, step := term, 1
+		if i := strings.Index(term, "/"); i >= 0 {
+			n, err := strconv.Atoi(term[i+1:])
+			if err != nil || n <= 0 {
+				return 0, false, fmt.Errorf("invalid step in '%s'", term)
+			}
+			rangePart, step = term[:i], n
+		}
+
+		var low, high int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			low, high = r.min, r.max
+			if step == 1 {
+				star = true
+			}
+		case strings.Contains(rangePart, "-"):
+			parts := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if low, err = cronValue(parts[0], r); err != nil {
+				return 0, false, err
+			}
+			if high, err = cronValue(parts[1], r); err != nil {
+				return 0, false, err
+			}
+		default:
+			v, err := cronValue(rangePart, r)
+			if err != nil {
+				return 0, false, err
+			}
+			low, high = v, v
+			if step > 1 {
+				high = r.max
+			}
+		}
+		if low > high {
+			return 0, false, fmt.Errorf("invalid range '%s'", term)
+		}
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, star, nil
+}
+
+func cronValue(s string, r cronBounds) (int, error) {
+	if r.names != nil {
+		if v, ok := r.names[strings.ToLower(s)]; ok {
+			return v, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s'", s)
+	}
+	if v < r.min || v > r.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", v, r.min, r.max)
+	}
+	return v, nil
+}
+
+type specSchedule struct {
+	second, minute, hour, dom, month, dow uint64
+	domStar, dowStar                      bool
+}
+
+// Next returns the first matching time strictly after t, or the zero time if
+// nothing matches within the next five years (e.g. `0 0 30 2 *`).
+func (s *specSchedule) Next(t time.Time) time.Time {
+	t = t.Add(time.Second - time.Duration(t.Nanosecond())*time.Nanosecond)
+	yearLimit := t.Year() + 5
+
+WRAP:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for 1<<uint(t.Month())&s.month == 0 {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		if t.Month() == time.January {
+			goto WRAP
+		}
+	}
+
+	for !s.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		if t.Day() == 1 {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Hour())&s.hour == 0 {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		if t.Hour() == 0 {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Minute())&s.minute == 0 {
+		t = t.Truncate(time.Minute).Add(time.Minute)
+		if t.Minute() == 0 {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Second())&s.second == 0 {
+		t = t.Truncate(time.Second).Add(time.Second)
+		if t.Second() == 0 {
+			goto WRAP
+		}
+	}
+
+	return t
+}
+
+// dayMatches applies the usual cron rule: when both day-of-month and
+// day-of-week are restricted, a day matching either one fires.
+func (s *specSchedule) dayMatches(t time.Time) bool {
+	domMatch := 1<<uint(t.Day())&s.dom > 0
+	dowMatch := 1<<uint(t.Weekday())&s.dow > 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+type everySchedule struct {
+	interval time.Duration
+}
+
+func (s *everySchedule) Next(t time.Time) time.Time {
+	return t.Add(s.interval - time.Duration(t.Nanosecond())*time.Nanosecond)
+}
@@ -0,0 +1,95 @@
+package essentials
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronExpressionRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 0 *",
+		"* * * 13 *",
+		"* * * * 8",
+		"60 * * * * *",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"1,,2 * * * *",
+		"* * * * funday",
+		"* * * smarch *",
+		"@every 500ms",
+		"@every soon",
+		"@fortnightly",
+	} {
+		_, err := ParseCronExpression(expr)
+		if err == nil {
+			t.Errorf("%q parsed", expr)
+		}
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	at := func(value string) time.Time {
+		v, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	// 2026-01-01 is a Thursday.
+	for _, c := range []struct {
+		expr string
+		from string
+		want string
+	}{
+		{"*/15 * * * *", "2026-01-01T00:00:00Z", "2026-01-01T00:15:00Z"},
+		{"0 9-17/4 * * *", "2026-01-01T10:00:00Z", "2026-01-01T13:00:00Z"},
+		{"0 9-17/4 * * *", "2026-01-01T17:00:00Z", "2026-01-02T09:00:00Z"},
+		{"10,40 * * * *", "2026-01-01T00:10:00Z", "2026-01-01T00:40:00Z"},
+		{"*/10 * * * * *", "2026-01-01T00:00:05Z", "2026-01-01T00:00:10Z"},
+		{"30 8 * * mon-fri", "2026-01-01T09:00:00Z", "2026-01-02T08:30:00Z"},
+		{"30 8 * * MON-FRI", "2026-01-02T09:00:00Z", "2026-01-05T08:30:00Z"},
+		{"0 0 1 jan,jul *", "2026-01-01T00:00:00Z", "2026-07-01T00:00:00Z"},
+		{"0 0 * * 7", "2026-01-01T00:00:00Z", "2026-01-04T00:00:00Z"},
+		{"0 0 * * 0", "2026-01-01T00:00:00Z", "2026-01-04T00:00:00Z"},
+		{"0 0 * * sun", "2026-01-01T00:00:00Z", "2026-01-04T00:00:00Z"},
+		// Day-of-month and day-of-week both restricted: either one fires.
+		{"0 0 13 * fri", "2026-01-01T00:00:00Z", "2026-01-02T00:00:00Z"},
+		{"0 0 13 * fri", "2026-01-09T00:00:00Z", "2026-01-13T00:00:00Z"},
+		// Only one restricted: that one decides.
+		{"0 0 13 * *", "2026-01-01T00:00:00Z", "2026-01-13T00:00:00Z"},
+		{"0 0 * * fri", "2026-01-02T00:00:00Z", "2026-01-09T00:00:00Z"},
+		{"0 0 29 2 *", "2026-01-01T00:00:00Z", "2028-02-29T00:00:00Z"},
+		{"@daily", "2026-01-01T00:00:00Z", "2026-01-02T00:00:00Z"},
+		{"@weekly", "2026-01-01T00:00:00Z", "2026-01-04T00:00:00Z"},
+		{"@every 90s", "2026-01-01T00:00:00.5Z", "2026-01-01T00:01:30Z"},
+		{"@every 1h", "2026-01-01T10:20:30Z", "2026-01-01T11:20:30Z"},
+	} {
+		schedule, err := ParseCronExpression(c.expr)
+		if err != nil {
+			t.Errorf("%q: %v", c.expr, err)
+			continue
+		}
+		if next := schedule.Next(at(c.from)); !next.Equal(at(c.want)) {
+			t.Errorf("%q after %s: %s, want %s", c.expr, c.from, next.Format(time.RFC3339), c.want)
+		}
+	}
+}
+
+func TestCronScheduleNextNeverFires(t *testing.T) {
+	for _, expr := range []string{"0 0 30 2 *", "0 0 31 4,6,9,11 *"} {
+		schedule, err := ParseCronExpression(expr)
+		if err != nil {
+			t.Fatalf("%q: %v", expr, err)
+		}
+		if next := schedule.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); !next.IsZero() {
+			t.Errorf("%q fires at %s", expr, next)
+		}
+	}
+}
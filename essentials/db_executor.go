@@ -60,7 +60,7 @@ func (m *MockDbExecutor) ExecScript(name string, args ...interface{}) (int64, er
 	return m.Exec(sql, args...)
 }
 func (m *MockDbExecutor) Query(query string, args ...interface{}) (*sql.Rows, error) {
-	m.sess.Logger().Infoln(query)
+	m.sess.Logger().Debugln(query)
 	return m.internalConnection.Query(query, args...)
 }
 func (m *MockDbExecutor) QueryRow(query string, args ...interface{}) *sql.Row {
@@ -10,16 +10,15 @@ type DbConnection struct {
 	internalConnection *sql.DB
 }
 
+// Close releases the handle. The underlying *sql.DB is shared by the session
+// and stays open.
 func (conn *DbConnection) Close() error {
-	err := conn.internalConnection.Close()
-	if err != nil {
-		return err
-	}
 	return nil
 }
 
 func (conn *DbConnection) Exec(query string, args ...interface{}) (int64, error) {
-	conn.sess.Logger().Infoln(query)
+	query, args = conn.sess.Dialect().Rebind(query, args)
+	conn.sess.Logger().Debugln(query)
 	result, err := conn.internalConnection.Exec(query, args...)
 	if err != nil {
 		return 0, err
@@ -44,7 +43,8 @@ func (conn *DbConnection) ExecScript(name string, args ...interface{}) (int64, e
 }
 
 func (conn *DbConnection) Query(query string, args ...interface{}) (*sql.Rows, error) {
-	conn.sess.Logger().Infoln(query)
+	query, args = conn.sess.Dialect().Rebind(query, args)
+	conn.sess.Logger().Debugln(query)
 	return conn.internalConnection.Query(query, args...)
 }
 
@@ -59,6 +59,7 @@ func (conn *DbConnection) BeginTx(level sql.IsolationLevel) (*DbTransaction, err
 	})
 
 	if err != nil {
+		DbConnectionErrors.Inc()
 		return nil, err
 	}
 
@@ -70,6 +71,7 @@ func (conn *DbConnection) BeginTx(level sql.IsolationLevel) (*DbTransaction, err
 }
 
 func (conn *DbConnection) QueryRow(query string, args ...interface{}) *sql.Row {
+	query, args = conn.sess.Dialect().Rebind(query, args)
 	return conn.GetInternalConnection().QueryRow(query, args...)
 }
 
@@ -14,7 +14,8 @@ func (transaction *DbTransaction) Rollback() error {
 	return transaction.internalTransaction.Rollback()
 }
 func (transaction *DbTransaction) Exec(query string, args ...interface{}) (int64, error) {
-	transaction.sess.Logger().Infoln(query)
+	query, args = transaction.sess.Dialect().Rebind(query, args)
+	transaction.sess.Logger().Debugln(query)
 	result, err := transaction.internalTransaction.Exec(query, args...)
 	if err != nil {
 		return 0, err
@@ -39,7 +40,8 @@ func (transaction *DbTransaction) ExecScript(name string, args ...interface{}) (
 }
 
 func (transaction *DbTransaction) Query(query string, args ...interface{}) (*sql.Rows, error) {
-	transaction.sess.Logger().Infoln(query)
+	query, args = transaction.sess.Dialect().Rebind(query, args)
+	transaction.sess.Logger().Debugln(query)
 	return transaction.internalTransaction.Query(query, args...)
 }
 func (transaction *DbTransaction) GetInternalTx() *sql.Tx {
@@ -50,6 +52,7 @@ func (transaction *DbTransaction) Commit() error {
 }
 
 func (transaction *DbTransaction) QueryRow(query string, args ...interface{}) *sql.Row {
+	query, args = transaction.sess.Dialect().Rebind(query, args)
 	return transaction.GetInternalTx().QueryRow(query, args...)
 }
 
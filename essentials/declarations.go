@@ -3,6 +3,7 @@ package essentials
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/streadway/amqp"
@@ -21,16 +22,11 @@ func NewDeclarationsConfig() *DeclarationsConfig {
 }
 
 func (cnf *DeclarationsConfig) Fanout(factory *ConnectionFactory) error {
-	conn, err := factory.RabbitMQ()
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-	channel, err := conn.Channel()
-	if err != nil {
-		return err
-	}
-	err = channel.ExchangeDeclare("declaration@matcha.fanout", "fanout", true, false, false, false, make(amqp.Table))
+	return factory.sess.AMQP().WithChannel(cnf.fanout)
+}
+
+func (cnf *DeclarationsConfig) fanout(channel *amqp.Channel) error {
+	err := channel.ExchangeDeclare("declaration@matcha.fanout", "fanout", true, false, false, false, make(amqp.Table))
 	if err != nil {
 		return err
 	}
@@ -53,6 +49,10 @@ type DeclarationMap struct {
 	sess      *Session
 	exchanges sync.Map
 	queues    sync.Map
+
+	// configured holds the JSON of every declaration as configured, keyed
+	// "exchange:<key>" or "queue:<key>", to tell changed ones on reload.
+	configured sync.Map
 }
 
 func (m *DeclarationMap) GetExchangeMap() *sync.Map {
@@ -64,6 +64,7 @@ func (m *DeclarationMap) ConfigureQueueDeclarations(source map[string]*Queue) {
 		return
 	}
 	for k, v := range source {
+		m.configured.Store("queue:"+k, declarationJSON(v))
 		m.queues.Store(k, v.Refill())
 	}
 }
@@ -73,10 +74,64 @@ func (m *DeclarationMap) ConfigureExchangeDeclarations(source map[string]*Exchan
 		return
 	}
 	for k, v := range source {
+		m.configured.Store("exchange:"+k, declarationJSON(v))
 		m.exchanges.Store(k, v.Refill())
 	}
 }
 
+// Reconfigure stores the declarations of cnf that are new or differ from the
+// configured ones and returns their exchange and queue keys. Declarations
+// missing from cnf are kept.
+func (m *DeclarationMap) Reconfigure(cnf *DeclarationsConfig) ([]string, []string) {
+	exchanges := make([]string, 0)
+	queues := make([]string, 0)
+	if cnf == nil {
+		return exchanges, queues
+	}
+	for k, v := range cnf.Exchanges {
+		if m.changed("exchange:"+k, v) {
+			m.ConfigureExchangeDeclarations(map[string]*Exchange{k: v})
+			exchanges = append(exchanges, k)
+		}
+	}
+	for k, v := range cnf.Queues {
+		if m.changed("queue:"+k, v) {
+			m.ConfigureQueueDeclarations(map[string]*Queue{k: v})
+			queues = append(queues, k)
+		}
+	}
+	sort.Strings(exchanges)
+	sort.Strings(queues)
+	return exchanges, queues
+}
+
+// Restore stores the exchanges and queues of keys again as configured, so
+// that the names and arguments they take from parameters are resolved anew.
+func (m *DeclarationMap) Restore(exchanges []string, queues []string) {
+	for _, k := range exchanges {
+		var e Exchange
+		if v, ok := m.configured.Load("exchange:" + k); ok && json.Unmarshal([]byte(v.(string)), &e) == nil {
+			m.exchanges.Store(k, e.Refill())
+		}
+	}
+	for _, k := range queues {
+		var q Queue
+		if v, ok := m.configured.Load("queue:" + k); ok && json.Unmarshal([]byte(v.(string)), &q) == nil {
+			m.queues.Store(k, q.Refill())
+		}
+	}
+}
+
+func (m *DeclarationMap) changed(key string, declaration interface{}) bool {
+	v, ok := m.configured.Load(key)
+	return !ok || v.(string) != declarationJSON(declaration)
+}
+
+func declarationJSON(declaration interface{}) string {
+	body, _ := json.Marshal(declaration)
+	return string(body)
+}
+
 func (m *DeclarationMap) completeRefString(key string, ref string) string {
 	if ref == "$" {
 		return fmt.Sprintf("$%s", key)
@@ -168,34 +223,6 @@ func (m *DeclarationMap) QueueDeclare(channel *amqp.Channel, key string) (amqp.Q
 	return queue, nil
 }
 
-func (m *DeclarationMap) TryExchangeDeclare(factory *ConnectionFactory, key string) {
-	conn, err := factory.RabbitMQ()
-	if err != nil {
-		return
-	}
-	defer conn.Close()
-	channel, err := conn.Channel()
-	if err != nil {
-		return
-	}
-	defer channel.Close()
-	_ = m.ExchangeDeclare(channel, key)
-}
-
-func (m *DeclarationMap) TryQueueDeclare(factory *ConnectionFactory, key string) {
-	conn, err := factory.RabbitMQ()
-	if err != nil {
-		return
-	}
-	defer conn.Close()
-	channel, err := conn.Channel()
-	if err != nil {
-		return
-	}
-	defer channel.Close()
-	_, _ = m.QueueDeclare(channel, key)
-}
-
 type QueueBinding struct {
 	RouteKey  string     `json:"route_key"`
 	Exchange  string     `json:"exchange"`
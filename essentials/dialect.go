@@ -0,0 +1,103 @@
+package essentials
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Dialect is the SQL flavour of the matcha database, chosen from
+// `db_driver_name`. Scripts are written for PostgreSQL with $n placeholders;
+// the other dialects override scripts by name from
+// resources/scripts/<dialect> and have their own resources/migrations/<dialect>.
+type Dialect string
+
+const (
+	DialectPostgres Dialect = "postgres"
+	DialectSQLite   Dialect = "sqlite"
+	DialectMySQL    Dialect = "mysql"
+)
+
+var placeholderRegexp = regexp.MustCompile(`\$(\d+)`)
+
+// DialectOf maps a database/sql driver name to its dialect. An empty name is
+// PostgreSQL.
+func DialectOf(driverName string) (Dialect, error) {
+	switch driverName {
+	case "", "postgres", "pgx":
+		return DialectPostgres, nil
+	case "sqlite3", "sqlite":
+		return DialectSQLite, nil
+	case "mysql":
+		return DialectMySQL, nil
+	}
+	return "", fmt.Errorf("unsupported db_driver_name '%s'", driverName)
+}
+
+// resourceDir is the sub directory of resources/scripts and
+// resources/migrations holding the scripts of the dialect.
+func (d Dialect) resourceDir() string {
+	if d == DialectPostgres {
+		return ""
+	}
+	return string(d)
+}
+
+// Schema returns the schema the tables live in. SQLite has no schemas, so the
+// `dbprefix` is ignored and the tables go to its main database.
+func (d Dialect) Schema(prefix string) string {
+	if d == DialectSQLite {
+		return "main"
+	}
+	return prefix
+}
+
+// DataSource adds the connection settings the scripts rely on unless the
+// data source sets them itself: MySQL sessions run in ANSI sql_mode so that
+// double quotes delimit identifiers, and SQLite transactions take the write
+// lock when they begin and wait for it instead of failing with SQLITE_BUSY.
+func (d Dialect) DataSource(dsn string) string {
+	switch d {
+	case DialectMySQL:
+		if !strings.Contains(dsn, "sql_mode=") {
+			dsn = appendDataSourceParam(dsn, "sql_mode=CONCAT(@@sql_mode,%27,ANSI%27)")
+		}
+	case DialectSQLite:
+		if !strings.Contains(dsn, "_txlock=") {
+			dsn = appendDataSourceParam(dsn, "_txlock=immediate")
+		}
+		if !strings.Contains(dsn, "_busy_timeout=") && !strings.Contains(dsn, "_timeout=") {
+			dsn = appendDataSourceParam(dsn, "_busy_timeout=5000")
+		}
+	}
+	return dsn
+}
+
+func appendDataSourceParam(dsn string, param string) string {
+	if strings.Contains(dsn, "?") {
+		return dsn + "&" + param
+	}
+	return dsn + "?" + param
+}
+
+// Rebind rewrites the $n placeholders of query for the dialect. SQLite takes
+// them as ?n; MySQL only has positional ?, so args are repeated and reordered
+// to follow the placeholders.
+func (d Dialect) Rebind(query string, args []interface{}) (string, []interface{}) {
+	switch d {
+	case DialectSQLite:
+		return placeholderRegexp.ReplaceAllString(query, "?$1"), args
+	case DialectMySQL:
+		bound := make([]interface{}, 0, len(args))
+		query = placeholderRegexp.ReplaceAllStringFunc(query, func(placeholder string) string {
+			n, err := strconv.Atoi(placeholder[1:])
+			if err == nil && n >= 1 && n <= len(args) {
+				bound = append(bound, args[n-1])
+			}
+			return "?"
+		})
+		return query, bound
+	}
+	return query, args
+}
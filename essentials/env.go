@@ -0,0 +1,96 @@
+package essentials
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// EnvHeader tells the environment a request belongs to.
+const EnvHeader = "X-matcha-Env"
+
+// Envs are the environments a message can belong to.
+var Envs = []string{"dev", "staging", "pro"}
+
+// ParseEnv validates env, ignoring case. An empty env stays empty.
+func ParseEnv(env string) (string, error) {
+	env = strings.ToLower(strings.TrimSpace(env))
+	if env == "" {
+		return "", nil
+	}
+	for _, e := range Envs {
+		if env == e {
+			return env, nil
+		}
+	}
+	return "", fmt.Errorf("env `%s` should be one of `%s`", env, strings.Join(Envs, "`, `"))
+}
+
+// RequestEnv returns the `X-matcha-Env` header of request, or fallback when
+// the request has none.
+func RequestEnv(request *http.Request, fallback string) string {
+	if request != nil {
+		if env := request.Header.Get(EnvHeader); env != "" {
+			return env
+		}
+	}
+	return fallback
+}
+
+// SETEnv makes the session serve env only. An empty env serves every
+// environment.
+func (sess *Session) SETEnv(env string) error {
+	env, err := ParseEnv(env)
+	if err != nil {
+		return err
+	}
+	sess.env = env
+	return nil
+}
+
+// Env returns the environment the session serves, "" for all of them.
+func (sess *Session) Env() string {
+	return sess.env
+}
+
+// ResolveEnv validates the env of a request against the env the session
+// serves, which it defaults to. It returns "" when neither is set.
+func (sess *Session) ResolveEnv(env string) (string, error) {
+	env, err := ParseEnv(env)
+	if err != nil {
+		return "", err
+	}
+	if sess.env == "" {
+		return env, nil
+	}
+	if env == "" {
+		return sess.env, nil
+	}
+	if env != sess.env {
+		return "", fmt.Errorf("env `%s` is not served by this agent, which serves `%s`", env, sess.env)
+	}
+	return env, nil
+}
+
+// RequireEnv is ResolveEnv for messages, which must belong to an env.
+func (sess *Session) RequireEnv(env string) (string, error) {
+	env, err := sess.ResolveEnv(env)
+	if err != nil {
+		return "", err
+	}
+	if env == "" {
+		return "", fmt.Errorf("HTTP header `%s` should be one of `%s`", EnvHeader, strings.Join(Envs, "`, `"))
+	}
+	return env, nil
+}
+
+// EnvExchange returns the exchange the deliveries of env go to. With the
+// `env_exchange` parameter set, e.g. to "{exchange}.{env}", every env has its
+// own exchanges; otherwise exchange is used as is.
+func (sess *Session) EnvExchange(env string, exchange string) string {
+	format := sess.LoadOrEmpty("env_exchange")
+	if format == "" || env == "" || exchange == "" {
+		return exchange
+	}
+	return strings.Replace(strings.Replace(format, "{exchange}", exchange, -1), "{env}", env, -1)
+}
@@ -2,6 +2,7 @@ package essentials
 
 import (
 	"database/sql"
+	"fmt"
 	"time"
 )
 
@@ -24,7 +25,7 @@ func (p *FailedMessageProcessor) Process() error {
 		return WrapError("FailedMessageProcessor", err)
 	}
 	defer transact.Rollback()
-	row, err := transact.QueryScriptRow("FindOneFailedMessage", time.Now().Add(time.Minute*-2).Unix())
+	row, err := transact.QueryScriptRow("FindOneFailedMessage", time.Now().Add(time.Minute*-2).Unix(), p.sess.Env())
 	if err != nil {
 		return WrapError("FailedMessageProcessor", err)
 	}
@@ -48,7 +49,7 @@ func (p *FailedMessageProcessor) Process() error {
 		return WrapError("FailedMessageProcessor", err)
 	}
 
-	p.sess.Logger().Infof("message %s failed", msg.ID)
+	LogWith(p.sess.Logger(), Fields{"message_id": msg.ID, "processor": "FailedMessageProcessor"}).Infoln(fmt.Sprintf("message %s failed", msg.ID))
 
 	return ProcessorWaitNext
 }
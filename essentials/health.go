@@ -0,0 +1,89 @@
+package essentials
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	HealthUp   = "up"
+	HealthDown = "down"
+)
+
+// HealthCheck is the result of one check of a HealthReport.
+type HealthCheck struct {
+	Status  string      `json:"status"`
+	Error   string      `json:"error,omitempty"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// HealthReport is the breakdown served by `/v1/health/live` and
+// `/v1/health/ready`. It is down as soon as one of its checks is.
+type HealthReport struct {
+	Status string                  `json:"status"`
+	Checks map[string]*HealthCheck `json:"checks"`
+}
+
+func NewHealthReport() *HealthReport {
+	return &HealthReport{Status: HealthUp, Checks: make(map[string]*HealthCheck)}
+}
+
+// Add records the check name, down when err is not nil.
+func (r *HealthReport) Add(name string, details interface{}, err error) {
+	check := &HealthCheck{Status: HealthUp, Details: details}
+	if err != nil {
+		check.Status = HealthDown
+		check.Error = err.Error()
+		r.Status = HealthDown
+	}
+	r.Checks[name] = check
+}
+
+func (r *HealthReport) Up() bool {
+	return r.Status == HealthUp
+}
+
+// PingDatabase checks that the session database answers within ctx.
+func (sess *Session) PingDatabase(ctx context.Context) error {
+	db, err := sess.database()
+	if err != nil {
+		return err
+	}
+	return db.PingContext(ctx)
+}
+
+// AMQPHealthError reports the shared AMQP connection as down while it is not
+// connected.
+func AMQPHealthError(h AmqpHealth) error {
+	if h.Connected && !h.Reconnecting {
+		return nil
+	}
+	if h.LastError != "" {
+		return errors.New("not connected: " + h.LastError)
+	}
+	return errors.New("not connected")
+}
+
+// LoopHealth is the state of a background loop: whether it runs and when it
+// last went round.
+type LoopHealth struct {
+	Running   bool   `json:"running"`
+	LastRound string `json:"last_round,omitempty"`
+}
+
+// CheckLoop reports a loop as down when it is not running or did not go
+// round for longer than staleAfter.
+func CheckLoop(running bool, lastRound time.Time, staleAfter time.Duration) (LoopHealth, error) {
+	h := LoopHealth{Running: running}
+	if !lastRound.IsZero() {
+		h.LastRound = FormatTime(lastRound)
+	}
+	if !running {
+		return h, errors.New("not running")
+	}
+	if !lastRound.IsZero() && time.Since(lastRound) > staleAfter {
+		return h, errors.New("no round since " + h.LastRound)
+	}
+	return h, nil
+}
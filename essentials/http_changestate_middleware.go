@@ -3,9 +3,18 @@ package essentials
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
 )
 
 func ExecuteChangeState(content []byte, sess *Session) error {
+	return ExecuteClientChangeState(content, "", "", sess)
+}
+
+// ExecuteClientChangeState changes the state of the subscription of the
+// verified client, which must match the `tag` of the payload when set. env,
+// when set, takes precedence over the `env` of the payload; the change is
+// rejected when the message belongs to another env.
+func ExecuteClientChangeState(content []byte, client string, env string, sess *Session) error {
 	// content, err := ioutil.ReadAll(m.Body)
 	// if err != nil {
 	// 	return err
@@ -15,6 +24,25 @@ func ExecuteChangeState(content []byte, sess *Session) error {
 	if err != nil {
 		return err
 	}
+	return ApplyChangeState(&payload, client, env, sess)
+}
+
+// ApplyChangeState records the new state of the subscription of payload, for
+// the HTTP endpoint and the statechange and confirm consumers alike. Changes
+// that can never be applied, such as one for a message of another env, are
+// reported as a *RejectedChangeError.
+func ApplyChangeState(payload *ChangeStatePayload, client string, env string, sess *Session) error {
+	err := payload.checkReceiver(client)
+	if err != nil {
+		return &RejectedChangeError{err}
+	}
+	if env == "" {
+		env = payload.Env
+	}
+	payload.Env, err = sess.ResolveEnv(env)
+	if err != nil {
+		return &RejectedChangeError{err}
+	}
 	conn, err := sess.CreateConnectionFactory().Database()
 	if err != nil {
 		return err
@@ -24,6 +52,16 @@ func ExecuteChangeState(content []byte, sess *Session) error {
 	if err != nil {
 		return err
 	}
+	defer transact.Rollback()
+	if payload.Env != "" {
+		msg, err := FindOneMessage(payload.MessageID, false, transact)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if msg != nil && msg.Env != "" && msg.Env != payload.Env {
+			return &RejectedChangeError{fmt.Errorf("message %s belongs to env `%s`, not `%s`", payload.MessageID, msg.Env, payload.Env)}
+		}
+	}
 	sub, err := FineOneLockSubscription("UNKNOWN", payload.MessageID, payload.ClientTag, transact)
 	if err != nil {
 		return err
@@ -73,3 +111,13 @@ func ExecuteChangeState(content []byte, sess *Session) error {
 
 	return nil
 }
+
+// RejectedChangeError is a state change that is refused whenever it is
+// retried.
+type RejectedChangeError struct {
+	err error
+}
+
+func (e *RejectedChangeError) Error() string {
+	return e.err.Error()
+}
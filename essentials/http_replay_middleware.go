@@ -0,0 +1,243 @@
+package essentials
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+type replayTarget struct {
+	exchange string
+	routeKey string
+}
+
+// ExecuteReplayMessage republishes the stored content of a message to its
+// subscriptions. Subscriptions created by a state change report have no
+// exchange of their own (UNKNOWN); those are replayed through the exchange and
+// route key of the event, once per distinct destination. The env of the
+// `X-matcha-Env` header, or of the body, must be the env of the message, and a
+// signed client can only replay the messages it published.
+func ExecuteReplayMessage(content []byte, request *http.Request, sess *Session) error {
+	var payload ReplayPayload
+	err := json.Unmarshal(content, &payload)
+	if err != nil {
+		return err
+	}
+	if payload.MessageID == "" {
+		return fmt.Errorf("field `%s` could not be null or empty", "message_id")
+	}
+	env, err := sess.ResolveEnv(RequestEnv(request, payload.Env))
+	if err != nil {
+		return err
+	}
+	remark := "replayed"
+	if payload.Remark != "" {
+		remark = fmt.Sprintf("%s : %s", remark, payload.Remark)
+	}
+
+	conn, err := sess.CreateConnectionFactory().Database()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	transact, err := conn.BeginTx(sql.LevelReadCommitted)
+	if err != nil {
+		return err
+	}
+	defer transact.Rollback()
+
+	msg, err := FindOneMessage(payload.MessageID, true, transact)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("message %s not found", payload.MessageID)
+	} else if err != nil {
+		return err
+	}
+	if msg.State == MessageCancelled {
+		return fmt.Errorf("message %s is %s and could not be replayed", msg.ID, msg.StateName)
+	}
+	_, err = sess.ResolveEnv(msg.Env)
+	if err != nil {
+		return err
+	}
+	if env != "" && msg.Env != "" && msg.Env != env {
+		return fmt.Errorf("message %s belongs to env `%s`, not `%s`", msg.ID, msg.Env, env)
+	}
+	if client := RequestClient(request); client != "" && msg.Publisher != client {
+		return fmt.Errorf("client %s can not replay message %s of publisher %s", client, msg.ID, msg.Publisher)
+	}
+
+	subs, err := msg.FetchSubscriptions(transact)
+	if err != nil {
+		return err
+	}
+	subs, err = filterReplaySubscriptions(subs, payload.Tags)
+	if err != nil {
+		return err
+	}
+	if len(subs) == 0 {
+		return fmt.Errorf("message %s has no subscription to replay", msg.ID)
+	}
+
+	deliveryMsg := &DeliveryMessage{
+		MessageID:   msg.ID,
+		MessageType: msg.MessageType,
+		Content:     msg.Content,
+		PublishTime: time.Now().Unix(),
+		Extensions:  make(map[string]string),
+	}
+	deliveryMsg.Extensions["x-matcha-tag"] = msg.Publisher
+	deliveryMsg.Extensions["x-matcha-replay"] = FormatTime(time.Now())
+	deliveryMsg.Extensions["x-matcha-env"] = msg.Env
+
+	var event *Event
+	targets := make([]replayTarget, 0, len(subs))
+	published := make([]*Subscription, 0, len(subs))
+	for _, sub := range subs {
+		target := replayTarget{exchange: sub.Exchange, routeKey: sub.RouteKey}
+		if sub.Exchange == "UNKNOWN" || sub.Exchange == "" {
+			if event == nil {
+				event, err = FindOneEvent(msg.ID, transact)
+				if err == sql.ErrNoRows {
+					return fmt.Errorf("subscription %s of message %s has no exchange to replay to", sub.ReceiverTag, msg.ID)
+				} else if err != nil {
+					return err
+				}
+				deliveryMsg.Extensions["x-matcha-routekey"] = event.RouteKey
+				if event.Queue != "" {
+					deliveryMsg.Extensions["x-matcha-queue"] = event.Queue
+				}
+			}
+			target = replayTarget{exchange: event.Exchange, routeKey: event.RouteKey}
+			if event.Queue != "" {
+				target.routeKey = event.Queue
+			}
+		} else {
+			published = append(published, sub)
+		}
+		target.exchange = sess.EnvExchange(msg.Env, target.exchange)
+		if !containsReplayTarget(targets, target) {
+			targets = append(targets, target)
+		}
+
+		err = sub.Reset("Scheduled", transact)
+		if err != nil {
+			return err
+		}
+		flow := &Flow{
+			SubscriptionID: sub.ID,
+			StateName:      "Scheduled",
+			Remark:         remark,
+		}
+		_, err = flow.Append(transact)
+		if err != nil {
+			return err
+		}
+	}
+
+	if msg.State == MessageScheduled || msg.State == MessageProcessing {
+		err = msg.AppendLog(remark, transact)
+	} else {
+		err = msg.ChangeStateWithRemark(MessageProcessing, remark, transact)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = publishReplay(sess, deliveryMsg, targets, msg.Trace())
+	if err != nil {
+		return err
+	}
+
+	// subscriptions with their own exchange were delivered directly, as the
+	// scheduler does; the others wait for the receiver to report back.
+	for _, sub := range published {
+		err = sub.ChangeState("Published", transact)
+		if err != nil {
+			return err
+		}
+		flow := &Flow{
+			SubscriptionID: sub.ID,
+			StateName:      "Published",
+			Remark:         remark,
+		}
+		_, err = flow.Append(transact)
+		if err != nil {
+			return err
+		}
+	}
+
+	return transact.Commit()
+}
+
+func filterReplaySubscriptions(subs []*Subscription, tags []string) ([]*Subscription, error) {
+	if len(tags) == 0 {
+		return subs, nil
+	}
+	result := make([]*Subscription, 0, len(tags))
+	for _, tag := range tags {
+		found := false
+		for _, sub := range subs {
+			if strings.EqualFold(sub.ReceiverTag, tag) {
+				result = append(result, sub)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("subscription %s not found", tag)
+		}
+	}
+	return result, nil
+}
+
+func containsReplayTarget(targets []replayTarget, target replayTarget) bool {
+	for _, t := range targets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
+// publishReplay publishes deliveryMsg to every target, each delivery a span
+// of trace.
+func publishReplay(sess *Session, deliveryMsg *DeliveryMessage, targets []replayTarget, trace TraceContext) error {
+	channel, err := sess.AMQP().AcquireChannel()
+	if err != nil {
+		return err
+	}
+	defer channel.Close()
+
+	for _, target := range targets {
+		LogWith(sess.Logger(), Fields{"message_id": deliveryMsg.MessageID}).Infoln(fmt.Sprintf("Replay : %s ; Exchange : %s ; Key : %s ", deliveryMsg.MessageID, target.exchange, target.routeKey))
+		span := sess.StartSpan("publish replay", trace)
+		span.SetAttribute("message_id", deliveryMsg.MessageID).SetAttribute("exchange", target.exchange).SetAttribute("route_key", target.routeKey)
+		err = publishReplayTarget(sess, channel, deliveryMsg, target, span.Context())
+		span.Finish(err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func publishReplayTarget(sess *Session, channel *ConfirmChannel, deliveryMsg *DeliveryMessage, target replayTarget, trace TraceContext) error {
+	headers := make(amqp.Table)
+	trace.Inject(deliveryMsg.Extensions, headers)
+	body, err := json.Marshal(deliveryMsg)
+	if err != nil {
+		return err
+	}
+	p := amqp.Publishing{
+		Headers: headers,
+		Body:    body,
+	}
+	if ttl := sess.LoadOrEmpty("message_ttl"); ttl != "" {
+		p.Expiration = ttl
+	}
+	return channel.Publish(target.exchange, target.routeKey, p)
+}
@@ -0,0 +1,59 @@
+//go:build cgo
+// +build cgo
+
+package essentials
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReplayMessageChecksEnvAndPublisher(t *testing.T) {
+	sess := newSQLiteSession(t)
+	tx := beginTx(t, sess)
+	msg, err := AppendMessage(&Payload{
+		Env:           "dev",
+		MessageType:   "order.created",
+		Content:       "{}",
+		Subscriptions: []*SubscriptionPayload{{Tag: "billing", Exchange: "matcha", RouteKey: "billing"}},
+		Headers:       map[string]interface{}{"x-matcha-client": "shop"},
+	}, nil, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a `rabbitmq` parameter an accepted replay fails to publish.
+	body := `{"message_id":"` + msg.ID + `"}`
+	for _, c := range []struct {
+		env      string
+		client   string
+		rejected string
+	}{
+		{"pro", "", "belongs to env"},
+		{"dev", "billing", "can not replay"},
+		{"dev", "", ""},
+		{"", "shop", ""},
+	} {
+		request := httptest.NewRequest("POST", "/v1/message/replay", strings.NewReader(body))
+		if c.env != "" {
+			request.Header.Set(EnvHeader, c.env)
+		}
+		if c.client != "" {
+			request = WithRequestClient(request, c.client)
+		}
+		err = ExecuteReplayMessage([]byte(body), request, sess)
+		if err == nil {
+			t.Fatalf("env %q client %q: replay published without a broker", c.env, c.client)
+		}
+		for _, rejection := range []string{"belongs to env", "can not replay"} {
+			if strings.Contains(err.Error(), rejection) != (rejection == c.rejected) {
+				t.Errorf("env %q client %q: err %v", c.env, c.client, err)
+			}
+		}
+	}
+}
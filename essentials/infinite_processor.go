@@ -1,6 +1,8 @@
 package essentials
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 	"time"
 )
@@ -10,9 +12,16 @@ type InfiniteProcessor struct {
 	processors []Processor
 	wg         sync.WaitGroup
 	running    bool
+	stop       chan struct{}
 	mu         sync.Mutex
+	// rounds holds when each processor last went round, by processorName.
+	rounds map[string]time.Time
 }
 
+// processorStaleAfter is how long a processor may go without a round, idle
+// waits of 60 seconds included, before it is reported down.
+const processorStaleAfter = 3 * time.Minute
+
 func NewInfiniteProcessor(sess *Session) *InfiniteProcessor {
 	return &InfiniteProcessor{
 		sess:    sess,
@@ -25,46 +34,108 @@ func NewInfiniteProcessor(sess *Session) *InfiniteProcessor {
 	}
 }
 
+// Stop wakes the processors waiting for their next round and waits for the
+// rounds in progress to finish.
 func (p *InfiniteProcessor) Stop() {
-	p.setRunning(false)
+	p.mu.Lock()
+	if p.running {
+		p.running = false
+		close(p.stop)
+	}
+	p.mu.Unlock()
 	p.wg.Wait()
 }
 
 func (p *InfiniteProcessor) Process() {
-	p.setRunning(true)
+	p.mu.Lock()
+	if p.running {
+		p.mu.Unlock()
+		return
+	}
+	p.running = true
+	p.stop = make(chan struct{})
+	p.rounds = make(map[string]time.Time)
+	p.mu.Unlock()
 	for _, processor := range p.processors {
-		go p.infiniteProcess(processor)
 		p.wg.Add(1)
+		go p.infiniteProcess(processor, p.stop)
 	}
 }
 
-func (p *InfiniteProcessor) setRunning(v bool) {
+func (p *InfiniteProcessor) getRunning() bool {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	p.running = v
+	return p.running
 }
 
-func (p *InfiniteProcessor) getRunning() bool {
+// Health reports, per processor, whether its loop runs and goes round.
+func (p *InfiniteProcessor) Health() (map[string]LoopHealth, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	return p.running
+	result := make(map[string]LoopHealth, len(p.processors))
+	var failed error
+	for _, processor := range p.processors {
+		name := processorName(processor)
+		h, err := CheckLoop(p.running, p.rounds[name], processorStaleAfter)
+		result[name] = h
+		if err != nil && failed == nil {
+			failed = WrapError(name, err)
+		}
+	}
+	return result, failed
+}
+
+func (p *InfiniteProcessor) setRound(name string) {
+	p.mu.Lock()
+	p.rounds[name] = time.Now()
+	p.mu.Unlock()
 }
 
-func (p *InfiniteProcessor) infiniteProcess(processor Processor) {
+func (p *InfiniteProcessor) infiniteProcess(processor Processor, stop <-chan struct{}) {
+	p.setRound(processorName(processor))
 	for {
 		if !p.getRunning() {
 			goto ForEnd
 		}
 
+		start := time.Now()
 		err := processor.Process()
-		if err != nil {
-			p.sess.Logger().Errorln(err)
+		name := processorName(processor)
+		ProcessorDuration.Observe(time.Since(start).Seconds(), name)
+		ProcessorIterations.Inc(name, processorOutcome(err))
+		p.setRound(name)
+		logger := LogWith(p.sess.Logger(), Fields{"processor": name})
+		if err != nil && err != ProcessorWaitNext {
+			logger.Errorln(err)
 		}
 		if err != ProcessorWaitNext {
-			p.sess.Logger().Debugln("InfiniteProcessor wait for next round")
-			time.Sleep(time.Second * 60)
+			logger.Debugln("InfiniteProcessor wait for next round")
+			select {
+			case <-time.After(time.Second * 60):
+			case <-stop:
+			}
 		}
 	}
 ForEnd:
 	p.wg.Done()
 }
+
+// processorName is the `processor` label of the processor metrics, e.g.
+// "SucceedMessageProcessor".
+func processorName(processor Processor) string {
+	name := fmt.Sprintf("%T", processor)
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// processorOutcome is the `outcome` label of a round: "processed" when the
+// processor handled a message and goes on at once, "idle" when it found
+// none and "error" otherwise.
+func processorOutcome(err error) string {
+	switch err {
+	case ProcessorWaitNext:
+		return "processed"
+	case nil:
+		return "idle"
+	}
+	return "error"
+}
@@ -0,0 +1,95 @@
+package essentials
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/standardcore/go-logging"
+)
+
+// logSeverity orders the logging levels, whose constants are not ordered by
+// severity.
+var logSeverity = map[logging.Level]int32{
+	logging.LevelDebug: 0,
+	logging.LevelInfo:  1,
+	logging.LevelWarn:  2,
+	logging.LevelError: 3,
+	logging.LevelFatal: 4,
+	logging.LevelPanic: 5,
+}
+
+// ParseLogLevel parses a `log_level` such as "DEBUG" or "warn".
+func ParseLogLevel(level string) (logging.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug", "trace":
+		return logging.LevelDebug, nil
+	case "", "info":
+		return logging.LevelInfo, nil
+	case "warn", "warning":
+		return logging.LevelWarn, nil
+	case "error", "err":
+		return logging.LevelError, nil
+	case "fatal":
+		return logging.LevelFatal, nil
+	case "panic":
+		return logging.LevelPanic, nil
+	}
+	return logging.LevelInfo, fmt.Errorf("unknown log level '%s'", level)
+}
+
+// LogLevelFilter drops the entries below a minimum level before they reach
+// the providers it wraps. The level can be changed while logging.
+type LogLevelFilter struct {
+	min int32
+}
+
+func NewLogLevelFilter(level string) (*LogLevelFilter, error) {
+	filter := &LogLevelFilter{}
+	return filter, filter.SetLevel(level)
+}
+
+// SetLevel changes the minimum level. An unknown level leaves it unchanged.
+func (f *LogLevelFilter) SetLevel(level string) error {
+	l, err := ParseLogLevel(level)
+	if err != nil {
+		return err
+	}
+	atomic.StoreInt32(&f.min, logSeverity[l])
+	return nil
+}
+
+// Enabled reports whether entries of level pass the filter.
+func (f *LogLevelFilter) Enabled(level logging.Level) bool {
+	return logSeverity[level] >= atomic.LoadInt32(&f.min)
+}
+
+// Wrap filters provider. It takes and returns the error of the provider
+// constructor so that it composes with Logger.TryAddProvider.
+func (f *LogLevelFilter) Wrap(provider logging.LoggerProvider, err error) (logging.LoggerProvider, error) {
+	if err != nil {
+		return provider, err
+	}
+	return &levelFilteredProvider{filter: f, provider: provider}, nil
+}
+
+type levelFilteredProvider struct {
+	filter   *LogLevelFilter
+	provider logging.LoggerProvider
+}
+
+func (p *levelFilteredProvider) Log(level logging.Level, msg string) {
+	if p.filter.Enabled(level) {
+		p.provider.Log(level, msg)
+	}
+}
+
+func (p *levelFilteredProvider) Sync() error {
+	return p.provider.Sync()
+}
+
+func (p *levelFilteredProvider) LogFields(level logging.Level, msg string, fields Fields) {
+	if p.filter.Enabled(level) {
+		logToProvider(p.provider, level, msg, fields)
+	}
+}
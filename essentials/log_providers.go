@@ -0,0 +1,214 @@
+package essentials
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/standardcore/go-logging"
+)
+
+// LogProviderConfig configures one provider of the `logging` configuration.
+type LogProviderConfig struct {
+	// Type is "console", "file", "syslog" or "logstash".
+	Type string `json:"type"`
+	// Level is the minimum level of the provider. Providers without one
+	// follow `log_level`, which a reload can change.
+	Level string `json:"level"`
+	// Format is "text" or "json" for console providers; files are always
+	// written as JSON.
+	Format string `json:"format"`
+
+	// Path, MaxSizeMB and MaxBackups configure file providers: the file is
+	// rotated to path.1, path.2... once it reaches MaxSizeMB (100 when not
+	// set), keeping MaxBackups old files (3 when not set).
+	Path       string `json:"path"`
+	MaxSizeMB  int    `json:"max_size_mb"`
+	MaxBackups int    `json:"max_backups"`
+
+	// App is the syslog application name, "matcha" when not set.
+	App string `json:"app"`
+
+	// Host and Port are the UDP address of logstash.
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+// NewLogger builds the logger of the providers. Providers without a level
+// are filtered by defaultLevel.
+func NewLogger(providers []*LogProviderConfig, defaultLevel *LogLevelFilter) (*StructuredLogger, error) {
+	logger := NewStructuredLogger()
+	for _, c := range providers {
+		filter := defaultLevel
+		if c.Level != "" {
+			var err error
+			filter, err = NewLogLevelFilter(c.Level)
+			if err != nil {
+				return nil, fmt.Errorf("logging %s: %s", c.Type, err)
+			}
+		}
+		provider, err := newLogProvider(c, filter)
+		if err != nil {
+			return nil, fmt.Errorf("logging %s: %s", c.Type, err)
+		}
+		err = logger.TryAddProvider(filter.Wrap(provider, nil))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return logger, nil
+}
+
+func newLogProvider(c *LogProviderConfig, filter *LogLevelFilter) (logging.LoggerProvider, error) {
+	debug := filter.Enabled(logging.LevelDebug)
+	switch strings.ToLower(c.Type) {
+	case "console":
+		return NewConsoleProvider(c.Format)
+	case "file":
+		return NewFileProvider(c.Path, c.MaxSizeMB, c.MaxBackups)
+	case "syslog":
+		app := c.App
+		if app == "" {
+			app = "matcha"
+		}
+		return NewSyslogProvider(debug, app)
+	case "logstash":
+		if c.Host == "" || c.Port == 0 {
+			return nil, fmt.Errorf("fields `host` and `port` could not be null or empty")
+		}
+		return NewLogstashProvider(debug, c.Host, c.Port)
+	}
+	return nil, fmt.Errorf("type `%s` should be `console`, `file`, `syslog` or `logstash`", c.Type)
+}
+
+// ConsoleProvider writes entries to stdout, as text or as JSON lines.
+type ConsoleProvider struct {
+	mu   sync.Mutex
+	json bool
+	out  io.Writer
+}
+
+func NewConsoleProvider(format string) (logging.LoggerProvider, error) {
+	switch strings.ToLower(format) {
+	case "", "text":
+		return &ConsoleProvider{out: os.Stdout}, nil
+	case "json":
+		return &ConsoleProvider{json: true, out: os.Stdout}, nil
+	}
+	return nil, fmt.Errorf("format `%s` should be `text` or `json`", format)
+}
+
+func (provider *ConsoleProvider) Log(level logging.Level, msg string) {
+	provider.LogFields(level, msg, nil)
+}
+
+func (provider *ConsoleProvider) LogFields(level logging.Level, msg string, fields Fields) {
+	var line string
+	if provider.json {
+		line = string(jsonLogEntry(level, msg, fields)) + "\n"
+	} else if len(fields) > 0 {
+		line = fmt.Sprintf("[%s:] %s %s\n", level.String(), msg, formatFields(fields))
+	} else {
+		line = fmt.Sprintf("[%s:] %s\n", level.String(), msg)
+	}
+	provider.mu.Lock()
+	_, _ = io.WriteString(provider.out, line)
+	provider.mu.Unlock()
+}
+
+func (provider *ConsoleProvider) Sync() error {
+	return nil
+}
+
+// FileProvider appends entries to a file as JSON lines, rotating it by size.
+type FileProvider struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func NewFileProvider(path string, maxSizeMB int, maxBackups int) (logging.LoggerProvider, error) {
+	if path == "" {
+		return nil, fmt.Errorf("field `path` could not be null or empty")
+	}
+	if maxSizeMB <= 0 {
+		maxSizeMB = 100
+	}
+	if maxBackups <= 0 {
+		maxBackups = 3
+	}
+	provider := &FileProvider{path: path, maxSize: int64(maxSizeMB) * 1024 * 1024, maxBackups: maxBackups}
+	return provider, provider.open()
+}
+
+func (provider *FileProvider) open() error {
+	file, err := os.OpenFile(provider.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	provider.file = file
+	provider.size = info.Size()
+	return nil
+}
+
+// rotate shifts path.N to path.N+1, dropping the oldest, moves the file to
+// path.1 and opens a new one.
+func (provider *FileProvider) rotate() error {
+	if provider.file != nil {
+		provider.file.Close()
+		provider.file = nil
+	}
+	_ = os.Remove(fmt.Sprintf("%s.%d", provider.path, provider.maxBackups))
+	for i := provider.maxBackups - 1; i >= 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", provider.path, i), fmt.Sprintf("%s.%d", provider.path, i+1))
+	}
+	err := os.Rename(provider.path, provider.path+".1")
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return provider.open()
+}
+
+func (provider *FileProvider) Log(level logging.Level, msg string) {
+	provider.LogFields(level, msg, nil)
+}
+
+func (provider *FileProvider) LogFields(level logging.Level, msg string, fields Fields) {
+	line := append(jsonLogEntry(level, msg, fields), '\n')
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	var err error
+	if provider.file == nil {
+		err = provider.open()
+	} else if provider.size > 0 && provider.size+int64(len(line)) > provider.maxSize {
+		err = provider.rotate()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, WrapError("FileProvider", err))
+		return
+	}
+	n, err := provider.file.Write(line)
+	provider.size += int64(n)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, WrapError("FileProvider", err))
+	}
+}
+
+func (provider *FileProvider) Sync() error {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if provider.file == nil {
+		return nil
+	}
+	return provider.file.Sync()
+}
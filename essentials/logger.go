@@ -39,6 +39,15 @@ func (provider *SyslogProvider) Log(level logging.Level, msg string) {
 	}
 }
 
+// LogFields logs msg with fields as fields of the syslog JSON entry.
+func (provider *SyslogProvider) LogFields(level logging.Level, msg string, fields Fields) {
+	logger := provider.logger
+	for _, k := range sortedFieldKeys(fields) {
+		logger = logger.With(k, fields[k])
+	}
+	(&SyslogProvider{logger: logger}).Log(level, msg)
+}
+
 func (provider *SyslogProvider) Sync() error {
 	return provider.logger.Sync()
 }
@@ -49,6 +58,9 @@ type LogStashProvider struct {
 
 func NewLogstashProvider(debugLevel bool, host string, port int) (logging.LoggerProvider, error) {
 	stash, err := log.NewLogstashWithTimeout(debugLevel, host, port, 10)
+	if err != nil {
+		return nil, err
+	}
 	defer stash.Sync()
 	return &LogStashProvider{
 		logger: stash,
@@ -59,6 +71,15 @@ func (provider *LogStashProvider) Sync() error {
 	return provider.logger.Sync()
 }
 
+// LogFields logs msg with fields as fields of the logstash JSON entry.
+func (provider *LogStashProvider) LogFields(level logging.Level, msg string, fields Fields) {
+	logger := provider.logger
+	for _, k := range sortedFieldKeys(fields) {
+		logger = logger.With(k, fields[k])
+	}
+	(&LogStashProvider{logger: logger}).Log(level, msg)
+}
+
 func (provider *LogStashProvider) Log(level logging.Level, msg string) {
 	switch level {
 	case logging.LevelDebug:
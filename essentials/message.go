@@ -18,6 +18,8 @@ type Message struct {
 	StateName          string
 	Retry              int32
 	Env                string
+	TraceParent        string
+	TraceState         string
 	CreationTime       int64
 	CreationTimeString string
 }
@@ -31,10 +33,11 @@ func (m *Message) FetchLogs(executor DbExecutor) ([]*MessageLog, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 	result := make([]*MessageLog, 0)
 	for rows.Next() {
 		var log MessageLog
-		err = rows.Scan(&log.ID, &log.MessageID, &log.OrignalState, &log.OrignalStateName, &log.State, &log.StateName, &log.CreationTime, &log.CreationTimeString)
+		err = rows.Scan(&log.ID, &log.MessageID, &log.OrignalState, &log.OrignalStateName, &log.State, &log.StateName, &log.Remark, &log.CreationTime, &log.CreationTimeString)
 		if err != nil {
 			return nil, err
 		}
@@ -48,6 +51,7 @@ func (m *Message) FetchSubscriptions(executor DbExecutor) ([]*Subscription, erro
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 	result := make([]*Subscription, 0)
 	for rows.Next() {
 		var item Subscription
@@ -72,7 +76,8 @@ func (m *Message) Append(executor DbExecutor) (string, error) {
 	_, err := executor.ExecScript("InsertMessage",
 		m.ID, m.MessageType, m.Content, m.State, m.StateName,
 		m.Retry, m.CreationTime, m.CreationTimeString,
-		m.Publisher, m.PublishTime, m.PublishTimeString, m.Env)
+		m.Publisher, m.PublishTime, m.PublishTimeString, m.Env,
+		m.TraceParent, m.TraceState)
 	if err != nil {
 		return "", err
 	}
@@ -102,6 +107,12 @@ func (m *Message) Published(executor DbExecutor) error {
 }
 
 func (m *Message) ChangeState(state MessageState, executor DbExecutor) error {
+	return m.ChangeStateWithRemark(state, "", executor)
+}
+
+// ChangeStateWithRemark changes the message state and keeps remark on the
+// message log row.
+func (m *Message) ChangeStateWithRemark(state MessageState, remark string, executor DbExecutor) error {
 	log := &MessageLog{
 		ID:                 NewOrderedUUID(),
 		MessageID:          m.ID,
@@ -109,6 +120,7 @@ func (m *Message) ChangeState(state MessageState, executor DbExecutor) error {
 		OrignalStateName:   m.State.String(),
 		State:              state,
 		StateName:          state.String(),
+		Remark:             remark,
 		CreationTime:       time.Now().Unix(),
 		CreationTimeString: FormatTime(time.Now()),
 	}
@@ -123,8 +135,31 @@ func (m *Message) ChangeState(state MessageState, executor DbExecutor) error {
 	return nil
 }
 
+// AppendLog records a remark about the message without changing its state.
+func (m *Message) AppendLog(remark string, executor DbExecutor) error {
+	log := &MessageLog{
+		MessageID:        m.ID,
+		OrignalState:     m.State,
+		OrignalStateName: m.State.String(),
+		State:            m.State,
+		StateName:        m.State.String(),
+		Remark:           remark,
+	}
+	_, err := log.Append(executor)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// IncreaseRetry increments the retry counter and returns its new value. Run
+// it in a transaction so no other retry slips in between the two statements.
 func (m *Message) IncreaseRetry(executor DbExecutor) (int32, error) {
-	row, err := executor.QueryScriptRow("IncreaseMessageRetry", m.ID)
+	_, err := executor.ExecScript("IncreaseMessageRetry", m.ID)
+	if err != nil {
+		return 0, err
+	}
+	row, err := executor.QueryScriptRow("FindMessageRetry", m.ID)
 	if err != nil {
 		return 0, err
 	}
@@ -136,6 +171,17 @@ func (m *Message) IncreaseRetry(executor DbExecutor) (int32, error) {
 	return retry, nil
 }
 
+// ResetRetry clears the retry counter, e.g. once an occurrence of a recurring
+// job has been published.
+func (m *Message) ResetRetry(executor DbExecutor) error {
+	_, err := executor.ExecScript("ResetMessageRetry", m.ID)
+	if err != nil {
+		return err
+	}
+	m.Retry = 0
+	return nil
+}
+
 func (m *Message) ChangeSubscriptionState(stateName string, receiverTag string, executor DbExecutor) error {
 	_, err := executor.ExecScript("ChangeSubscriptionState", stateName, time.Now().Unix(), FormatTime(time.Now()), "NOVALUE", m.ID, receiverTag)
 	if err != nil {
@@ -144,6 +190,9 @@ func (m *Message) ChangeSubscriptionState(stateName string, receiverTag string,
 	return nil
 }
 
+// AppendDefaultSubscribers subscribes the receivers of the template named
+// templateName to the message. Receivers already subscribed keep their own
+// subscription.
 func (m *Message) AppendDefaultSubscribers(templateName string, executor DbExecutor) error {
 	template, err := FindOneTemplate("UNKNOWN", templateName, executor)
 	if err != nil {
@@ -156,7 +205,19 @@ func (m *Message) AppendDefaultSubscribers(templateName string, executor DbExecu
 	if err != nil {
 		return WrapError("AppendDefaultSubscribers", err)
 	}
+	existing, err := m.FetchSubscriptions(executor)
+	if err != nil {
+		return WrapError("AppendDefaultSubscribers", err)
+	}
+	subscribed := make(map[string]bool, len(existing))
+	for _, sub := range existing {
+		subscribed[sub.ReceiverTag] = true
+	}
 	for _, sub := range subs {
+		if subscribed[sub.ReceiverTag] {
+			continue
+		}
+		subscribed[sub.ReceiverTag] = true
 		s := &Subscription{
 			ID:          NewOrderedUUID(),
 			MessageID:   m.ID,
@@ -169,6 +230,15 @@ func (m *Message) AppendDefaultSubscribers(templateName string, executor DbExecu
 		if err != nil {
 			return WrapError("AppendDefaultSubscribers", err)
 		}
+		flow := &Flow{
+			SubscriptionID: s.ID,
+			StateName:      "Scheduled",
+			Remark:         "",
+		}
+		_, err = flow.Append(executor)
+		if err != nil {
+			return WrapError("AppendDefaultSubscribers", err)
+		}
 	}
 	return nil
 }
@@ -180,6 +250,7 @@ type MessageLog struct {
 	OrignalStateName   string
 	State              MessageState
 	StateName          string
+	Remark             string
 	CreationTime       int64
 	CreationTimeString string
 }
@@ -194,7 +265,7 @@ func (m *MessageLog) Append(executor DbExecutor) (string, error) {
 	m.CreationTime = time.Now().Unix()
 	m.CreationTimeString = FormatTime(time.Now())
 	_, err := executor.ExecScript("InsertMessageLog", m.ID, m.MessageID, m.OrignalState, m.OrignalStateName,
-		m.State, m.StateName, m.CreationTime, m.CreationTimeString)
+		m.State, m.StateName, m.CreationTime, m.CreationTimeString, m.Remark)
 	if err != nil {
 		return "", err
 	}
@@ -235,11 +306,23 @@ func (s *Subscription) ChangeState(stateName string, executor DbExecutor) error
 	return nil
 }
 
+// Reset changes the subscription state even when it already Failed, for
+// subscriptions that are delivered again.
+func (s *Subscription) Reset(stateName string, executor DbExecutor) error {
+	_, err := executor.ExecScript("ResetSubscriptionState", stateName, time.Now().Unix(), FormatTime(time.Now()), s.ID)
+	if err != nil {
+		return err
+	}
+	s.StateName = stateName
+	return nil
+}
+
 func (s *Subscription) FetchFlows(executor DbExecutor) ([]*Flow, error) {
 	rows, err := executor.QueryScript("FetchFlows", s.ID)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 	result := make([]*Flow, 0)
 	for rows.Next() {
 		var item Flow
@@ -286,6 +369,7 @@ const (
 	MessageSucceeded               = MessageState(3)
 	MessageFailed                  = MessageState(4)
 	MessageRollback                = MessageState(5)
+	MessageCancelled               = MessageState(7) // 6 was the legacy Published state
 	MessageUnknown                 = MessageState(99)
 )
 
@@ -310,6 +394,8 @@ func ParseMessageState(str string) MessageState {
 		return MessageUnknown
 	} else if str == "Rollback" {
 		return MessageRollback
+	} else if str == "Cancelled" {
+		return MessageCancelled
 	}
 	return MessageUnknown
 }
@@ -326,6 +412,8 @@ func (state MessageState) String() string {
 		return "Failed"
 	case MessageRollback:
 		return "Rollback"
+	case MessageCancelled:
+		return "Cancelled"
 	}
 	return "Unknown"
 }
@@ -343,18 +431,45 @@ func FindOneMessage(ID string, lock bool, executor DbExecutor) (*Message, error)
 	var m Message
 	err = row.Scan(&m.ID, &m.MessageType, &m.Content, &m.State,
 		&m.StateName, &m.Retry, &m.CreationTime, &m.CreationTimeString,
-		&m.Publisher, &m.PublishTime, &m.PublishTimeString, &m.Env)
+		&m.Publisher, &m.PublishTime, &m.PublishTimeString, &m.Env,
+		&m.TraceParent, &m.TraceState)
 	if err != nil {
 		return nil, err
 	}
 	return &m, nil
 }
 
+type Event struct {
+	ID        string
+	MessageID string
+	Exchange  string
+	RouteKey  string
+	Queue     string
+}
+
+func FindOneEvent(messageID string, executor DbExecutor) (*Event, error) {
+	row, err := executor.QueryScriptRow("FindOneEvent", messageID)
+	if err != nil {
+		return nil, err
+	}
+	var e Event
+	var queue *string
+	err = row.Scan(&e.ID, &e.MessageID, &e.Exchange, &e.RouteKey, &queue)
+	if err != nil {
+		return nil, err
+	}
+	if queue != nil {
+		e.Queue = *queue
+	}
+	return &e, nil
+}
+
 func FindProcessingMessageIDs(executor DbExecutor) ([]string, error) {
 	rows, err := executor.QueryScript("FindProcessingMessage")
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 	result := make([]string, 0)
 	for rows.Next() {
 		var ID string
@@ -366,8 +481,14 @@ func FindProcessingMessageIDs(executor DbExecutor) ([]string, error) {
 
 func AppendMessage(payload *Payload, extsHandler func(*ExtensionsEventArgs, DbExecutor) error, executor DbExecutor) (*Message, error) {
 	msg := NewMessage()
+	msg.ID = payload.MessageID
 	msg.MessageType = payload.MessageType
 	msg.Content = payload.Content
+	msg.Env = payload.Env
+	if trace := payload.Trace(); trace.Valid() {
+		msg.TraceParent = trace.TraceParent()
+		msg.TraceState = trace.State
+	}
 
 	if v, ok := payload.Headers["x-matcha-client"]; ok {
 		msg.Publisher = v.(string)
@@ -423,6 +544,13 @@ func AppendMessage(payload *Payload, extsHandler func(*ExtensionsEventArgs, DbEx
 		}
 	}
 
+	if templateName := payload.Extensions["x-matcha-template"]; templateName != "" {
+		err = msg.AppendDefaultSubscribers(templateName, executor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if extsHandler != nil {
 		err = extsHandler(&ExtensionsEventArgs{
 			Extensions: payload.Extensions,
@@ -433,6 +561,7 @@ func AppendMessage(payload *Payload, extsHandler func(*ExtensionsEventArgs, DbEx
 		}
 	}
 
+	MessagesAppended.Inc(msg.MessageType, msg.Publisher)
 	return msg, nil
 }
 
@@ -464,25 +593,3 @@ func FineOneLockSubscription(id string, messageID string, receiverTag string, ex
 	}
 	return &m, nil
 }
-
-type failedMessage struct {
-	MessageID   string
-	MessageType string
-}
-
-func findFailedMessage(executor DbExecutor) (*failedMessage, error) {
-	rows, err := executor.QueryScript("FindFailedMessage")
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	if rows.Next() {
-		var m failedMessage
-		err = rows.Scan(&m.MessageID, &m.MessageType)
-		if err != nil {
-			return nil, err
-		}
-		return &m, nil
-	}
-	return nil, nil
-}
@@ -0,0 +1,238 @@
+package essentials
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Metrics holds the metrics of the process, written out in the Prometheus
+// text format by `/metrics`.
+var Metrics = NewMetricsRegistry()
+
+var (
+	MessagesAppended = Metrics.NewCounterVec("matcha_messages_appended_total",
+		"Messages stored, by message type and publisher.", "type", "publisher")
+	Publishes = Metrics.NewCounterVec("matcha_publishes_total",
+		"Messages published to the broker, by exchange and result.", "exchange", "result")
+	JobRetries = Metrics.NewCounterVec("matcha_job_retries_total",
+		"Failed background job deliveries rescheduled for another attempt, by job kind.", "kind")
+	JobGiveUps = Metrics.NewCounterVec("matcha_job_giveups_total",
+		"Background jobs dead-lettered after their last attempt, by job kind.", "kind")
+	ProcessorIterations = Metrics.NewCounterVec("matcha_processor_iterations_total",
+		"Rounds of the message processors, by processor and outcome.", "processor", "outcome")
+	ProcessorDuration = Metrics.NewHistogramVec("matcha_processor_iteration_duration_seconds",
+		"Duration of the rounds of the message processors.", DefaultBuckets, "processor")
+	HTTPRequestDuration = Metrics.NewHistogramVec("matcha_http_request_duration_seconds",
+		"Duration of the HTTP requests, by route, method and status code.", DefaultBuckets, "route", "method", "code")
+	DbConnectionErrors = Metrics.NewCounterVec("matcha_db_connection_errors_total",
+		"Failures to open the database or to get a connection from the pool.")
+	AmqpConnectionErrors = Metrics.NewCounterVec("matcha_amqp_connection_errors_total",
+		"Failed dials to the broker and connections closed by it.", "reason")
+)
+
+// DefaultBuckets are the histogram buckets, in seconds.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+// MetricsRegistry is a set of counters and histograms.
+type MetricsRegistry struct {
+	mu      sync.Mutex
+	metrics []metric
+}
+
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{metrics: make([]metric, 0)}
+}
+
+func (r *MetricsRegistry) register(m metric) {
+	r.mu.Lock()
+	r.metrics = append(r.metrics, m)
+	r.mu.Unlock()
+}
+
+// WriteText writes every metric in the Prometheus text format 0.0.4.
+func (r *MetricsRegistry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	metrics := make([]metric, len(r.metrics))
+	copy(metrics, r.metrics)
+	r.mu.Unlock()
+	bw := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(bw)
+	}
+	return bw.Flush()
+}
+
+// CounterVec is a counter per combination of label values.
+type CounterVec struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func (r *MetricsRegistry) NewCounterVec(name string, help string, labels ...string) *CounterVec {
+	c := &CounterVec{name: name, help: help, labels: labels, values: make(map[string]float64)}
+	r.register(c)
+	return c
+}
+
+// Inc adds one to the counter of labelValues, given in the order of the
+// labels.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	key := labelKey(c.labels, labelValues)
+	c.mu.Lock()
+	c.values[key] += v
+	c.mu.Unlock()
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	writeHeader(w, c.name, c.help, "counter")
+	if len(c.labels) == 0 && len(c.values) == 0 {
+		fmt.Fprintf(w, "%s 0\n", c.name)
+		return
+	}
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labels, key, ""), formatFloat(c.values[key]))
+	}
+}
+
+// HistogramVec is a histogram per combination of label values.
+type HistogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	values map[string]*histogram
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func (r *MetricsRegistry) NewHistogramVec(name string, help string, buckets []float64, labels ...string) *HistogramVec {
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+	h := &HistogramVec{name: name, help: help, labels: labels, buckets: sorted, values: make(map[string]*histogram)}
+	r.register(h)
+	return h
+}
+
+// Observe records v in the histogram of labelValues.
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	key := labelKey(h.labels, labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	value, ok := h.values[key]
+	if !ok {
+		value = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.values[key] = value
+	}
+	for i, bound := range h.buckets {
+		if v <= bound {
+			value.counts[i]++
+		}
+	}
+	value.count++
+	value.sum += v
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	writeHeader(w, h.name, h.help, "histogram")
+	keys := make([]string, 0, len(h.values))
+	for key := range h.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := h.values[key]
+		for i, bound := range h.buckets {
+			le := fmt.Sprintf("le=\"%s\"", formatFloat(bound))
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, key, le), value.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, key, "le=\"+Inf\""), value.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.labels, key, ""), formatFloat(value.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.labels, key, ""), value.count)
+	}
+}
+
+const labelSeparator = "\xff"
+
+// labelKey joins labelValues, padded or cut to the number of labels.
+func labelKey(labels []string, labelValues []string) string {
+	values := make([]string, len(labels))
+	copy(values, labelValues)
+	return strings.Join(values, labelSeparator)
+}
+
+func formatLabels(labels []string, key string, extra string) string {
+	pairs := make([]string, 0, len(labels)+1)
+	if len(labels) > 0 {
+		for i, value := range strings.Split(key, labelSeparator) {
+			pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", labels[i], escapeLabelValue(value)))
+		}
+	}
+	if extra != "" {
+		pairs = append(pairs, extra)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var labelValueReplacer = strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n")
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+func writeHeader(w *bufio.Writer, name string, help string, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, strings.Replace(strings.Replace(help, "\\", "\\\\", -1), "\n", "\\n", -1))
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
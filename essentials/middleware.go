@@ -1,6 +1,14 @@
 package essentials
 
+import "context"
+
 type Middleware interface {
 	Equalable
 	Execute(ctx *MatchaContext) error
 }
+
+// Stopper is implemented by middlewares with background work to drain on
+// shutdown.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
@@ -0,0 +1,298 @@
+package essentials
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migration is one versioned schema change embedded from
+// resources/migrations/NNNN_name.up.sql and NNNN_name.down.sql. Both scripts
+// refer to the schema as ${SCHEMA}.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration is applied to the database.
+type MigrationStatus struct {
+	Version     int    `json:"version"`
+	Name        string `json:"name"`
+	Applied     bool   `json:"applied"`
+	AppliedTime string `json:"applied_time,omitempty"`
+}
+
+// Migrator applies the embedded migrations of the session dialect to the
+// `dbprefix` schema and records them in "citadel.schema_migrations". Every
+// migration runs in its own transaction together with its version row; MySQL
+// commits DDL implicitly, so a failed MySQL migration may be left half done.
+type Migrator struct {
+	sess       *Session
+	dialect    Dialect
+	schema     string
+	migrations []*Migration
+}
+
+func NewMigrator(sess *Session) (*Migrator, error) {
+	schema, err := sess.Require("dbprefix")
+	if err != nil {
+		return nil, err
+	}
+	dialect := sess.Dialect()
+	migrations, err := LoadMigrations(NewMigrationResources().GetResourcesOf(dialect.resourceDir()))
+	if err != nil {
+		return nil, WrapError("NewMigrator", err)
+	}
+	return &Migrator{sess: sess, dialect: dialect, schema: dialect.Schema(schema), migrations: migrations}, nil
+}
+
+// LoadMigrations pairs the up and down scripts of res by version, ordered by
+// version.
+func LoadMigrations(res map[string][]byte) ([]*Migration, error) {
+	byVersion := make(map[int]*Migration)
+	for key, data := range res {
+		parts := strings.Split(key, ".")
+		if len(parts) != 3 || parts[2] != "sql" || (parts[1] != "up" && parts[1] != "down") {
+			return nil, fmt.Errorf("invalid migration file name %s", key)
+		}
+		idx := strings.Index(parts[0], "_")
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid migration file name %s", key)
+		}
+		version, err := strconv.Atoi(parts[0][:idx])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s", key)
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: parts[0][idx+1:]}
+			byVersion[version] = m
+		} else if m.Name != parts[0][idx+1:] {
+			return nil, fmt.Errorf("migration %d has two names, %s and %s", version, m.Name, parts[0][idx+1:])
+		}
+		if parts[1] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+	result := make([]*Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down script", m.Version, m.Name)
+		}
+		result = append(result, m)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+func (m *Migrator) table() string {
+	return fmt.Sprintf(`"%s"."citadel.schema_migrations"`, m.schema)
+}
+
+func (m *Migrator) expand(script string) string {
+	return strings.Replace(script, "${SCHEMA}", m.schema, -1)
+}
+
+// exec runs the statements of script one by one, since not every driver
+// accepts several statements in one call.
+func (m *Migrator) exec(tx *sql.Tx, script string) error {
+	for _, statement := range strings.Split(m.expand(script), ";\n") {
+		statement = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(statement), ";"))
+		if statement == "" {
+			continue
+		}
+		_, err := tx.Exec(statement)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) ensureTable(db *sql.DB) error {
+	if m.dialect == DialectPostgres {
+		_, err := db.Exec(fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS "%s"`, m.schema))
+		if err != nil {
+			return err
+		}
+	}
+	_, err := db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  "Version" INT NOT NULL,
+  "Name" VARCHAR(128) NOT NULL,
+  "AppliedTime" BIGINT NOT NULL,
+  "AppliedTimeString" VARCHAR(32) NOT NULL,
+  CONSTRAINT "PK_citadel.schema_migrations" PRIMARY KEY ("Version")
+)`, m.table()))
+	return err
+}
+
+func (m *Migrator) applied(db *sql.DB) (map[int]string, error) {
+	rows, err := db.Query(fmt.Sprintf(`SELECT "Version", "AppliedTimeString" FROM %s`, m.table()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := make(map[int]string)
+	for rows.Next() {
+		var version int
+		var appliedTime string
+		err = rows.Scan(&version, &appliedTime)
+		if err != nil {
+			return nil, err
+		}
+		result[version] = appliedTime
+	}
+	return result, rows.Err()
+}
+
+// Status lists every embedded migration, plus applied versions that are no
+// longer embedded, ordered by version.
+func (m *Migrator) Status() ([]*MigrationStatus, error) {
+	db, err := m.sess.database()
+	if err != nil {
+		return nil, WrapError("Migrator.Status", err)
+	}
+	err = m.ensureTable(db)
+	if err != nil {
+		return nil, WrapError("Migrator.Status", err)
+	}
+	applied, err := m.applied(db)
+	if err != nil {
+		return nil, WrapError("Migrator.Status", err)
+	}
+	result := make([]*MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		appliedTime, ok := applied[migration.Version]
+		result = append(result, &MigrationStatus{
+			Version:     migration.Version,
+			Name:        migration.Name,
+			Applied:     ok,
+			AppliedTime: appliedTime,
+		})
+		delete(applied, migration.Version)
+	}
+	for version, appliedTime := range applied {
+		result = append(result, &MigrationStatus{Version: version, Name: "(unknown)", Applied: true, AppliedTime: appliedTime})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// Up applies every pending migration in version order and returns the applied
+// ones.
+func (m *Migrator) Up() ([]*Migration, error) {
+	db, err := m.sess.database()
+	if err != nil {
+		return nil, WrapError("Migrator.Up", err)
+	}
+	err = m.ensureTable(db)
+	if err != nil {
+		return nil, WrapError("Migrator.Up", err)
+	}
+	result := make([]*Migration, 0)
+	for _, migration := range m.migrations {
+		done, err := m.run(db, migration, true)
+		if err != nil {
+			return result, WrapError(fmt.Sprintf("Migrator.Up:%04d_%s", migration.Version, migration.Name), err)
+		}
+		if done {
+			m.sess.Logger().Infoln(fmt.Sprintf("migration %04d_%s applied", migration.Version, migration.Name))
+			result = append(result, migration)
+		}
+	}
+	return result, nil
+}
+
+// Down reverts the last steps applied migrations, newest first, and returns
+// the reverted ones.
+func (m *Migrator) Down(steps int) ([]*Migration, error) {
+	db, err := m.sess.database()
+	if err != nil {
+		return nil, WrapError("Migrator.Down", err)
+	}
+	err = m.ensureTable(db)
+	if err != nil {
+		return nil, WrapError("Migrator.Down", err)
+	}
+	applied, err := m.applied(db)
+	if err != nil {
+		return nil, WrapError("Migrator.Down", err)
+	}
+	result := make([]*Migration, 0, steps)
+	for i := len(m.migrations) - 1; i >= 0 && len(result) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		done, err := m.run(db, migration, false)
+		if err != nil {
+			return result, WrapError(fmt.Sprintf("Migrator.Down:%04d_%s", migration.Version, migration.Name), err)
+		}
+		if done {
+			m.sess.Logger().Infoln(fmt.Sprintf("migration %04d_%s reverted", migration.Version, migration.Name))
+			result = append(result, migration)
+		}
+	}
+	return result, nil
+}
+
+// run applies (up) or reverts migration. The version table is locked for the
+// transaction so concurrent migrators apply every migration once; it reports
+// false when another migrator got there first. SQLite transactions hold the
+// database write lock already and MySQL locks the version row range read
+// FOR UPDATE.
+func (m *Migrator) run(db *sql.DB, migration *Migration, up bool) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	lock := ""
+	switch m.dialect {
+	case DialectPostgres:
+		_, err = tx.Exec(fmt.Sprintf(`LOCK TABLE %s IN EXCLUSIVE MODE`, m.table()))
+		if err != nil {
+			return false, err
+		}
+	case DialectMySQL:
+		lock = " FOR UPDATE"
+	}
+	var count int
+	query, args := m.dialect.Rebind(fmt.Sprintf(`SELECT COUNT(1) FROM %s WHERE "Version" = $1%s`, m.table(), lock), []interface{}{migration.Version})
+	err = tx.QueryRow(query, args...).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	if up == (count > 0) {
+		return false, nil
+	}
+
+	if up {
+		err = m.exec(tx, migration.Up)
+		if err != nil {
+			return false, err
+		}
+		now := time.Now()
+		query, args = m.dialect.Rebind(fmt.Sprintf(`INSERT INTO %s ("Version", "Name", "AppliedTime", "AppliedTimeString") VALUES ($1, $2, $3, $4)`, m.table()),
+			[]interface{}{migration.Version, migration.Name, now.Unix(), FormatTime(now)})
+	} else {
+		err = m.exec(tx, migration.Down)
+		if err != nil {
+			return false, err
+		}
+		query, args = m.dialect.Rebind(fmt.Sprintf(`DELETE FROM %s WHERE "Version" = $1`, m.table()), []interface{}{migration.Version})
+	}
+	_, err = tx.Exec(query, args...)
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
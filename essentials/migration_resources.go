@@ -0,0 +1,117 @@
+package essentials
+
+//creation_time:2026-10-18T11:01:19Z
+
+//0001_init.down.sql
+//0001_init.up.sql
+//0002_job_scheduling.down.sql
+//0002_job_scheduling.up.sql
+//0003_message_log_remark.down.sql
+//0003_message_log_remark.up.sql
+//0004_message_env.down.sql
+//0004_message_env.up.sql
+//0005_message_trace.down.sql
+//0005_message_trace.up.sql
+//0006_job_occurrence.down.sql
+//0006_job_occurrence.up.sql
+//mysql/0001_init.down.sql
+//mysql/0001_init.up.sql
+//mysql/0002_job_scheduling.down.sql
+//mysql/0002_job_scheduling.up.sql
+//mysql/0003_message_log_remark.down.sql
+//mysql/0003_message_log_remark.up.sql
+//mysql/0004_message_env.down.sql
+//mysql/0004_message_env.up.sql
+//mysql/0005_message_trace.down.sql
+//mysql/0005_message_trace.up.sql
+//mysql/0006_job_occurrence.down.sql
+//mysql/0006_job_occurrence.up.sql
+//sqlite/0001_init.down.sql
+//sqlite/0001_init.up.sql
+//sqlite/0002_job_scheduling.down.sql
+//sqlite/0002_job_scheduling.up.sql
+//sqlite/0003_message_log_remark.down.sql
+//sqlite/0003_message_log_remark.up.sql
+//sqlite/0004_message_env.down.sql
+//sqlite/0004_message_env.up.sql
+//sqlite/0005_message_trace.down.sql
+//sqlite/0005_message_trace.up.sql
+//sqlite/0006_job_occurrence.down.sql
+//sqlite/0006_job_occurrence.up.sql
+
+func NewMigrationResources() *ScriptResources {
+	r := &ScriptResources{}
+	r.Store("0001_init.down.sql", "RFJPUCBUQUJMRSBJRiBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3ViX3RlbXBsYXRlX2RldGFpbHMiOwpEUk9QIFRBQkxFIElGIEVYSVNUUyAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJfdGVtcGxhdGVzIjsKRFJPUCBUQUJMRSBJRiBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwuam9icyI7CkRST1AgVEFCTEUgSUYgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLmV2ZW50cyI7CkRST1AgVEFCTEUgSUYgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLmZsb3dzIjsKRFJPUCBUQUJMRSBJRiBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyI7CkRST1AgVEFCTEUgSUYgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VfbG9ncyI7CkRST1AgVEFCTEUgSUYgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIjsK")
+
+	r.Store("0001_init.up.sql", "Q1JFQVRFIFNDSEVNQSBJRiBOT1QgRVhJU1RTICIke1NDSEVNQX0iOwoKQ1JFQVRFIFRBQkxFIElGIE5PVCBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiICgKICAiSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJNZXNzYWdlVHlwZSIgVkFSQ0hBUig2NCkgTk9UIE5VTEwsCiAgIkNvbnRlbnQiIFRFWFQgTk9UIE5VTEwgREVGQVVMVCAnJywKICAiU3RhdGUiIFNNQUxMSU5UIE5PVCBOVUxMLAogICJTdGF0ZU5hbWUiIFZBUkNIQVIoMzIpIE5PVCBOVUxMLAogICJSZXRyeSIgSU5UIE5PVCBOVUxMIERFRkFVTFQgMCwKICAiQ3JlYXRpb25UaW1lIiBCSUdJTlQgTk9UIE5VTEwsCiAgIkNyZWF0aW9uVGltZVN0cmluZyIgVkFSQ0hBUigzMikgTk9UIE5VTEwsCiAgIlB1Ymxpc2hlciIgVkFSQ0hBUigxMjgpIE5PVCBOVUxMIERFRkFVTFQgJycsCiAgIlB1Ymxpc2hUaW1lIiBCSUdJTlQgTk9UIE5VTEwgREVGQVVMVCAwLAogICJQdWJsaXNoVGltZVN0cmluZyIgVkFSQ0hBUigzMikgTk9UIE5VTEwgREVGQVVMVCAnJywKICAiRW52IiBWQVJDSEFSKDMyKSBOT1QgTlVMTCBERUZBVUxUICcnLAogIENPTlNUUkFJTlQgIlBLX2NpdGFkZWwubWVzc2FnZXMiIFBSSU1BUlkgS0VZICgiSUQiKQopOwpDUkVBVEUgSU5ERVggSUYgTk9UIEVYSVNUUyAiSVhfY2l0YWRlbC5tZXNzYWdlc19NZXNzYWdlVHlwZV9TdGF0ZSIgT04gIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiICgiTWVzc2FnZVR5cGUiLCAiU3RhdGUiLCAiQ3JlYXRpb25UaW1lIik7CgpDUkVBVEUgVEFCTEUgSUYgTk9UIEVYSVNUUyAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlX2xvZ3MiICgKICAiSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJNZXNzYWdlSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJPcmlnbmFsU3RhdGUiIFNNQUxMSU5UIE5PVCBOVUxMLAogICJPcmlnbmFsU3RhdGVOYW1lIiBWQVJDSEFSKDMyKSBOT1QgTlVMTCwKICAiU3RhdGUiIFNNQUxMSU5UIE5PVCBOVUxMLAogICJTdGF0ZU5hbWUiIFZBUkNIQVIoMzIpIE5PVCBOVUxMLAogICJDcmVhdGlvblRpbWUiIEJJR0lOVCBOT1QgTlVMTCwKICAiQ3JlYXRpb25UaW1lU3RyaW5nIiBWQVJDSEFSKDMyKSBOT1QgTlVMTCwKICBDT05TVFJBSU5UICJQS19jaXRhZGVsLm1lc3NhZ2VfbG9ncyIgUFJJTUFSWSBLRVkgKCJJRCIpCik7CkNSRUFURSBJTkRFWCBJRiBOT1QgRVhJU1RTICJJWF9jaXRhZGVsLm1lc3NhZ2VfbG9nc19NZXNzYWdlSUQiIE9OICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VfbG9ncyIgKCJNZXNzYWdlSUQiLCAiQ3JlYXRpb25UaW1lIik7CgpDUkVBVEUgVEFCTEUgSUYgTk9UIEVYSVNUUyAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIiAoCiAgIklEIiBWQVJDSEFSKDM2KSBOT1QgTlVMTCwKICAiTWVzc2FnZUlEIiBWQVJDSEFSKDM2KSBOT1QgTlVMTCwKICAiUmVjZWl2ZXJUYWciIFZBUkNIQVIoMTI4KSBOT1QgTlVMTCwKICAiRXhjaGFuZ2UiIFZBUkNIQVIoMjU1KSBOT1QgTlVMTCwKICAiUm91dGVLZXkiIFZBUkNIQVIoMjU1KSBOT1QgTlVMTCwKICAiU3RhdGVOYW1lIiBWQVJDSEFSKDMyKSBOT1QgTlVMTCwKICAiTGFzdE1vdGlmeVRpbWUiIEJJR0lOVCBOT1QgTlVMTCBERUZBVUxUIDAsCiAgIkxhc3RNb3RpZnlUaW1lU3RyaW5nIiBWQVJDSEFSKDMyKSBOT1QgTlVMTCBERUZBVUxUICcnLAogIENPTlNUUkFJTlQgIlBLX2NpdGFkZWwuc3Vic2NyaXB0aW9ucyIgUFJJTUFSWSBLRVkgKCJJRCIpCik7CkNSRUFURSBJTkRFWCBJRiBOT1QgRVhJU1RTICJJWF9jaXRhZGVsLnN1YnNjcmlwdGlvbnNfTWVzc2FnZUlEIiBPTiAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIiAoIk1lc3NhZ2VJRCIsICJSZWNlaXZlclRhZyIpOwoKQ1JFQVRFIFRBQkxFIElGIE5PVCBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwuZmxvd3MiICgKICAiSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJTdWJzY3JpcHRpb25JRCIgVkFSQ0hBUigzNikgTk9UIE5VTEwsCiAgIlN0YXRlTmFtZSIgVkFSQ0hBUigzMikgTk9UIE5VTEwsCiAgIlJlbWFyayIgVEVYVCBOT1QgTlVMTCBERUZBVUxUICcnLAogICJDcmVhdGlvblRpbWUiIEJJR0lOVCBOT1QgTlVMTCwKICAiQ3JlYXRpb25UaW1lU3RyaW5nIiBWQVJDSEFSKDMyKSBOT1QgTlVMTCwKICBDT05TVFJBSU5UICJQS19jaXRhZGVsLmZsb3dzIiBQUklNQVJZIEtFWSAoIklEIikKKTsKQ1JFQVRFIElOREVYIElGIE5PVCBFWElTVFMgIklYX2NpdGFkZWwuZmxvd3NfU3Vic2NyaXB0aW9uSUQiIE9OICIke1NDSEVNQX0iLiJjaXRhZGVsLmZsb3dzIiAoIlN1YnNjcmlwdGlvbklEIiwgIkNyZWF0aW9uVGltZSIpOwoKQ1JFQVRFIFRBQkxFIElGIE5PVCBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwuZXZlbnRzIiAoCiAgIklEIiBWQVJDSEFSKDM2KSBOT1QgTlVMTCwKICAiTWVzc2FnZUlEIiBWQVJDSEFSKDM2KSBOT1QgTlVMTCwKICAiRXhjaGFuZ2UiIFZBUkNIQVIoMjU1KSBOT1QgTlVMTCwKICAiUm91dGVLZXkiIFZBUkNIQVIoMjU1KSBOT1QgTlVMTCwKICAiUXVldWUiIFZBUkNIQVIoMjU1KSBOVUxMLAogIENPTlNUUkFJTlQgIlBLX2NpdGFkZWwuZXZlbnRzIiBQUklNQVJZIEtFWSAoIklEIikKKTsKQ1JFQVRFIFVOSVFVRSBJTkRFWCBJRiBOT1QgRVhJU1RTICJVWF9jaXRhZGVsLmV2ZW50c19NZXNzYWdlSUQiIE9OICIke1NDSEVNQX0iLiJjaXRhZGVsLmV2ZW50cyIgKCJNZXNzYWdlSUQiKTsKCkNSRUFURSBUQUJMRSBJRiBOT1QgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLmpvYnMiICgKICAiSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJNZXNzYWdlSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJFeHByZXNzaW9uIiBWQVJDSEFSKDI1NSkgTk9UIE5VTEwgREVGQVVMVCAnJywKICAiS2luZCIgU01BTExJTlQgTk9UIE5VTEwsCiAgIktpbmROYW1lIiBWQVJDSEFSKDMyKSBOT1QgTlVMTCwKICAiRGVsYXlTZWNvbmRzIiBJTlQgTk9UIE5VTEwgREVGQVVMVCAwLAogIENPTlNUUkFJTlQgIlBLX2NpdGFkZWwuam9icyIgUFJJTUFSWSBLRVkgKCJJRCIpCik7CkNSRUFURSBVTklRVUUgSU5ERVggSUYgTk9UIEVYSVNUUyAiVVhfY2l0YWRlbC5qb2JzX01lc3NhZ2VJRCIgT04gIiR7U0NIRU1BfSIuImNpdGFkZWwuam9icyIgKCJNZXNzYWdlSUQiKTsKCkNSRUFURSBUQUJMRSBJRiBOT1QgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1Yl90ZW1wbGF0ZXMiICgKICAiSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJOYW1lIiBWQVJDSEFSKDEyOCkgTk9UIE5VTEwsCiAgIkRlc2NyaXB0aW9uIiBURVhUIE5PVCBOVUxMIERFRkFVTFQgJycsCiAgIkNyZWF0aW9uVGltZSIgQklHSU5UIE5PVCBOVUxMLAogICJDcmVhdGlvblRpbWVTdHJpbmciIFZBUkNIQVIoMzIpIE5PVCBOVUxMLAogIENPTlNUUkFJTlQgIlBLX2NpdGFkZWwuc3ViX3RlbXBsYXRlcyIgUFJJTUFSWSBLRVkgKCJJRCIpCik7CkNSRUFURSBVTklRVUUgSU5ERVggSUYgTk9UIEVYSVNUUyAiVVhfY2l0YWRlbC5zdWJfdGVtcGxhdGVzX05hbWUiIE9OICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1Yl90ZW1wbGF0ZXMiICgiTmFtZSIpOwoKQ1JFQVRFIFRBQkxFIElGIE5PVCBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3ViX3RlbXBsYXRlX2RldGFpbHMiICgKICAiSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJUZW1wbGF0ZUlEIiBWQVJDSEFSKDM2KSBOT1QgTlVMTCwKICAiUmVjZWl2ZXJUYWciIFZBUkNIQVIoMTI4KSBOT1QgTlVMTCwKICAiRXhjaGFuZ2UiIFZBUkNIQVIoMjU1KSBOT1QgTlVMTCwKICAiUm91dGVLZXkiIFZBUkNIQVIoMjU1KSBOT1QgTlVMTCwKICAiQ3JlYXRpb25UaW1lIiBCSUdJTlQgTk9UIE5VTEwsCiAgIkNyZWF0aW9uVGltZVN0cmluZyIgVkFSQ0hBUigzMikgTk9UIE5VTEwsCiAgQ09OU1RSQUlOVCAiUEtfY2l0YWRlbC5zdWJfdGVtcGxhdGVfZGV0YWlscyIgUFJJTUFSWSBLRVkgKCJJRCIpCik7CkNSRUFURSBJTkRFWCBJRiBOT1QgRVhJU1RTICJJWF9jaXRhZGVsLnN1Yl90ZW1wbGF0ZV9kZXRhaWxzX1RlbXBsYXRlSUQiIE9OICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1Yl90ZW1wbGF0ZV9kZXRhaWxzIiAoIlRlbXBsYXRlSUQiKTsK")
+
+	r.Store("0002_job_scheduling.down.sql", "RFJPUCBJTkRFWCBJRiBFWElTVFMgIiR7U0NIRU1BfSIuIklYX2NpdGFkZWwuam9ic19OZXh0RmlyZVRpbWUiOwpBTFRFUiBUQUJMRSAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5qb2JzIiBEUk9QIENPTFVNTiBJRiBFWElTVFMgIlJldHJ5UG9saWN5IjsKQUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwuam9icyIgRFJPUCBDT0xVTU4gSUYgRVhJU1RTICJOZXh0RmlyZVRpbWUiOwo=")
+
+	r.Store("0002_job_scheduling.up.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwuam9icyIgQUREIENPTFVNTiBJRiBOT1QgRVhJU1RTICJOZXh0RmlyZVRpbWUiIEJJR0lOVCBOVUxMOwpBTFRFUiBUQUJMRSAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5qb2JzIiBBREQgQ09MVU1OIElGIE5PVCBFWElTVFMgIlJldHJ5UG9saWN5IiBURVhUIE5VTEw7CkNSRUFURSBJTkRFWCBJRiBOT1QgRVhJU1RTICJJWF9jaXRhZGVsLmpvYnNfTmV4dEZpcmVUaW1lIiBPTiAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5qb2JzIiAoIk5leHRGaXJlVGltZSIpIFdIRVJFICJOZXh0RmlyZVRpbWUiIElTIE5PVCBOVUxMOwo=")
+
+	r.Store("0003_message_log_remark.down.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZV9sb2dzIiBEUk9QIENPTFVNTiBJRiBFWElTVFMgIlJlbWFyayI7Cg==")
+
+	r.Store("0003_message_log_remark.up.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZV9sb2dzIiBBREQgQ09MVU1OIElGIE5PVCBFWElTVFMgIlJlbWFyayIgVEVYVCBOVUxMOwo=")
+
+	r.Store("0004_message_env.down.sql", "RFJPUCBJTkRFWCBJRiBFWElTVFMgIiR7U0NIRU1BfSIuIklYX2NpdGFkZWwubWVzc2FnZXNfRW52IjsK")
+
+	r.Store("0004_message_env.up.sql", "Q1JFQVRFIElOREVYIElGIE5PVCBFWElTVFMgIklYX2NpdGFkZWwubWVzc2FnZXNfRW52IiBPTiAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIgKCJFbnYiKTsK")
+
+	r.Store("0005_message_trace.down.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIERST1AgQ09MVU1OIElGIEVYSVNUUyAiVHJhY2VTdGF0ZSI7CkFMVEVSIFRBQkxFICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIiBEUk9QIENPTFVNTiBJRiBFWElTVFMgIlRyYWNlUGFyZW50IjsK")
+
+	r.Store("0005_message_trace.up.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIEFERCBDT0xVTU4gSUYgTk9UIEVYSVNUUyAiVHJhY2VQYXJlbnQiIFZBUkNIQVIoNjQpIE5PVCBOVUxMIERFRkFVTFQgJyc7CkFMVEVSIFRBQkxFICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIiBBREQgQ09MVU1OIElGIE5PVCBFWElTVFMgIlRyYWNlU3RhdGUiIFZBUkNIQVIoNTEyKSBOT1QgTlVMTCBERUZBVUxUICcnOwo=")
+
+	r.Store("0006_job_occurrence.down.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwuam9icyIgRFJPUCBDT0xVTU4gSUYgRVhJU1RTICJPY2N1cnJlbmNlVGltZSI7Cg==")
+
+	r.Store("0006_job_occurrence.up.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwuam9icyIgQUREIENPTFVNTiBJRiBOT1QgRVhJU1RTICJPY2N1cnJlbmNlVGltZSIgQklHSU5UIE5VTEw7Cg==")
+
+	r.Store("mysql/0001_init.down.sql", "RFJPUCBUQUJMRSBJRiBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3ViX3RlbXBsYXRlX2RldGFpbHMiOwpEUk9QIFRBQkxFIElGIEVYSVNUUyAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJfdGVtcGxhdGVzIjsKRFJPUCBUQUJMRSBJRiBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwuam9icyI7CkRST1AgVEFCTEUgSUYgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLmV2ZW50cyI7CkRST1AgVEFCTEUgSUYgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLmZsb3dzIjsKRFJPUCBUQUJMRSBJRiBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyI7CkRST1AgVEFCTEUgSUYgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VfbG9ncyI7CkRST1AgVEFCTEUgSUYgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIjsK")
+
+	r.Store("mysql/0001_init.up.sql", "Q1JFQVRFIFRBQkxFIElGIE5PVCBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiICgKICAiSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJNZXNzYWdlVHlwZSIgVkFSQ0hBUig2NCkgTk9UIE5VTEwsCiAgIkNvbnRlbnQiIFRFWFQgTk9UIE5VTEwsCiAgIlN0YXRlIiBTTUFMTElOVCBOT1QgTlVMTCwKICAiU3RhdGVOYW1lIiBWQVJDSEFSKDMyKSBOT1QgTlVMTCwKICAiUmV0cnkiIElOVCBOT1QgTlVMTCBERUZBVUxUIDAsCiAgIkNyZWF0aW9uVGltZSIgQklHSU5UIE5PVCBOVUxMLAogICJDcmVhdGlvblRpbWVTdHJpbmciIFZBUkNIQVIoMzIpIE5PVCBOVUxMLAogICJQdWJsaXNoZXIiIFZBUkNIQVIoMTI4KSBOT1QgTlVMTCBERUZBVUxUICcnLAogICJQdWJsaXNoVGltZSIgQklHSU5UIE5PVCBOVUxMIERFRkFVTFQgMCwKICAiUHVibGlzaFRpbWVTdHJpbmciIFZBUkNIQVIoMzIpIE5PVCBOVUxMIERFRkFVTFQgJycsCiAgIkVudiIgVkFSQ0hBUigzMikgTk9UIE5VTEwgREVGQVVMVCAnJywKICBDT05TVFJBSU5UICJQS19jaXRhZGVsLm1lc3NhZ2VzIiBQUklNQVJZIEtFWSAoIklEIiksCiAgSU5ERVggIklYX2NpdGFkZWwubWVzc2FnZXNfTWVzc2FnZVR5cGVfU3RhdGUiICgiTWVzc2FnZVR5cGUiLCAiU3RhdGUiLCAiQ3JlYXRpb25UaW1lIikKKTsKCkNSRUFURSBUQUJMRSBJRiBOT1QgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VfbG9ncyIgKAogICJJRCIgVkFSQ0hBUigzNikgTk9UIE5VTEwsCiAgIk1lc3NhZ2VJRCIgVkFSQ0hBUigzNikgTk9UIE5VTEwsCiAgIk9yaWduYWxTdGF0ZSIgU01BTExJTlQgTk9UIE5VTEwsCiAgIk9yaWduYWxTdGF0ZU5hbWUiIFZBUkNIQVIoMzIpIE5PVCBOVUxMLAogICJTdGF0ZSIgU01BTExJTlQgTk9UIE5VTEwsCiAgIlN0YXRlTmFtZSIgVkFSQ0hBUigzMikgTk9UIE5VTEwsCiAgIkNyZWF0aW9uVGltZSIgQklHSU5UIE5PVCBOVUxMLAogICJDcmVhdGlvblRpbWVTdHJpbmciIFZBUkNIQVIoMzIpIE5PVCBOVUxMLAogIENPTlNUUkFJTlQgIlBLX2NpdGFkZWwubWVzc2FnZV9sb2dzIiBQUklNQVJZIEtFWSAoIklEIiksCiAgSU5ERVggIklYX2NpdGFkZWwubWVzc2FnZV9sb2dzX01lc3NhZ2VJRCIgKCJNZXNzYWdlSUQiLCAiQ3JlYXRpb25UaW1lIikKKTsKCkNSRUFURSBUQUJMRSBJRiBOT1QgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1YnNjcmlwdGlvbnMiICgKICAiSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJNZXNzYWdlSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJSZWNlaXZlclRhZyIgVkFSQ0hBUigxMjgpIE5PVCBOVUxMLAogICJFeGNoYW5nZSIgVkFSQ0hBUigyNTUpIE5PVCBOVUxMLAogICJSb3V0ZUtleSIgVkFSQ0hBUigyNTUpIE5PVCBOVUxMLAogICJTdGF0ZU5hbWUiIFZBUkNIQVIoMzIpIE5PVCBOVUxMLAogICJMYXN0TW90aWZ5VGltZSIgQklHSU5UIE5PVCBOVUxMIERFRkFVTFQgMCwKICAiTGFzdE1vdGlmeVRpbWVTdHJpbmciIFZBUkNIQVIoMzIpIE5PVCBOVUxMIERFRkFVTFQgJycsCiAgQ09OU1RSQUlOVCAiUEtfY2l0YWRlbC5zdWJzY3JpcHRpb25zIiBQUklNQVJZIEtFWSAoIklEIiksCiAgSU5ERVggIklYX2NpdGFkZWwuc3Vic2NyaXB0aW9uc19NZXNzYWdlSUQiICgiTWVzc2FnZUlEIiwgIlJlY2VpdmVyVGFnIikKKTsKCkNSRUFURSBUQUJMRSBJRiBOT1QgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLmZsb3dzIiAoCiAgIklEIiBWQVJDSEFSKDM2KSBOT1QgTlVMTCwKICAiU3Vic2NyaXB0aW9uSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJTdGF0ZU5hbWUiIFZBUkNIQVIoMzIpIE5PVCBOVUxMLAogICJSZW1hcmsiIFRFWFQgTk9UIE5VTEwsCiAgIkNyZWF0aW9uVGltZSIgQklHSU5UIE5PVCBOVUxMLAogICJDcmVhdGlvblRpbWVTdHJpbmciIFZBUkNIQVIoMzIpIE5PVCBOVUxMLAogIENPTlNUUkFJTlQgIlBLX2NpdGFkZWwuZmxvd3MiIFBSSU1BUlkgS0VZICgiSUQiKSwKICBJTkRFWCAiSVhfY2l0YWRlbC5mbG93c19TdWJzY3JpcHRpb25JRCIgKCJTdWJzY3JpcHRpb25JRCIsICJDcmVhdGlvblRpbWUiKQopOwoKQ1JFQVRFIFRBQkxFIElGIE5PVCBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwuZXZlbnRzIiAoCiAgIklEIiBWQVJDSEFSKDM2KSBOT1QgTlVMTCwKICAiTWVzc2FnZUlEIiBWQVJDSEFSKDM2KSBOT1QgTlVMTCwKICAiRXhjaGFuZ2UiIFZBUkNIQVIoMjU1KSBOT1QgTlVMTCwKICAiUm91dGVLZXkiIFZBUkNIQVIoMjU1KSBOT1QgTlVMTCwKICAiUXVldWUiIFZBUkNIQVIoMjU1KSBOVUxMLAogIENPTlNUUkFJTlQgIlBLX2NpdGFkZWwuZXZlbnRzIiBQUklNQVJZIEtFWSAoIklEIiksCiAgVU5JUVVFIElOREVYICJVWF9jaXRhZGVsLmV2ZW50c19NZXNzYWdlSUQiICgiTWVzc2FnZUlEIikKKTsKCkNSRUFURSBUQUJMRSBJRiBOT1QgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLmpvYnMiICgKICAiSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJNZXNzYWdlSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJFeHByZXNzaW9uIiBWQVJDSEFSKDI1NSkgTk9UIE5VTEwgREVGQVVMVCAnJywKICAiS2luZCIgU01BTExJTlQgTk9UIE5VTEwsCiAgIktpbmROYW1lIiBWQVJDSEFSKDMyKSBOT1QgTlVMTCwKICAiRGVsYXlTZWNvbmRzIiBJTlQgTk9UIE5VTEwgREVGQVVMVCAwLAogIENPTlNUUkFJTlQgIlBLX2NpdGFkZWwuam9icyIgUFJJTUFSWSBLRVkgKCJJRCIpLAogIFVOSVFVRSBJTkRFWCAiVVhfY2l0YWRlbC5qb2JzX01lc3NhZ2VJRCIgKCJNZXNzYWdlSUQiKQopOwoKQ1JFQVRFIFRBQkxFIElGIE5PVCBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3ViX3RlbXBsYXRlcyIgKAogICJJRCIgVkFSQ0hBUigzNikgTk9UIE5VTEwsCiAgIk5hbWUiIFZBUkNIQVIoMTI4KSBOT1QgTlVMTCwKICAiRGVzY3JpcHRpb24iIFRFWFQgTk9UIE5VTEwsCiAgIkNyZWF0aW9uVGltZSIgQklHSU5UIE5PVCBOVUxMLAogICJDcmVhdGlvblRpbWVTdHJpbmciIFZBUkNIQVIoMzIpIE5PVCBOVUxMLAogIENPTlNUUkFJTlQgIlBLX2NpdGFkZWwuc3ViX3RlbXBsYXRlcyIgUFJJTUFSWSBLRVkgKCJJRCIpLAogIFVOSVFVRSBJTkRFWCAiVVhfY2l0YWRlbC5zdWJfdGVtcGxhdGVzX05hbWUiICgiTmFtZSIpCik7CgpDUkVBVEUgVEFCTEUgSUYgTk9UIEVYSVNUUyAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJfdGVtcGxhdGVfZGV0YWlscyIgKAogICJJRCIgVkFSQ0hBUigzNikgTk9UIE5VTEwsCiAgIlRlbXBsYXRlSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJSZWNlaXZlclRhZyIgVkFSQ0hBUigxMjgpIE5PVCBOVUxMLAogICJFeGNoYW5nZSIgVkFSQ0hBUigyNTUpIE5PVCBOVUxMLAogICJSb3V0ZUtleSIgVkFSQ0hBUigyNTUpIE5PVCBOVUxMLAogICJDcmVhdGlvblRpbWUiIEJJR0lOVCBOT1QgTlVMTCwKICAiQ3JlYXRpb25UaW1lU3RyaW5nIiBWQVJDSEFSKDMyKSBOT1QgTlVMTCwKICBDT05TVFJBSU5UICJQS19jaXRhZGVsLnN1Yl90ZW1wbGF0ZV9kZXRhaWxzIiBQUklNQVJZIEtFWSAoIklEIiksCiAgSU5ERVggIklYX2NpdGFkZWwuc3ViX3RlbXBsYXRlX2RldGFpbHNfVGVtcGxhdGVJRCIgKCJUZW1wbGF0ZUlEIikKKTsK")
+
+	r.Store("mysql/0002_job_scheduling.down.sql", "RFJPUCBJTkRFWCAiSVhfY2l0YWRlbC5qb2JzX05leHRGaXJlVGltZSIgT04gIiR7U0NIRU1BfSIuImNpdGFkZWwuam9icyI7CkFMVEVSIFRBQkxFICIke1NDSEVNQX0iLiJjaXRhZGVsLmpvYnMiIERST1AgQ09MVU1OICJSZXRyeVBvbGljeSI7CkFMVEVSIFRBQkxFICIke1NDSEVNQX0iLiJjaXRhZGVsLmpvYnMiIERST1AgQ09MVU1OICJOZXh0RmlyZVRpbWUiOwo=")
+
+	r.Store("mysql/0002_job_scheduling.up.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwuam9icyIgQUREIENPTFVNTiAiTmV4dEZpcmVUaW1lIiBCSUdJTlQgTlVMTDsKQUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwuam9icyIgQUREIENPTFVNTiAiUmV0cnlQb2xpY3kiIFRFWFQgTlVMTDsKQ1JFQVRFIElOREVYICJJWF9jaXRhZGVsLmpvYnNfTmV4dEZpcmVUaW1lIiBPTiAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5qb2JzIiAoIk5leHRGaXJlVGltZSIpOwo=")
+
+	r.Store("mysql/0003_message_log_remark.down.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZV9sb2dzIiBEUk9QIENPTFVNTiAiUmVtYXJrIjsK")
+
+	r.Store("mysql/0003_message_log_remark.up.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZV9sb2dzIiBBREQgQ09MVU1OICJSZW1hcmsiIFRFWFQgTlVMTDsK")
+
+	r.Store("mysql/0004_message_env.down.sql", "RFJPUCBJTkRFWCAiSVhfY2l0YWRlbC5tZXNzYWdlc19FbnYiIE9OICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIjsK")
+
+	r.Store("mysql/0004_message_env.up.sql", "Q1JFQVRFIElOREVYICJJWF9jaXRhZGVsLm1lc3NhZ2VzX0VudiIgT04gIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiICgiRW52Iik7Cg==")
+
+	r.Store("mysql/0005_message_trace.down.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIERST1AgQ09MVU1OICJUcmFjZVN0YXRlIjsKQUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIERST1AgQ09MVU1OICJUcmFjZVBhcmVudCI7Cg==")
+
+	r.Store("mysql/0005_message_trace.up.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIEFERCBDT0xVTU4gIlRyYWNlUGFyZW50IiBWQVJDSEFSKDY0KSBOT1QgTlVMTCBERUZBVUxUICcnOwpBTFRFUiBUQUJMRSAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIgQUREIENPTFVNTiAiVHJhY2VTdGF0ZSIgVkFSQ0hBUig1MTIpIE5PVCBOVUxMIERFRkFVTFQgJyc7Cg==")
+
+	r.Store("mysql/0006_job_occurrence.down.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwuam9icyIgRFJPUCBDT0xVTU4gIk9jY3VycmVuY2VUaW1lIjsK")
+
+	r.Store("mysql/0006_job_occurrence.up.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwuam9icyIgQUREIENPTFVNTiAiT2NjdXJyZW5jZVRpbWUiIEJJR0lOVCBOVUxMOwo=")
+
+	r.Store("sqlite/0001_init.down.sql", "RFJPUCBUQUJMRSBJRiBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3ViX3RlbXBsYXRlX2RldGFpbHMiOwpEUk9QIFRBQkxFIElGIEVYSVNUUyAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJfdGVtcGxhdGVzIjsKRFJPUCBUQUJMRSBJRiBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwuam9icyI7CkRST1AgVEFCTEUgSUYgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLmV2ZW50cyI7CkRST1AgVEFCTEUgSUYgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLmZsb3dzIjsKRFJPUCBUQUJMRSBJRiBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyI7CkRST1AgVEFCTEUgSUYgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VfbG9ncyI7CkRST1AgVEFCTEUgSUYgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIjsK")
+
+	r.Store("sqlite/0001_init.up.sql", "Q1JFQVRFIFRBQkxFIElGIE5PVCBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiICgKICAiSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJNZXNzYWdlVHlwZSIgVkFSQ0hBUig2NCkgTk9UIE5VTEwsCiAgIkNvbnRlbnQiIFRFWFQgTk9UIE5VTEwgREVGQVVMVCAnJywKICAiU3RhdGUiIElOVEVHRVIgTk9UIE5VTEwsCiAgIlN0YXRlTmFtZSIgVkFSQ0hBUigzMikgTk9UIE5VTEwsCiAgIlJldHJ5IiBJTlRFR0VSIE5PVCBOVUxMIERFRkFVTFQgMCwKICAiQ3JlYXRpb25UaW1lIiBJTlRFR0VSIE5PVCBOVUxMLAogICJDcmVhdGlvblRpbWVTdHJpbmciIFZBUkNIQVIoMzIpIE5PVCBOVUxMLAogICJQdWJsaXNoZXIiIFZBUkNIQVIoMTI4KSBOT1QgTlVMTCBERUZBVUxUICcnLAogICJQdWJsaXNoVGltZSIgSU5URUdFUiBOT1QgTlVMTCBERUZBVUxUIDAsCiAgIlB1Ymxpc2hUaW1lU3RyaW5nIiBWQVJDSEFSKDMyKSBOT1QgTlVMTCBERUZBVUxUICcnLAogICJFbnYiIFZBUkNIQVIoMzIpIE5PVCBOVUxMIERFRkFVTFQgJycsCiAgQ09OU1RSQUlOVCAiUEtfY2l0YWRlbC5tZXNzYWdlcyIgUFJJTUFSWSBLRVkgKCJJRCIpCik7CkNSRUFURSBJTkRFWCBJRiBOT1QgRVhJU1RTICIke1NDSEVNQX0iLiJJWF9jaXRhZGVsLm1lc3NhZ2VzX01lc3NhZ2VUeXBlX1N0YXRlIiBPTiAiY2l0YWRlbC5tZXNzYWdlcyIgKCJNZXNzYWdlVHlwZSIsICJTdGF0ZSIsICJDcmVhdGlvblRpbWUiKTsKCkNSRUFURSBUQUJMRSBJRiBOT1QgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VfbG9ncyIgKAogICJJRCIgVkFSQ0hBUigzNikgTk9UIE5VTEwsCiAgIk1lc3NhZ2VJRCIgVkFSQ0hBUigzNikgTk9UIE5VTEwsCiAgIk9yaWduYWxTdGF0ZSIgSU5URUdFUiBOT1QgTlVMTCwKICAiT3JpZ25hbFN0YXRlTmFtZSIgVkFSQ0hBUigzMikgTk9UIE5VTEwsCiAgIlN0YXRlIiBJTlRFR0VSIE5PVCBOVUxMLAogICJTdGF0ZU5hbWUiIFZBUkNIQVIoMzIpIE5PVCBOVUxMLAogICJDcmVhdGlvblRpbWUiIElOVEVHRVIgTk9UIE5VTEwsCiAgIkNyZWF0aW9uVGltZVN0cmluZyIgVkFSQ0hBUigzMikgTk9UIE5VTEwsCiAgQ09OU1RSQUlOVCAiUEtfY2l0YWRlbC5tZXNzYWdlX2xvZ3MiIFBSSU1BUlkgS0VZICgiSUQiKQopOwpDUkVBVEUgSU5ERVggSUYgTk9UIEVYSVNUUyAiJHtTQ0hFTUF9Ii4iSVhfY2l0YWRlbC5tZXNzYWdlX2xvZ3NfTWVzc2FnZUlEIiBPTiAiY2l0YWRlbC5tZXNzYWdlX2xvZ3MiICgiTWVzc2FnZUlEIiwgIkNyZWF0aW9uVGltZSIpOwoKQ1JFQVRFIFRBQkxFIElGIE5PVCBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyIgKAogICJJRCIgVkFSQ0hBUigzNikgTk9UIE5VTEwsCiAgIk1lc3NhZ2VJRCIgVkFSQ0hBUigzNikgTk9UIE5VTEwsCiAgIlJlY2VpdmVyVGFnIiBWQVJDSEFSKDEyOCkgTk9UIE5VTEwsCiAgIkV4Y2hhbmdlIiBWQVJDSEFSKDI1NSkgTk9UIE5VTEwsCiAgIlJvdXRlS2V5IiBWQVJDSEFSKDI1NSkgTk9UIE5VTEwsCiAgIlN0YXRlTmFtZSIgVkFSQ0hBUigzMikgTk9UIE5VTEwsCiAgIkxhc3RNb3RpZnlUaW1lIiBJTlRFR0VSIE5PVCBOVUxMIERFRkFVTFQgMCwKICAiTGFzdE1vdGlmeVRpbWVTdHJpbmciIFZBUkNIQVIoMzIpIE5PVCBOVUxMIERFRkFVTFQgJycsCiAgQ09OU1RSQUlOVCAiUEtfY2l0YWRlbC5zdWJzY3JpcHRpb25zIiBQUklNQVJZIEtFWSAoIklEIikKKTsKQ1JFQVRFIElOREVYIElGIE5PVCBFWElTVFMgIiR7U0NIRU1BfSIuIklYX2NpdGFkZWwuc3Vic2NyaXB0aW9uc19NZXNzYWdlSUQiIE9OICJjaXRhZGVsLnN1YnNjcmlwdGlvbnMiICgiTWVzc2FnZUlEIiwgIlJlY2VpdmVyVGFnIik7CgpDUkVBVEUgVEFCTEUgSUYgTk9UIEVYSVNUUyAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5mbG93cyIgKAogICJJRCIgVkFSQ0hBUigzNikgTk9UIE5VTEwsCiAgIlN1YnNjcmlwdGlvbklEIiBWQVJDSEFSKDM2KSBOT1QgTlVMTCwKICAiU3RhdGVOYW1lIiBWQVJDSEFSKDMyKSBOT1QgTlVMTCwKICAiUmVtYXJrIiBURVhUIE5PVCBOVUxMIERFRkFVTFQgJycsCiAgIkNyZWF0aW9uVGltZSIgSU5URUdFUiBOT1QgTlVMTCwKICAiQ3JlYXRpb25UaW1lU3RyaW5nIiBWQVJDSEFSKDMyKSBOT1QgTlVMTCwKICBDT05TVFJBSU5UICJQS19jaXRhZGVsLmZsb3dzIiBQUklNQVJZIEtFWSAoIklEIikKKTsKQ1JFQVRFIElOREVYIElGIE5PVCBFWElTVFMgIiR7U0NIRU1BfSIuIklYX2NpdGFkZWwuZmxvd3NfU3Vic2NyaXB0aW9uSUQiIE9OICJjaXRhZGVsLmZsb3dzIiAoIlN1YnNjcmlwdGlvbklEIiwgIkNyZWF0aW9uVGltZSIpOwoKQ1JFQVRFIFRBQkxFIElGIE5PVCBFWElTVFMgIiR7U0NIRU1BfSIuImNpdGFkZWwuZXZlbnRzIiAoCiAgIklEIiBWQVJDSEFSKDM2KSBOT1QgTlVMTCwKICAiTWVzc2FnZUlEIiBWQVJDSEFSKDM2KSBOT1QgTlVMTCwKICAiRXhjaGFuZ2UiIFZBUkNIQVIoMjU1KSBOT1QgTlVMTCwKICAiUm91dGVLZXkiIFZBUkNIQVIoMjU1KSBOT1QgTlVMTCwKICAiUXVldWUiIFZBUkNIQVIoMjU1KSBOVUxMLAogIENPTlNUUkFJTlQgIlBLX2NpdGFkZWwuZXZlbnRzIiBQUklNQVJZIEtFWSAoIklEIikKKTsKQ1JFQVRFIFVOSVFVRSBJTkRFWCBJRiBOT1QgRVhJU1RTICIke1NDSEVNQX0iLiJVWF9jaXRhZGVsLmV2ZW50c19NZXNzYWdlSUQiIE9OICJjaXRhZGVsLmV2ZW50cyIgKCJNZXNzYWdlSUQiKTsKCkNSRUFURSBUQUJMRSBJRiBOT1QgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLmpvYnMiICgKICAiSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJNZXNzYWdlSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJFeHByZXNzaW9uIiBWQVJDSEFSKDI1NSkgTk9UIE5VTEwgREVGQVVMVCAnJywKICAiS2luZCIgSU5URUdFUiBOT1QgTlVMTCwKICAiS2luZE5hbWUiIFZBUkNIQVIoMzIpIE5PVCBOVUxMLAogICJEZWxheVNlY29uZHMiIElOVEVHRVIgTk9UIE5VTEwgREVGQVVMVCAwLAogIENPTlNUUkFJTlQgIlBLX2NpdGFkZWwuam9icyIgUFJJTUFSWSBLRVkgKCJJRCIpCik7CkNSRUFURSBVTklRVUUgSU5ERVggSUYgTk9UIEVYSVNUUyAiJHtTQ0hFTUF9Ii4iVVhfY2l0YWRlbC5qb2JzX01lc3NhZ2VJRCIgT04gImNpdGFkZWwuam9icyIgKCJNZXNzYWdlSUQiKTsKCkNSRUFURSBUQUJMRSBJRiBOT1QgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1Yl90ZW1wbGF0ZXMiICgKICAiSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMLAogICJOYW1lIiBWQVJDSEFSKDEyOCkgTk9UIE5VTEwsCiAgIkRlc2NyaXB0aW9uIiBURVhUIE5PVCBOVUxMIERFRkFVTFQgJycsCiAgIkNyZWF0aW9uVGltZSIgSU5URUdFUiBOT1QgTlVMTCwKICAiQ3JlYXRpb25UaW1lU3RyaW5nIiBWQVJDSEFSKDMyKSBOT1QgTlVMTCwKICBDT05TVFJBSU5UICJQS19jaXRhZGVsLnN1Yl90ZW1wbGF0ZXMiIFBSSU1BUlkgS0VZICgiSUQiKQopOwpDUkVBVEUgVU5JUVVFIElOREVYIElGIE5PVCBFWElTVFMgIiR7U0NIRU1BfSIuIlVYX2NpdGFkZWwuc3ViX3RlbXBsYXRlc19OYW1lIiBPTiAiY2l0YWRlbC5zdWJfdGVtcGxhdGVzIiAoIk5hbWUiKTsKCkNSRUFURSBUQUJMRSBJRiBOT1QgRVhJU1RTICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1Yl90ZW1wbGF0ZV9kZXRhaWxzIiAoCiAgIklEIiBWQVJDSEFSKDM2KSBOT1QgTlVMTCwKICAiVGVtcGxhdGVJRCIgVkFSQ0hBUigzNikgTk9UIE5VTEwsCiAgIlJlY2VpdmVyVGFnIiBWQVJDSEFSKDEyOCkgTk9UIE5VTEwsCiAgIkV4Y2hhbmdlIiBWQVJDSEFSKDI1NSkgTk9UIE5VTEwsCiAgIlJvdXRlS2V5IiBWQVJDSEFSKDI1NSkgTk9UIE5VTEwsCiAgIkNyZWF0aW9uVGltZSIgSU5URUdFUiBOT1QgTlVMTCwKICAiQ3JlYXRpb25UaW1lU3RyaW5nIiBWQVJDSEFSKDMyKSBOT1QgTlVMTCwKICBDT05TVFJBSU5UICJQS19jaXRhZGVsLnN1Yl90ZW1wbGF0ZV9kZXRhaWxzIiBQUklNQVJZIEtFWSAoIklEIikKKTsKQ1JFQVRFIElOREVYIElGIE5PVCBFWElTVFMgIiR7U0NIRU1BfSIuIklYX2NpdGFkZWwuc3ViX3RlbXBsYXRlX2RldGFpbHNfVGVtcGxhdGVJRCIgT04gImNpdGFkZWwuc3ViX3RlbXBsYXRlX2RldGFpbHMiICgiVGVtcGxhdGVJRCIpOwo=")
+
+	r.Store("sqlite/0002_job_scheduling.down.sql", "RFJPUCBJTkRFWCBJRiBFWElTVFMgIiR7U0NIRU1BfSIuIklYX2NpdGFkZWwuam9ic19OZXh0RmlyZVRpbWUiOwpBTFRFUiBUQUJMRSAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5qb2JzIiBEUk9QIENPTFVNTiAiUmV0cnlQb2xpY3kiOwpBTFRFUiBUQUJMRSAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5qb2JzIiBEUk9QIENPTFVNTiAiTmV4dEZpcmVUaW1lIjsK")
+
+	r.Store("sqlite/0002_job_scheduling.up.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwuam9icyIgQUREIENPTFVNTiAiTmV4dEZpcmVUaW1lIiBJTlRFR0VSIE5VTEw7CkFMVEVSIFRBQkxFICIke1NDSEVNQX0iLiJjaXRhZGVsLmpvYnMiIEFERCBDT0xVTU4gIlJldHJ5UG9saWN5IiBURVhUIE5VTEw7CkNSRUFURSBJTkRFWCBJRiBOT1QgRVhJU1RTICIke1NDSEVNQX0iLiJJWF9jaXRhZGVsLmpvYnNfTmV4dEZpcmVUaW1lIiBPTiAiY2l0YWRlbC5qb2JzIiAoIk5leHRGaXJlVGltZSIpIFdIRVJFICJOZXh0RmlyZVRpbWUiIElTIE5PVCBOVUxMOwo=")
+
+	r.Store("sqlite/0003_message_log_remark.down.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZV9sb2dzIiBEUk9QIENPTFVNTiAiUmVtYXJrIjsK")
+
+	r.Store("sqlite/0003_message_log_remark.up.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZV9sb2dzIiBBREQgQ09MVU1OICJSZW1hcmsiIFRFWFQgTlVMTDsK")
+
+	r.Store("sqlite/0004_message_env.down.sql", "RFJPUCBJTkRFWCBJRiBFWElTVFMgIiR7U0NIRU1BfSIuIklYX2NpdGFkZWwubWVzc2FnZXNfRW52IjsK")
+
+	r.Store("sqlite/0004_message_env.up.sql", "Q1JFQVRFIElOREVYIElGIE5PVCBFWElTVFMgIiR7U0NIRU1BfSIuIklYX2NpdGFkZWwubWVzc2FnZXNfRW52IiBPTiAiY2l0YWRlbC5tZXNzYWdlcyIgKCJFbnYiKTsK")
+
+	r.Store("sqlite/0005_message_trace.down.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIERST1AgQ09MVU1OICJUcmFjZVN0YXRlIjsKQUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIERST1AgQ09MVU1OICJUcmFjZVBhcmVudCI7Cg==")
+
+	r.Store("sqlite/0005_message_trace.up.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIEFERCBDT0xVTU4gIlRyYWNlUGFyZW50IiBWQVJDSEFSKDY0KSBOT1QgTlVMTCBERUZBVUxUICcnOwpBTFRFUiBUQUJMRSAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIgQUREIENPTFVNTiAiVHJhY2VTdGF0ZSIgVkFSQ0hBUig1MTIpIE5PVCBOVUxMIERFRkFVTFQgJyc7Cg==")
+
+	r.Store("sqlite/0006_job_occurrence.down.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwuam9icyIgRFJPUCBDT0xVTU4gIk9jY3VycmVuY2VUaW1lIjsK")
+
+	r.Store("sqlite/0006_job_occurrence.up.sql", "QUxURVIgVEFCTEUgIiR7U0NIRU1BfSIuImNpdGFkZWwuam9icyIgQUREIENPTFVNTiAiT2NjdXJyZW5jZVRpbWUiIElOVEVHRVIgTlVMTDsK")
+
+	return r
+}
@@ -1,6 +1,9 @@
 package essentials
 
 type Payload struct {
+	// MessageID is the ID the message gets, set by the outbox relay from the
+	// outbox row. It never comes from a request body; the others get a new ID.
+	MessageID     string                 `json:"-"`
 	Env           string                 `json:"env"`
 	ClientTag     string                 `json:"client_tag"`
 	MessageType   string                 `json:"type"`
@@ -26,3 +29,11 @@ type ExtensionsEventArgs struct {
 	Extensions map[string]string
 	MessageID  string
 }
+
+type DeliveryMessage struct {
+	MessageID   string            `json:"message_id"`
+	MessageType string            `json:"message_type"`
+	Content     string            `json:"content"`
+	PublishTime int64             `json:"publish_time"`
+	Extensions  map[string]string `json:"exts"`
+}
@@ -0,0 +1,11 @@
+package essentials
+
+// ReplayPayload is the body of /v1/message/replay. Tags limits the replay to
+// the subscriptions of those receivers; empty means every subscription. Env
+// is used when the request has no `X-matcha-Env` header.
+type ReplayPayload struct {
+	MessageID string   `json:"message_id"`
+	Env       string   `json:"env"`
+	Tags      []string `json:"tags"`
+	Remark    string   `json:"remark"`
+}
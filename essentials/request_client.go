@@ -0,0 +1,52 @@
+package essentials
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+type requestClientKey struct{}
+
+// WithRequestClient returns request carrying the identity of the client
+// whose signature it was verified with.
+func WithRequestClient(request *http.Request, client string) *http.Request {
+	return request.WithContext(context.WithValue(request.Context(), requestClientKey{}, client))
+}
+
+// RequestClient returns the verified client of request, or "" when the
+// request was not signed.
+func RequestClient(request *http.Request) string {
+	if request == nil {
+		return ""
+	}
+	client, _ := request.Context().Value(requestClientKey{}).(string)
+	return client
+}
+
+// SetPublisher makes the verified client the publisher of the message,
+// replacing the `x-matcha-client` header and the `client_tag` the request
+// declared itself.
+func (p *Payload) SetPublisher(client string) {
+	if p.Headers == nil {
+		p.Headers = make(map[string]interface{})
+	}
+	p.Headers["x-matcha-client"] = client
+	p.ClientTag = client
+}
+
+// checkReceiver makes the verified client the subscriber of a state change.
+// A signed client can only change its own subscriptions.
+func (p *ChangeStatePayload) checkReceiver(client string) error {
+	if client == "" {
+		return nil
+	}
+	if p.ClientTag == "" {
+		p.ClientTag = client
+		return nil
+	}
+	if p.ClientTag != client {
+		return fmt.Errorf("client %s can not change the state of subscriber %s", client, p.ClientTag)
+	}
+	return nil
+}
@@ -1,22 +1,33 @@
 package essentials
 
-//creation_time:2017-12-20T10:59:48+08:00
+//creation_time:2026-10-18T11:06:07Z
 
+//change_job_fire_time.yml
+//change_job_occurrence.yml
 //change_message_state.yml
 //change_subscription_state.yml
+//create_outbox_table.yml
+//delete_sub_template.yml
+//delete_sub_template_details.yml
 //fetch_flows.yml
 //fetch_message_logs.yml
+//fetch_occurrence_deliveries.yml
 //fetch_sub_template_details.yml
 //fetch_subscriptions.yml
 //find_failed_event.yml
+//find_message_retry.yml
 //find_processing_message.yml
 //find_subscriptions.yml
 //find_unconfirmed_message.yml
+//find_unscheduled_jobs.yml
+//findone_due_job.yml
 //findone_event.yml
 //findone_failed_message.yml
+//findone_locked_job.yml
 //findone_locked_message.yml
 //findone_locked_subscription.yml
 //findone_messages.yml
+//findone_pending_outbox_row.yml
 //findone_rollback_message.yml
 //findone_subscription.yml
 //findone_succeed_message.yml
@@ -27,70 +38,146 @@ package essentials
 //insert_flow.yml
 //insert_message.yml
 //insert_message_log.yml
+//insert_outbox_row.yml
+//insert_sub_template.yml
+//insert_sub_template_detail.yml
 //insert_subscription.yml
 //list_events.yml
 //list_jobs.yml
+//list_sub_templates.yml
+//outbox_row_failed.yml
+//outbox_row_relayed.yml
 //published_message.yml
+//reset_message_retry.yml
+//reset_subscription_state.yml
+//update_sub_template.yml
+//mysql/create_outbox_table.yml
+//sqlite/findone_due_job.yml
+//sqlite/findone_failed_message.yml
+//sqlite/findone_locked_job.yml
+//sqlite/findone_locked_message.yml
+//sqlite/findone_locked_subscription.yml
+//sqlite/findone_pending_outbox_row.yml
+//sqlite/findone_rollback_message.yml
+//sqlite/findone_succeed_message.yml
 
 func NewScriptResources() *ScriptResources {
 	r := &ScriptResources{}
-	r.Store("change_message_state_yml", "bmFtZTogQ2hhbmdlTWVzc2FnZVN0YXRlDQoNCnNjcmlwdDoNCiAgVVBEQVRFIA0KICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIiANCiAgU0VUIA0KICAgICJTdGF0ZSIgPSAkMSwgDQogICAgIlN0YXRlTmFtZSIgPSAkMg0KICBXSEVSRSANCiAgICAiSUQiID0gJDM7DQo=")
+	r.Store("change_job_fire_time_yml", "bmFtZTogQ2hhbmdlSm9iRmlyZVRpbWUKCnNjcmlwdDoKICBVUERBVEUgCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5qb2JzIiAKICBTRVQgCiAgICAiTmV4dEZpcmVUaW1lIiA9ICQxCiAgV0hFUkUgCiAgICAiSUQiID0gJDI7Cg==")
 
-	r.Store("change_subscription_state_yml", "bmFtZTogQ2hhbmdlU3Vic2NyaXB0aW9uU3RhdGUNCg0Kc2NyaXB0Og0KICBVUERBVEUgDQogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyIgDQogIFNFVCANCiAgICAiU3RhdGVOYW1lIiA9ICQxLCANCiAgICAiTGFzdE1vdGlmeVRpbWUiID0gJDIsIA0KICAgICJMYXN0TW90aWZ5VGltZVN0cmluZyIgPSAkMw0KICBXSEVSRSANCiAgICAoKCJJRCIgPSAkNCkgDQogICAgT1IgDQogICAgKCJNZXNzYWdlSUQiID0gJDUgQU5EICJSZWNlaXZlclRhZyI9JDYpKQ0KICAgIEFORCAoIlN0YXRlTmFtZSIgIT0gJ0ZhaWxlZCcpOw0K")
+	r.Store("change_job_occurrence_yml", "bmFtZTogQ2hhbmdlSm9iT2NjdXJyZW5jZQoKc2NyaXB0OgogIFVQREFURSAKICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLmpvYnMiIAogIFNFVCAKICAgICJPY2N1cnJlbmNlVGltZSIgPSAkMQogIFdIRVJFIAogICAgIklEIiA9ICQyOwo=")
 
-	r.Store("fetch_flows_yml", "bmFtZTogRmV0Y2hGbG93cw0KDQpzY3JpcHQ6DQogIFNFTEVDVA0KICAgICJJRCIsIA0KICAgICJTdWJzY3JpcHRpb25JRCIsIA0KICAgICJTdGF0ZU5hbWUiLCANCiAgICAiUmVtYXJrIiwgDQogICAgIkNyZWF0aW9uVGltZSIsIA0KICAgICJDcmVhdGlvblRpbWVTdHJpbmciDQogIEZST00NCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5mbG93cyINCiAgV0hFUkUNCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5mbG93cyIuIlN1YnNjcmlwdGlvbklEIj0kMQ0KICBPUkRFUiBCWQ0KICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLmZsb3dzIi4iQ3JlYXRpb25UaW1lIiBBU0M=")
+	r.Store("change_message_state_yml", "bmFtZTogQ2hhbmdlTWVzc2FnZVN0YXRlCgpzY3JpcHQ6CiAgVVBEQVRFIAogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIAogIFNFVCAKICAgICJTdGF0ZSIgPSAkMSwgCiAgICAiU3RhdGVOYW1lIiA9ICQyCiAgV0hFUkUgCiAgICAiSUQiID0gJDM7Cg==")
 
-	r.Store("fetch_message_logs_yml", "bmFtZTogRmV0Y2hNZXNzYWdlTG9ncw0KDQpzY3JpcHQ6DQogIFNFTEVDVCANCiAgICAiSUQiLCANCiAgICAiTWVzc2FnZUlEIiwgDQogICAgIk9yaWduYWxTdGF0ZSIsIA0KICAgICJPcmlnbmFsU3RhdGVOYW1lIiwgDQogICAgIlN0YXRlIiwgDQogICAgIlN0YXRlTmFtZSIsIA0KICAgICJDcmVhdGlvblRpbWUiLCANCiAgICAiQ3JlYXRpb25UaW1lU3RyaW5nIg0KICBGUk9NDQogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZV9sb2dzIg0KICBXSEVSRQ0KICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VfbG9ncyIuIk1lc3NhZ2VJRCI9JDENCiAgT1JERVIgQlkNCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlX2xvZ3MiLiJDcmVhdGlvblRpbWUiIEFTQw==")
+	r.Store("change_subscription_state_yml", "bmFtZTogQ2hhbmdlU3Vic2NyaXB0aW9uU3RhdGUKCnNjcmlwdDoKICBVUERBVEUgCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIiAKICBTRVQgCiAgICAiU3RhdGVOYW1lIiA9ICQxLCAKICAgICJMYXN0TW90aWZ5VGltZSIgPSAkMiwgCiAgICAiTGFzdE1vdGlmeVRpbWVTdHJpbmciID0gJDMKICBXSEVSRSAKICAgICgoIklEIiA9ICQ0KSAKICAgIE9SIAogICAgKCJNZXNzYWdlSUQiID0gJDUgQU5EICJSZWNlaXZlclRhZyI9JDYpKQogICAgQU5EICgiU3RhdGVOYW1lIiAhPSAnRmFpbGVkJyk7Cg==")
 
-	r.Store("fetch_sub_template_details_yml", "bmFtZTogRmV0Y2hTdWJUZW1wbGF0ZURldGFpbHMNCg0Kc2NyaXB0Og0KICBTRUxFQ1QNCgkgICJJRCIsDQoJICAiVGVtcGxhdGVJRCIsDQoJICAiUmVjZWl2ZXJUYWciLA0KCSAgIkV4Y2hhbmdlIiwNCgkgICJSb3V0ZUtleSIsDQoJICAiQ3JlYXRpb25UaW1lIiwNCgkgICJDcmVhdGlvblRpbWVTdHJpbmciIA0KICBGUk9NDQoJICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJfdGVtcGxhdGVfZGV0YWlscyIgDQogIFdIRVJFDQoJICAiVGVtcGxhdGVJRCIgPSAkMQ==")
+	r.Store("create_outbox_table_yml", "bmFtZTogQ3JlYXRlT3V0Ym94VGFibGUKCnZhcmlhYmxlczogCiAgVEFCTEU6ICcibWF0Y2hhX291dGJveCInCiAgSU5ERVg6IG1hdGNoYV9vdXRib3gKCnNjcmlwdDoKICBDUkVBVEUgVEFCTEUgSUYgTk9UIEVYSVNUUyAke1RBQkxFfSAoCiAgICAiSUQiIFZBUkNIQVIoMzYpIE5PVCBOVUxMIFBSSU1BUlkgS0VZLAogICAgIktpbmQiIFZBUkNIQVIoMTYpIE5PVCBOVUxMLAogICAgIlBheWxvYWQiIFRFWFQgTk9UIE5VTEwsCiAgICAiU3RhdGUiIFNNQUxMSU5UIE5PVCBOVUxMIERFRkFVTFQgMCwKICAgICJBdHRlbXB0cyIgSU5UIE5PVCBOVUxMIERFRkFVTFQgMCwKICAgICJSZW1hcmsiIFRFWFQgTlVMTCwKICAgICJDcmVhdGlvblRpbWUiIEJJR0lOVCBOT1QgTlVMTCwKICAgICJSZWxheVRpbWUiIEJJR0lOVCBOVUxMLAogICAgIlRyYWNlUGFyZW50IiBWQVJDSEFSKDY0KSBOT1QgTlVMTCBERUZBVUxUICcnLAogICAgIlRyYWNlU3RhdGUiIFZBUkNIQVIoNTEyKSBOT1QgTlVMTCBERUZBVUxUICcnCiAgKTsKICBDUkVBVEUgSU5ERVggSUYgTk9UIEVYSVNUUyAiSVhfJHtJTkRFWH1fU3RhdGUiIE9OICR7VEFCTEV9ICgiU3RhdGUiLCAiQXR0ZW1wdHMiLCAiQ3JlYXRpb25UaW1lIik7Cg==")
 
-	r.Store("fetch_subscriptions_yml", "bmFtZTogRmV0Y2hTdWJzY3JpcHRpb25zDQoNCnNjcmlwdDoNCiAgU0VMRUNUDQogICAgIklEIiwgDQogICAgIk1lc3NhZ2VJRCIsIA0KICAgICJSZWNlaXZlclRhZyIsIA0KICAgICJFeGNoYW5nZSIsIA0KICAgICJSb3V0ZUtleSIsDQogICAgIlN0YXRlTmFtZSIsDQogICAgIkxhc3RNb3RpZnlUaW1lIiwNCiAgICAiTGFzdE1vdGlmeVRpbWVTdHJpbmciDQogIEZST00gDQogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyINCiAgV0hFUkUNCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIi4iTWVzc2FnZUlEIj0kMQ0KICBPUkRFUiBCWQ0KICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1YnNjcmlwdGlvbnMiLiJSZWNlaXZlclRhZyIgQVND")
+	r.Store("delete_sub_template_yml", "bmFtZTogRGVsZXRlU3ViVGVtcGxhdGUKCnNjcmlwdDoKICBERUxFVEUgRlJPTSAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJfdGVtcGxhdGVzIgogIFdIRVJFCiAgICAiSUQiID0gJDE7Cg==")
 
-	r.Store("find_failed_event_yml", "bmFtZTogRmluZEZhaWxlZEV2ZW50DQoNCg0KdmFyaWFibGVzOiANCiAgU1RBVEU6IDQNCiAgU1RBVEVOQU1FOiBGYWlsZWQNCiAgTUVTU0FHRVRZUEU6IFRyYW5zYWN0aW9uDQogIFBVQkxJU0hFRDogNg0KICBQUk9DRVNTSU5HOiAyDQogIFNVQlNVQ0NFRUQ6IFN1Y2NlZWQNCiAgTE9DS0VEOiBGT1IgVVBEQVRFIFNLSVAgTE9DS0VEIA0KDQpzY3JpcHQ6DQogIFVQREFURSAicHVibGljIi4iY2l0YWRlbC5tZXNzYWdlcyIgDQogIFNFVCAiU3RhdGUiID0gJHtTVEFURX0gQU5EICJTdGF0ZU5hbWUiPScke1NUQVRFTkFNRX0nDQogIFdIRVJFDQoJICAiSUQiID0gKA0KICAgICAgICBTRUxFQ1QgIm0iLiJJRCIgDQogICAgICAgIEZST00gIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIEFTICJtIg0KCSAgICAgIElOTkVSIEpPSU4gIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyIgQVMgc3ViIE9OICJtIi4iSUQiID0gInN1YiIuIk1lc3NhZ2VJRCIgDQogICAgICAgIFdIRVJFDQoJICAgICAgICAoIm0iLiJTdGF0ZSIgPSAke1BVQkxJU0hFRH0gT1IgIm0iLiJTdGF0ZSIgPSAke1BST0NFU1NJTkd9KSANCgkgICAgICAgIEFORCAibSIuIk1lc3NhZ2VUeXBlIiA9ICcke01FU1NBR0VUWVBFfScgDQoJICAgICAgICBBTkQgKA0KCSAgICAgICAgInN1YiIuIlN0YXRlTmFtZSIgPSAnRmFpbGVkJyANCgkgICAgICAgIE9SICgic3ViIi4iU3RhdGVOYW1lIiA8PiAnJHtTVUJTVUNDRUVEfScgQU5EICJzdWIiLiJMYXN0TW90aWZ5VGltZSIgPCAkMSApKSANCgkgICAgICAgIExJTUlUIDEgJHtMT0NLRUR9DQoJKSBSRVRVUk5JTkcgKg==")
+	r.Store("delete_sub_template_details_yml", "bmFtZTogRGVsZXRlU3ViVGVtcGxhdGVEZXRhaWxzCgpzY3JpcHQ6CiAgREVMRVRFIEZST00gIiR7U0NIRU1BfSIuImNpdGFkZWwuc3ViX3RlbXBsYXRlX2RldGFpbHMiCiAgV0hFUkUKICAgICJUZW1wbGF0ZUlEIiA9ICQxOwo=")
 
-	r.Store("find_processing_message_yml", "bmFtZTogRmluZFByb2Nlc3NpbmdNZXNzYWdlDQoNCnZhcmlhYmxlczogDQogIFNUQVRFOiAyDQogIFNUQVRFTkFNRTogUHJvY2Vzc2luZw0KDQpzY3JpcHQ6DQogICAgU0VMRUNUDQogICAgICBtc2cuIklEIg0KICAgIEZST00NCiAgICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIiBBUyAibXNnIg0KICAgIFdIRVJFDQogICAgICBtc2cuIlN0YXRlIj0ke1NUQVRFfQ0KICAgICAgQU5EIG1zZy4iU3RhdGVOYW1lIj0nJHtTVEFURU5BTUV9JyANCiAgICBPUkRFUiBCWQ0KICAgICAgbXNnLiJJRCIgQVND")
+	r.Store("fetch_flows_yml", "bmFtZTogRmV0Y2hGbG93cwoKc2NyaXB0OgogIFNFTEVDVAogICAgIklEIiwgCiAgICAiU3Vic2NyaXB0aW9uSUQiLCAKICAgICJTdGF0ZU5hbWUiLCAKICAgICJSZW1hcmsiLCAKICAgICJDcmVhdGlvblRpbWUiLCAKICAgICJDcmVhdGlvblRpbWVTdHJpbmciCiAgRlJPTQogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuZmxvd3MiCiAgV0hFUkUKICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLmZsb3dzIi4iU3Vic2NyaXB0aW9uSUQiPSQxCiAgT1JERVIgQlkKICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLmZsb3dzIi4iQ3JlYXRpb25UaW1lIiBBU0MK")
 
-	r.Store("find_subscriptions_yml", "bmFtZTogRmluZFN1YnNjcmlwdGlvbg0KDQpzY3JpcHQ6DQogIFNFTEVDVCANCiAgICAiSUQiLCANCiAgICAiTWVzc2FnZUlEIiwgDQogICAgIlJlY2VpdmVyVGFnIiwgDQogICAgIkV4Y2hhbmdlIiwgDQogICAgIlJvdXRlS2V5IiwgDQogICAgIlN0YXRlTmFtZSINCiAgRlJPTSANCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIg0KICBXSEVSRQ0KICAgICgiSUQiID0gJDEpIA0KICAgIE9SIA0KICAgICgiTWVzc2FnZUlEIiA9ICQyIEFORCAiUmVjZWl2ZXJUYWciPSQzKTs=")
+	r.Store("fetch_message_logs_yml", "bmFtZTogRmV0Y2hNZXNzYWdlTG9ncwoKc2NyaXB0OgogIFNFTEVDVCAKICAgICJJRCIsIAogICAgIk1lc3NhZ2VJRCIsIAogICAgIk9yaWduYWxTdGF0ZSIsIAogICAgIk9yaWduYWxTdGF0ZU5hbWUiLCAKICAgICJTdGF0ZSIsIAogICAgIlN0YXRlTmFtZSIsIAogICAgQ09BTEVTQ0UoIlJlbWFyayIsICcnKSBBUyAiUmVtYXJrIiwgCiAgICAiQ3JlYXRpb25UaW1lIiwgCiAgICAiQ3JlYXRpb25UaW1lU3RyaW5nIgogIEZST00KICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VfbG9ncyIKICBXSEVSRQogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZV9sb2dzIi4iTWVzc2FnZUlEIj0kMQogIE9SREVSIEJZCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlX2xvZ3MiLiJDcmVhdGlvblRpbWUiIEFTQwo=")
 
-	r.Store("find_unconfirmed_message_yml", "bmFtZTogRmluZFVuQ29uZmlybWVkTWVzc2FnZQ0KDQpzY3JpcHQ6DQogIFNFTEVDVA0KCSAgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiLiJJRCIsDQoJICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIuIlN0YXRlIiwNCgkgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIi4iU3RhdGVOYW1lIiwNCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIuIlB1Ymxpc2hlciIsDQoJICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIuIlB1Ymxpc2hUaW1lIiwNCgkgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIi4iUHVibGlzaFRpbWVTdHJpbmciIA0KICBGUk9NDQoJICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIgDQogIFdIRVJFDQoJICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIuIlN0YXRlIiA9IDYgDQoJICBBTkQgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiLiJTdGF0ZU5hbWUiID0gJ1B1Ymxpc2hlZCcgDQogICAgQU5EICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIi4iUHVibGlzaGVyIiBJUyBOT1QgTlVMTCANCiAgICBBTkQgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiLiJQdWJsaXNoZXIiIDw+ICcnDQoJICBBTkQgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiLiJQdWJsaXNoVGltZSIgPD0gJDE=")
+	r.Store("fetch_occurrence_deliveries_yml", "bmFtZTogRmV0Y2hPY2N1cnJlbmNlRGVsaXZlcmllcwoKc2NyaXB0OgogIFNFTEVDVAogICAgZmxvdy4iU3Vic2NyaXB0aW9uSUQiCiAgRlJPTQogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuZmxvd3MiIEFTIGZsb3cKICBJTk5FUiBKT0lOIAogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyIgQVMgc3ViIE9OIHN1Yi4iSUQiID0gZmxvdy4iU3Vic2NyaXB0aW9uSUQiCiAgV0hFUkUKICAgIHN1Yi4iTWVzc2FnZUlEIiA9ICQxCiAgICBBTkQgZmxvdy4iU3RhdGVOYW1lIiA9ICdQdWJsaXNoZWQnCiAgICBBTkQgZmxvdy4iUmVtYXJrIiA9ICQyCg==")
 
-	r.Store("findone_event_yml", "bmFtZTogRmluZE9uZUV2ZW50DQoNCnNjcmlwdDoNCiAgU0VMRUNUDQoJICAiSUQiLA0KCSAgIk1lc3NhZ2VJRCIsDQoJICAiRXhjaGFuZ2UiLA0KCSAgIlJvdXRlS2V5IiwNCgkgICJRdWV1ZSIgDQogIEZST00NCgkgICIke1NDSEVNQX0iLiJjaXRhZGVsLmV2ZW50cyINCiAgV0hFUkUgDQogICAgIk1lc3NhZ2VJRCI9JDE=")
+	r.Store("fetch_sub_template_details_yml", "bmFtZTogRmV0Y2hTdWJUZW1wbGF0ZURldGFpbHMKCnNjcmlwdDoKICBTRUxFQ1QKCSAgIklEIiwKCSAgIlRlbXBsYXRlSUQiLAoJICAiUmVjZWl2ZXJUYWciLAoJICAiRXhjaGFuZ2UiLAoJICAiUm91dGVLZXkiLAoJICAiQ3JlYXRpb25UaW1lIiwKCSAgIkNyZWF0aW9uVGltZVN0cmluZyIgCiAgRlJPTQoJICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJfdGVtcGxhdGVfZGV0YWlscyIgCiAgV0hFUkUKCSAgIlRlbXBsYXRlSUQiID0gJDE=")
 
-	r.Store("findone_failed_message_yml", "bmFtZTogRmluZE9uZUZhaWxlZE1lc3NhZ2UNCg0Kc2NyaXB0Og0KICAgIFNFTEVDVA0KCSAgICBtc2cuIklEIiwgDQogICAgICBtc2cuIk1lc3NhZ2VUeXBlIiwgDQogICAgICBtc2cuIkNvbnRlbnQiLCANCiAgICAgIG1zZy4iU3RhdGUiLCANCiAgICAgIG1zZy4iU3RhdGVOYW1lIiwgDQogICAgICBtc2cuIlJldHJ5IiwgDQogICAgICBtc2cuIkNyZWF0aW9uVGltZSIsIA0KICAgICAgbXNnLiJDcmVhdGlvblRpbWVTdHJpbmciLCANCiAgICAgIG1zZy4iUHVibGlzaGVyIiwgDQogICAgICBtc2cuIlB1Ymxpc2hUaW1lIiwgDQogICAgICBtc2cuIlB1Ymxpc2hUaW1lU3RyaW5nIiwgDQogICAgICBtc2cuIkVudiINCiAgICBGUk9NDQoJICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIiBBUyBtc2cNCgkgIElOTkVSIEpPSU4gKA0KICAgICAgU0VMRUNUDQoJICAgICAgaW5uZXJTdWIuIklEIiwNCgkgICAgICBpbm5lclN1Yi4iTWVzc2FnZUlEIiwNCgkgICAgICBpbm5lckZsb3cuIlN0YXRlTmFtZSIsDQoJICAgICAgaW5uZXJGbG93LiJDcmVhdGlvblRpbWUiIEFTICJMYXN0TW90aWZ5VGltZSIsDQoJICAgICAgaW5uZXJGbG93LiJDcmVhdGlvblRpbWVTdHJpbmciIEFTICJMYXN0TW90aWZ5VGltZVN0cmluZyIgDQogICAgICBGUk9NDQoJICAgICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyIgQVMgaW5uZXJTdWINCgkgICAgSU5ORVIgSk9JTiANCiAgICAgICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuZmxvd3MiIEFTIGlubmVyRmxvdyBPTiBpbm5lclN1Yi4iSUQiID0gaW5uZXJGbG93LiJTdWJzY3JpcHRpb25JRCIgDQogICAgICBXSEVSRQ0KCSAgICAgIGlubmVyRmxvdy4iQ3JlYXRpb25UaW1lIiA9ICgNCiAgICAgICAgICBTRUxFQ1QNCgkgICAgICAgICAgaW5uZXIxLiJDcmVhdGlvblRpbWUiIA0KICAgICAgICAgIEZST00gKCANCiAgICAgICAgICAgICAgU0VMRUNUIA0KICAgICAgICAgICAgICAgIHN1YklubmVyMS4iU3Vic2NyaXB0aW9uSUQiLCBNQVgoc3ViSW5uZXIxLiJDcmVhdGlvblRpbWUiKSBBUyAiQ3JlYXRpb25UaW1lIiANCiAgICAgICAgICAgICAgRlJPTSANCiAgICAgICAgICAgICAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5mbG93cyIgQVMgc3ViSW5uZXIxIA0KICAgICAgICAgICAgICBHUk9VUCBCWSBzdWJJbm5lcjEuIlN1YnNjcmlwdGlvbklEIiANCiAgICAgICAgICAgICkgQVMgaW5uZXIxIA0KICAgICAgICAgIFdIRVJFDQoJICAgICAgICAgIGlubmVyMS4iU3Vic2NyaXB0aW9uSUQiID0gaW5uZXJTdWIuIklEIiANCgkgICAgICAgICkgDQoJICAgICkgQVMgc3ViIE9OIG1zZy4iSUQiID0gc3ViLiJNZXNzYWdlSUQiIA0KICAgIFdIRVJFDQogICAgICBtc2cuIk1lc3NhZ2VUeXBlIj0gJ0V2ZW50Jw0KCSAgICBBTkQgbXNnLiJTdGF0ZSIgPSAyDQogICAgICBBTkQgbXNnLiJDcmVhdGlvblRpbWUiIDw9ICQxDQoJICAgIEFORCAoIA0KICAgICAgICBzdWIuIlN0YXRlTmFtZSIgPSAnRmFpbGVkJyANCiAgICAgICAgT1IgKCANCiAgICAgICAgICBzdWIuIlN0YXRlTmFtZSIgPD4gJ1N1Y2NlZWRlZCcgDQogICAgICAgICAgQU5EIHN1Yi4iU3RhdGVOYW1lIiA8PiAnRmFpbGVkJyANCiAgICAgICAgICBBTkQgc3ViLiJMYXN0TW90aWZ5VGltZSIgPD0gJDEgDQogICAgICAgICkgDQogICAgICAgIE9SICggDQogICAgICAgICAgU0VMRUNUIA0KICAgICAgICAgICAgQ09VTlQgKCAqICkgDQogICAgICAgICAgRlJPTSANCiAgICAgICAgICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1YnNjcmlwdGlvbnMiIEFTIHN1YjIgDQogICAgICAgICAgV0hFUkUgDQogICAgICAgICAgICBzdWIyLiJNZXNzYWdlSUQiID0gbXNnLiJJRCIgDQogICAgICAgICkgPSAwDQogICAgICApDQoJICBMSU1JVCAxDQoJICBGT1IgVVBEQVRFIFNLSVAgTE9DS0VEOw==")
+	r.Store("fetch_subscriptions_yml", "bmFtZTogRmV0Y2hTdWJzY3JpcHRpb25zCgpzY3JpcHQ6CiAgU0VMRUNUCiAgICAiSUQiLCAKICAgICJNZXNzYWdlSUQiLCAKICAgICJSZWNlaXZlclRhZyIsIAogICAgIkV4Y2hhbmdlIiwgCiAgICAiUm91dGVLZXkiLAogICAgIlN0YXRlTmFtZSIsCiAgICAiTGFzdE1vdGlmeVRpbWUiLAogICAgIkxhc3RNb3RpZnlUaW1lU3RyaW5nIgogIEZST00gCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIgogIFdIRVJFCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIi4iTWVzc2FnZUlEIj0kMQogIE9SREVSIEJZCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIi4iUmVjZWl2ZXJUYWciIEFTQwo=")
 
-	r.Store("findone_locked_message_yml", "bmFtZTogRmluZE9uZUxvY2tlZE1lc3NhZ2UNCg0Kc2NyaXB0Og0KICBTRUxFQ1QNCiAgICAiSUQiLCANCiAgICAiTWVzc2FnZVR5cGUiLCANCiAgICAiQ29udGVudCIsIA0KICAgICJTdGF0ZSIsIA0KICAgICJTdGF0ZU5hbWUiLCANCiAgICAiUmV0cnkiLCANCiAgICAiQ3JlYXRpb25UaW1lIiwgDQogICAgIkNyZWF0aW9uVGltZVN0cmluZyIsIA0KICAgICJQdWJsaXNoZXIiLCANCiAgICAiUHVibGlzaFRpbWUiLCANCiAgICAiUHVibGlzaFRpbWVTdHJpbmciLCANCiAgICAiRW52Ig0KICBGUk9NIA0KICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIg0KICBXSEVSRQ0KICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIi4iSUQiPSQxDQogIEZPUiBVUERBVEUgU0tJUCBMT0NLRUQNCiAgICA=")
+	r.Store("find_failed_event_yml", "bmFtZTogRmluZEZhaWxlZEV2ZW50CgoKdmFyaWFibGVzOiAKICBTVEFURTogNAogIFNUQVRFTkFNRTogRmFpbGVkCiAgTUVTU0FHRVRZUEU6IFRyYW5zYWN0aW9uCiAgUFVCTElTSEVEOiA2CiAgUFJPQ0VTU0lORzogMgogIFNVQlNVQ0NFRUQ6IFN1Y2NlZWQKICBMT0NLRUQ6IEZPUiBVUERBVEUgU0tJUCBMT0NLRUQgCgpzY3JpcHQ6CiAgVVBEQVRFICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIiAKICBTRVQgIlN0YXRlIiA9ICR7U1RBVEV9IEFORCAiU3RhdGVOYW1lIj0nJHtTVEFURU5BTUV9JwogIFdIRVJFCgkgICJJRCIgPSAoCiAgICAgICAgU0VMRUNUICJtIi4iSUQiIAogICAgICAgIEZST00gIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIEFTICJtIgoJICAgICAgSU5ORVIgSk9JTiAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIiBBUyBzdWIgT04gIm0iLiJJRCIgPSAic3ViIi4iTWVzc2FnZUlEIiAKICAgICAgICBXSEVSRQoJICAgICAgICAoIm0iLiJTdGF0ZSIgPSAke1BVQkxJU0hFRH0gT1IgIm0iLiJTdGF0ZSIgPSAke1BST0NFU1NJTkd9KSAKCSAgICAgICAgQU5EICJtIi4iTWVzc2FnZVR5cGUiID0gJyR7TUVTU0FHRVRZUEV9JyAKCSAgICAgICAgQU5EICgKCSAgICAgICAgInN1YiIuIlN0YXRlTmFtZSIgPSAnRmFpbGVkJyAKCSAgICAgICAgT1IgKCJzdWIiLiJTdGF0ZU5hbWUiIDw+ICcke1NVQlNVQ0NFRUR9JyBBTkQgInN1YiIuIkxhc3RNb3RpZnlUaW1lIiA8ICQxICkpIAoJICAgICAgICBMSU1JVCAxICR7TE9DS0VEfQoJKSBSRVRVUk5JTkcgKg==")
 
-	r.Store("findone_locked_subscription_yml", "bmFtZTogRmluZE9uZUxvY2tlZFN1YnNjcmlwdGlvbg0KDQpzY3JpcHQ6DQogIFNFTEVDVA0KICAgICJJRCIsIA0KICAgICJNZXNzYWdlSUQiLCANCiAgICAiUmVjZWl2ZXJUYWciLCANCiAgICAiRXhjaGFuZ2UiLCANCiAgICAiUm91dGVLZXkiLA0KICAgICJTdGF0ZU5hbWUiDQogIEZST00gDQogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyINCiAgV0hFUkUNCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIi4iSUQiPSQxIE9SICgiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIi4iTWVzc2FnZUlEIj0kMiBBTkQgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyIuIlJlY2VpdmVyVGFnIj0kMykNCiAgRk9SIFVQREFURSBOT1dBSVQ7")
+	r.Store("find_message_retry_yml", "bmFtZTogRmluZE1lc3NhZ2VSZXRyeQoKc2NyaXB0OgogIFNFTEVDVAogICAgIlJldHJ5IgogIEZST00KICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIgogIFdIRVJFCiAgICAiSUQiID0gJDE7Cg==")
 
-	r.Store("findone_messages_yml", "bmFtZTogRmluZE9uZU1lc3NhZ2UNCg0Kc2NyaXB0Og0KICBTRUxFQ1QNCiAgICAiSUQiLCANCiAgICAiTWVzc2FnZVR5cGUiLCANCiAgICAiQ29udGVudCIsIA0KICAgICJTdGF0ZSIsIA0KICAgICJTdGF0ZU5hbWUiLCANCiAgICAiUmV0cnkiLCANCiAgICAiQ3JlYXRpb25UaW1lIiwgDQogICAgIkNyZWF0aW9uVGltZVN0cmluZyIsIA0KICAgICJQdWJsaXNoZXIiLCANCiAgICAiUHVibGlzaFRpbWUiLCANCiAgICAiUHVibGlzaFRpbWVTdHJpbmciLCANCiAgICAiRW52Ig0KICBGUk9NIA0KICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIg0KICBXSEVSRQ0KICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIi4iSUQiPSQxDQogICAg")
+	r.Store("find_processing_message_yml", "bmFtZTogRmluZFByb2Nlc3NpbmdNZXNzYWdlCgp2YXJpYWJsZXM6IAogIFNUQVRFOiAyCiAgU1RBVEVOQU1FOiBQcm9jZXNzaW5nCgpzY3JpcHQ6CiAgICBTRUxFQ1QKICAgICAgbXNnLiJJRCIKICAgIEZST00KICAgICAgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIEFTICJtc2ciCiAgICBXSEVSRQogICAgICBtc2cuIlN0YXRlIj0ke1NUQVRFfQogICAgICBBTkQgbXNnLiJTdGF0ZU5hbWUiPScke1NUQVRFTkFNRX0nIAogICAgT1JERVIgQlkKICAgICAgbXNnLiJJRCIgQVND")
 
-	r.Store("findone_rollback_message_yml", "bmFtZTogRmluZE9uZVJvbGxiYWNrTWVzc2FnZQ0KDQpzY3JpcHQ6DQogIFNFTEVDVA0KCSAgbXNnLiJJRCIsDQoJICBtc2cuIk1lc3NhZ2VUeXBlIiwNCgkJbXNnLiJQdWJsaXNoZXIiLA0KCSAgbXNnLiJDb250ZW50IiwNCgkgIGV2ZS4iUm91dGVLZXkiLA0KCSAgZXZlLiJRdWV1ZSIsDQoJICBldmUuIkV4Y2hhbmdlIiANCiAgRlJPTSAoDQogICAgU0VMRUNUDQoJICAgIGlubmVyTXNnLiJJRCIsDQoJCQlpbm5lck1zZy4iTWVzc2FnZVR5cGUiLA0KCSAgICBpbm5lck1zZy4iUHVibGlzaGVyIiwNCgkgICAgaW5uZXJNc2cuIkNvbnRlbnQiIA0KICAgIEZST00NCgkgICAgInB1YmxpYyIuImNpdGFkZWwubWVzc2FnZXMiIEFTIGlubmVyTXNnIA0KICAgIFdIRVJFDQoJICAgIGlubmVyTXNnLiJNZXNzYWdlVHlwZSIgPSAnRXZlbnQnIA0KCSAgICBBTkQgaW5uZXJNc2cuIlN0YXRlIiA9IDQgDQoJICBMSU1JVCAxIEZPUiBVUERBVEUgU0tJUCBMT0NLRUQgDQoJKSBBUyBtc2cNCglJTk5FUiBKT0lOICJwdWJsaWMiLiJjaXRhZGVsLmV2ZW50cyIgQVMgZXZlIE9OIG1zZy4iSUQiID0gZXZlLiJNZXNzYWdlSUQi")
+	r.Store("find_subscriptions_yml", "bmFtZTogRmluZFN1YnNjcmlwdGlvbgoKc2NyaXB0OgogIFNFTEVDVCAKICAgICJJRCIsIAogICAgIk1lc3NhZ2VJRCIsIAogICAgIlJlY2VpdmVyVGFnIiwgCiAgICAiRXhjaGFuZ2UiLCAKICAgICJSb3V0ZUtleSIsIAogICAgIlN0YXRlTmFtZSIKICBGUk9NIAogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyIKICBXSEVSRQogICAgKCJJRCIgPSAkMSkgCiAgICBPUiAKICAgICgiTWVzc2FnZUlEIiA9ICQyIEFORCAiUmVjZWl2ZXJUYWciPSQzKTs=")
 
-	r.Store("findone_subscription_yml", "bmFtZTogRmluZE9uZVN1YnNjcmlwdGlvbg0KDQpzY3JpcHQ6DQogIFNFTEVDVA0KICAgICJJRCIsIA0KICAgICJNZXNzYWdlSUQiLCANCiAgICAiUmVjZWl2ZXJUYWciLCANCiAgICAiRXhjaGFuZ2UiLCANCiAgICAiUm91dGVLZXkiLA0KICAgICJTdGF0ZU5hbWUiDQogIEZST00gDQogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyINCiAgV0hFUkUNCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIi4iSUQiPSQxIE9SICgiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIi4iTWVzc2FnZUlEIj0kMiBBTkQgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyIuIlJlY2VpdmVyVGFnIj0kMykNCiAgOw==")
+	r.Store("find_unconfirmed_message_yml", "bmFtZTogRmluZFVuQ29uZmlybWVkTWVzc2FnZQoKc2NyaXB0OgogIFNFTEVDVAoJICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIuIklEIiwKCSAgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiLiJTdGF0ZSIsCgkgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIi4iU3RhdGVOYW1lIiwKICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIi4iUHVibGlzaGVyIiwKCSAgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiLiJQdWJsaXNoVGltZSIsCgkgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIi4iUHVibGlzaFRpbWVTdHJpbmciIAogIEZST00KCSAgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIAogIFdIRVJFCgkgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIi4iU3RhdGUiID0gNiAKCSAgQU5EICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIi4iU3RhdGVOYW1lIiA9ICdQdWJsaXNoZWQnIAogICAgQU5EICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIi4iUHVibGlzaGVyIiBJUyBOT1QgTlVMTCAKICAgIEFORCAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIuIlB1Ymxpc2hlciIgPD4gJycKCSAgQU5EICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIi4iUHVibGlzaFRpbWUiIDw9ICQxCg==")
 
-	r.Store("findone_succeed_message_yml", "bmFtZTogRmluZE9uZVN1Y2NlZWRNZXNzYWdlDQoNCnNjcmlwdDoNCiAgU0VMRUNUDQoJICAgIG1zZy4iSUQiLCANCiAgICAgIG1zZy4iTWVzc2FnZVR5cGUiLCANCiAgICAgIG1zZy4iQ29udGVudCIsIA0KICAgICAgbXNnLiJTdGF0ZSIsIA0KICAgICAgbXNnLiJTdGF0ZU5hbWUiLCANCiAgICAgIG1zZy4iUmV0cnkiLCANCiAgICAgIG1zZy4iQ3JlYXRpb25UaW1lIiwgDQogICAgICBtc2cuIkNyZWF0aW9uVGltZVN0cmluZyIsIA0KICAgICAgbXNnLiJQdWJsaXNoZXIiLCANCiAgICAgIG1zZy4iUHVibGlzaFRpbWUiLCANCiAgICAgIG1zZy4iUHVibGlzaFRpbWVTdHJpbmciLCANCiAgICAgIG1zZy4iRW52Ig0KICAgIEZST00NCgkgICAgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIEFTIG1zZyANCiAgICBXSEVSRQ0KCSAgICAoIA0KICAgICAgICBTRUxFQ1QgDQogICAgICAgICAgQ09VTlQgKCAqICkgDQogICAgICAgIEZST00gDQogICAgICAgICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyIgQVMgc3ViIA0KICAgICAgICBXSEVSRSANCiAgICAgICAgICBzdWIuIk1lc3NhZ2VJRCIgPSBtc2cuIklEIiANCiAgICAgICAgICBBTkQgc3ViLiJTdGF0ZU5hbWUiIDw+ICdTdWNjZWVkZWQnIA0KICAgICAgKSA9IDANCiAgICAgIEFORCAoIA0KICAgICAgICBTRUxFQ1QgDQogICAgICAgICAgQ09VTlQgKCAqICkgDQogICAgICAgIEZST00gDQogICAgICAgICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyIgQVMgc3ViMiANCiAgICAgICAgV0hFUkUgDQogICAgICAgICAgc3ViMi4iTWVzc2FnZUlEIiA9IG1zZy4iSUQiIA0KICAgICAgKSA+IDANCiAgICAgIEFORCAiQ3JlYXRpb25UaW1lIiA8PSAkMQ0KICAgICAgQU5EICJTdGF0ZSI9Mg0KICAgICAgQU5EICJNZXNzYWdlVHlwZSIgPSAnRXZlbnQnDQogICAgTElNSVQgMQ0KICAgIEZPUiBVUERBVEUgU0tJUCBMT0NLRUQ7")
+	r.Store("find_unscheduled_jobs_yml", "bmFtZTogRmluZFVuc2NoZWR1bGVkSm9icwoKdmFyaWFibGVzOiAKICBTVEFURTogMgogIENST05KT0I6IDMKCnNjcmlwdDoKICBTRUxFQ1QKICAgIGpvYi4iSUQiLAogICAgam9iLiJNZXNzYWdlSUQiLAogICAgam9iLiJFeHByZXNzaW9uIiwKICAgIGpvYi4iS2luZCIsCiAgICBqb2IuIktpbmROYW1lIiwKICAgIGpvYi4iRGVsYXlTZWNvbmRzIiwKICAgIG1zZy4iQ3JlYXRpb25UaW1lIiArIGpvYi4iRGVsYXlTZWNvbmRzIiBBUyAiTmV4dEZpcmVUaW1lIiwKICAgIGpvYi4iUmV0cnlQb2xpY3kiLAogICAgam9iLiJPY2N1cnJlbmNlVGltZSIKICBGUk9NCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5qb2JzIiBBUyBqb2IKICBJTk5FUiBKT0lOIAogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIEFTIG1zZyBPTiBtc2cuIklEIiA9IGpvYi4iTWVzc2FnZUlEIgogIFdIRVJFCiAgICBqb2IuIk5leHRGaXJlVGltZSIgSVMgTlVMTAogICAgQU5EIG1zZy4iU3RhdGUiID0gJHtTVEFURX0KICAgIEFORCAobXNnLiJFbnYiID0gJDEgT1IgbXNnLiJFbnYiID0gJycgT1IgJDEgPSAnJykKICAgIEFORCAoCiAgICAgIGpvYi4iS2luZCIgPSAke0NST05KT0J9CiAgICAgIE9SICgKICAgICAgICBTRUxFQ1QgCiAgICAgICAgICBDT1VOVCAoICogKSAKICAgICAgICBGUk9NIAogICAgICAgICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyIgQVMgc3ViIAogICAgICAgIFdIRVJFIAogICAgICAgICAgc3ViLiJNZXNzYWdlSUQiID0gbXNnLiJJRCIgCiAgICAgICAgICBBTkQgc3ViLiJTdGF0ZU5hbWUiID0gJ1NjaGVkdWxlZCcgCiAgICAgICkgPiAwCiAgICApCg==")
 
-	r.Store("findone_template_yml", "bmFtZTogRmluZE9uZVRlbXBsYXRlDQoNCnNjcmlwdDoNCiAgU0VMRUNUDQoJICAiSUQiLA0KCSAgIk5hbWUiLA0KCSAgIkRlc2NyaXB0aW9uIiwNCgkgICJDcmVhdGlvblRpbWUiLA0KCSAgIkNyZWF0aW9uVGltZVN0cmluZyIgDQogIEZST00NCgkgICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1Yl90ZW1wbGF0ZXMiDQogIFdIRVJFDQoJICAiSUQiPSQxIE9SICJOYW1lIj0kMg==")
+	r.Store("findone_due_job_yml", "bmFtZTogRmluZE9uZUR1ZUpvYgoKdmFyaWFibGVzOiAKICBTVEFURTogMgoKc2NyaXB0OgogIFNFTEVDVAogICAgam9iLiJJRCIsCiAgICBqb2IuIk1lc3NhZ2VJRCIsCiAgICBqb2IuIkV4cHJlc3Npb24iLAogICAgam9iLiJLaW5kIiwKICAgIGpvYi4iS2luZE5hbWUiLAogICAgam9iLiJEZWxheVNlY29uZHMiLAogICAgam9iLiJOZXh0RmlyZVRpbWUiLAogICAgam9iLiJSZXRyeVBvbGljeSIsCiAgICBqb2IuIk9jY3VycmVuY2VUaW1lIgogIEZST00KICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLmpvYnMiIEFTIGpvYgogIElOTkVSIEpPSU4gCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIgQVMgbXNnIE9OIG1zZy4iSUQiID0gam9iLiJNZXNzYWdlSUQiCiAgV0hFUkUKICAgIGpvYi4iTmV4dEZpcmVUaW1lIiA8PSAkMQogICAgQU5EIG1zZy4iU3RhdGUiID0gJHtTVEFURX0KICAgIEFORCAobXNnLiJFbnYiID0gJDIgT1IgbXNnLiJFbnYiID0gJycgT1IgJDIgPSAnJykKICBPUkRFUiBCWQogICAgam9iLiJOZXh0RmlyZVRpbWUiIEFTQwogIExJTUlUIDEKICBGT1IgVVBEQVRFIFNLSVAgTE9DS0VEOwo=")
 
-	r.Store("increase_message_retry_yml", "bmFtZTogSW5jcmVhc2VNZXNzYWdlUmV0cnkNCg0Kc2NyaXB0Og0KICBVUERBVEUgDQogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIA0KICBTRVQgDQogICAgIlJldHJ5IiA9ICJSZXRyeSIgKyAxDQogIFdIRVJFIA0KICAgICJJRCIgPSAkMQ0KICBSRVRVUk5JTkcgDQogICAgIlJldHJ5IjsNCg==")
+	r.Store("findone_event_yml", "bmFtZTogRmluZE9uZUV2ZW50CgpzY3JpcHQ6CiAgU0VMRUNUCgkgICJJRCIsCgkgICJNZXNzYWdlSUQiLAoJICAiRXhjaGFuZ2UiLAoJICAiUm91dGVLZXkiLAoJICAiUXVldWUiIAogIEZST00KCSAgIiR7U0NIRU1BfSIuImNpdGFkZWwuZXZlbnRzIgogIFdIRVJFIAogICAgIk1lc3NhZ2VJRCI9JDE=")
 
-	r.Store("insert_backgroudjob_yml", "bmFtZTogSW5zZXJ0QmFja2dyb3VuZEpvYg0KDQpzY3JpcHQ6DQogIElOU0VSVCBJTlRPICIke1NDSEVNQX0iLiJjaXRhZGVsLmpvYnMiKA0KICAgICJJRCIsIA0KICAgICJNZXNzYWdlSUQiLCANCiAgICAiRXhwcmVzc2lvbiIsIA0KICAgICJLaW5kIiwgDQogICAgIktpbmROYW1lIiwgDQogICAgIkRlbGF5U2Vjb25kcyINCiAgKSBWQUxVRVMgKA0KICAgICQxLA0KICAgICQyLA0KICAgICQzLA0KICAgICQ0LA0KICAgICQ1LA0KICAgICQ2DQogICk7DQo=")
+	r.Store("findone_failed_message_yml", "bmFtZTogRmluZE9uZUZhaWxlZE1lc3NhZ2UKCnNjcmlwdDoKICAgIFNFTEVDVAoJICAgIG1zZy4iSUQiLCAKICAgICAgbXNnLiJNZXNzYWdlVHlwZSIsIAogICAgICBtc2cuIkNvbnRlbnQiLCAKICAgICAgbXNnLiJTdGF0ZSIsIAogICAgICBtc2cuIlN0YXRlTmFtZSIsIAogICAgICBtc2cuIlJldHJ5IiwgCiAgICAgIG1zZy4iQ3JlYXRpb25UaW1lIiwgCiAgICAgIG1zZy4iQ3JlYXRpb25UaW1lU3RyaW5nIiwgCiAgICAgIG1zZy4iUHVibGlzaGVyIiwgCiAgICAgIG1zZy4iUHVibGlzaFRpbWUiLCAKICAgICAgbXNnLiJQdWJsaXNoVGltZVN0cmluZyIsIAogICAgICBtc2cuIkVudiIKICAgIEZST00KCSAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIgQVMgbXNnCgkgIElOTkVSIEpPSU4gKAogICAgICBTRUxFQ1QKCSAgICAgIGlubmVyU3ViLiJJRCIsCgkgICAgICBpbm5lclN1Yi4iTWVzc2FnZUlEIiwKCSAgICAgIGlubmVyRmxvdy4iU3RhdGVOYW1lIiwKCSAgICAgIGlubmVyRmxvdy4iQ3JlYXRpb25UaW1lIiBBUyAiTGFzdE1vdGlmeVRpbWUiLAoJICAgICAgaW5uZXJGbG93LiJDcmVhdGlvblRpbWVTdHJpbmciIEFTICJMYXN0TW90aWZ5VGltZVN0cmluZyIgCiAgICAgIEZST00KCSAgICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1YnNjcmlwdGlvbnMiIEFTIGlubmVyU3ViCgkgICAgSU5ORVIgSk9JTiAKICAgICAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5mbG93cyIgQVMgaW5uZXJGbG93IE9OIGlubmVyU3ViLiJJRCIgPSBpbm5lckZsb3cuIlN1YnNjcmlwdGlvbklEIiAKICAgICAgV0hFUkUKCSAgICAgIGlubmVyRmxvdy4iQ3JlYXRpb25UaW1lIiA9ICgKICAgICAgICAgIFNFTEVDVAoJICAgICAgICAgIGlubmVyMS4iQ3JlYXRpb25UaW1lIiAKICAgICAgICAgIEZST00gKCAKICAgICAgICAgICAgICBTRUxFQ1QgCiAgICAgICAgICAgICAgICBzdWJJbm5lcjEuIlN1YnNjcmlwdGlvbklEIiwgTUFYKHN1YklubmVyMS4iQ3JlYXRpb25UaW1lIikgQVMgIkNyZWF0aW9uVGltZSIgCiAgICAgICAgICAgICAgRlJPTSAKICAgICAgICAgICAgICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLmZsb3dzIiBBUyBzdWJJbm5lcjEgCiAgICAgICAgICAgICAgR1JPVVAgQlkgc3ViSW5uZXIxLiJTdWJzY3JpcHRpb25JRCIgCiAgICAgICAgICAgICkgQVMgaW5uZXIxIAogICAgICAgICAgV0hFUkUKCSAgICAgICAgICBpbm5lcjEuIlN1YnNjcmlwdGlvbklEIiA9IGlubmVyU3ViLiJJRCIgCgkgICAgICAgICkgCgkgICAgKSBBUyBzdWIgT04gbXNnLiJJRCIgPSBzdWIuIk1lc3NhZ2VJRCIgCiAgICBXSEVSRQogICAgICBtc2cuIk1lc3NhZ2VUeXBlIj0gJ0V2ZW50JwoJICAgIEFORCBtc2cuIlN0YXRlIiA9IDIKICAgICAgQU5EIG1zZy4iQ3JlYXRpb25UaW1lIiA8PSAkMQogICAgICBBTkQgKG1zZy4iRW52IiA9ICQyIE9SIG1zZy4iRW52IiA9ICcnIE9SICQyID0gJycpCgkgICAgQU5EICggCiAgICAgICAgc3ViLiJTdGF0ZU5hbWUiID0gJ0ZhaWxlZCcgCiAgICAgICAgT1IgKCAKICAgICAgICAgIHN1Yi4iU3RhdGVOYW1lIiA8PiAnU3VjY2VlZGVkJyAKICAgICAgICAgIEFORCBzdWIuIlN0YXRlTmFtZSIgPD4gJ0ZhaWxlZCcgCiAgICAgICAgICBBTkQgc3ViLiJMYXN0TW90aWZ5VGltZSIgPD0gJDEgCiAgICAgICAgKSAKICAgICAgICBPUiAoIAogICAgICAgICAgU0VMRUNUIAogICAgICAgICAgICBDT1VOVCAoICogKSAKICAgICAgICAgIEZST00gCiAgICAgICAgICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1YnNjcmlwdGlvbnMiIEFTIHN1YjIgCiAgICAgICAgICBXSEVSRSAKICAgICAgICAgICAgc3ViMi4iTWVzc2FnZUlEIiA9IG1zZy4iSUQiIAogICAgICAgICkgPSAwCiAgICAgICkKCSAgTElNSVQgMQoJICBGT1IgVVBEQVRFIFNLSVAgTE9DS0VEOw==")
 
-	r.Store("insert_event_yml", "bmFtZTogSW5zZXJ0RXZlbnQNCg0Kc2NyaXB0Og0KICBJTlNFUlQgSU5UTyAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5ldmVudHMiKA0KICAgICJJRCIsIA0KICAgICJNZXNzYWdlSUQiLCANCiAgICAiRXhjaGFuZ2UiLCANCiAgICAiUm91dGVLZXkiLA0KICAgICJRdWV1ZSINCiAgKSBWQUxVRVMgKA0KICAgICQxLA0KICAgICQyLA0KICAgICQzLA0KICAgICQ0LA0KICAgICQ1DQogICk7DQo=")
+	r.Store("findone_locked_job_yml", "bmFtZTogRmluZE9uZUxvY2tlZEpvYgoKc2NyaXB0OgogIFNFTEVDVAogICAgIklEIiwKICAgICJNZXNzYWdlSUQiLAogICAgIkV4cHJlc3Npb24iLAogICAgIktpbmQiLAogICAgIktpbmROYW1lIiwKICAgICJEZWxheVNlY29uZHMiLAogICAgIk5leHRGaXJlVGltZSIsCiAgICAiUmV0cnlQb2xpY3kiLAogICAgIk9jY3VycmVuY2VUaW1lIgogIEZST00KICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLmpvYnMiCiAgV0hFUkUKICAgICJNZXNzYWdlSUQiID0gJDEKICBGT1IgVVBEQVRFOwo=")
 
-	r.Store("insert_flow_yml", "bmFtZTogSW5zZXJ0Rmxvdw0KDQpzY3JpcHQ6DQogIElOU0VSVCBJTlRPICIke1NDSEVNQX0iLiJjaXRhZGVsLmZsb3dzIigNCiAgICAiSUQiLCANCiAgICAiU3Vic2NyaXB0aW9uSUQiLCANCiAgICAiU3RhdGVOYW1lIiwgDQogICAgIlJlbWFyayIsIA0KICAgICJDcmVhdGlvblRpbWUiLCANCiAgICAiQ3JlYXRpb25UaW1lU3RyaW5nIg0KICApIFZBTFVFUyAoDQogICAgJDEsDQogICAgJDIsDQogICAgJDMsDQogICAgJDQsDQogICAgJDUsDQogICAgJDYNCiAgKTsNCg==")
+	r.Store("findone_locked_message_yml", "bmFtZTogRmluZE9uZUxvY2tlZE1lc3NhZ2UKCnNjcmlwdDoKICBTRUxFQ1QKICAgICJJRCIsIAogICAgIk1lc3NhZ2VUeXBlIiwgCiAgICAiQ29udGVudCIsIAogICAgIlN0YXRlIiwgCiAgICAiU3RhdGVOYW1lIiwgCiAgICAiUmV0cnkiLCAKICAgICJDcmVhdGlvblRpbWUiLCAKICAgICJDcmVhdGlvblRpbWVTdHJpbmciLCAKICAgICJQdWJsaXNoZXIiLCAKICAgICJQdWJsaXNoVGltZSIsIAogICAgIlB1Ymxpc2hUaW1lU3RyaW5nIiwgCiAgICAiRW52IiwKICAgICJUcmFjZVBhcmVudCIsCiAgICAiVHJhY2VTdGF0ZSIKICBGUk9NIAogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiCiAgV0hFUkUKICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIi4iSUQiPSQxCiAgRk9SIFVQREFURSBTS0lQIExPQ0tFRAogICAg")
 
-	r.Store("insert_message_yml", "bmFtZTogSW5zZXJ0TWVzc2FnZQ0KDQpzY3JpcHQ6DQogIElOU0VSVCBJTlRPICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIigNCiAgICAiSUQiLCANCiAgICAiTWVzc2FnZVR5cGUiLCANCiAgICAiQ29udGVudCIsIA0KICAgICJTdGF0ZSIsIA0KICAgICJTdGF0ZU5hbWUiLCANCiAgICAiUmV0cnkiLCANCiAgICAiQ3JlYXRpb25UaW1lIiwgDQogICAgIkNyZWF0aW9uVGltZVN0cmluZyIsIA0KICAgICJQdWJsaXNoZXIiLCANCiAgICAiUHVibGlzaFRpbWUiLCANCiAgICAiUHVibGlzaFRpbWVTdHJpbmciLCANCiAgICAiRW52Ig0KICApIFZBTFVFUyAoDQogICAgJDEsIA0KICAgICQyLCANCiAgICAkMywgDQogICAgJDQsIA0KICAgICQ1LCANCiAgICAkNiwgDQogICAgJDcsIA0KICAgICQ4LA0KICAgICQ5LA0KICAgICQxMCwNCiAgICAkMTEsDQogICAgJDEyDQogICk7DQo=")
+	r.Store("findone_locked_subscription_yml", "bmFtZTogRmluZE9uZUxvY2tlZFN1YnNjcmlwdGlvbgoKc2NyaXB0OgogIFNFTEVDVAogICAgIklEIiwgCiAgICAiTWVzc2FnZUlEIiwgCiAgICAiUmVjZWl2ZXJUYWciLCAKICAgICJFeGNoYW5nZSIsIAogICAgIlJvdXRlS2V5IiwKICAgICJTdGF0ZU5hbWUiCiAgRlJPTSAKICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1YnNjcmlwdGlvbnMiCiAgV0hFUkUKICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1YnNjcmlwdGlvbnMiLiJJRCI9JDEgT1IgKCIke1NDSEVNQX0iLiJjaXRhZGVsLnN1YnNjcmlwdGlvbnMiLiJNZXNzYWdlSUQiPSQyIEFORCAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIi4iUmVjZWl2ZXJUYWciPSQzKQogIEZPUiBVUERBVEUgTk9XQUlUOw==")
 
-	r.Store("insert_message_log_yml", "bmFtZTogSW5zZXJ0TWVzc2FnZUxvZw0KDQpzY3JpcHQ6IA0KICBJTlNFUlQgSU5UTyAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlX2xvZ3MiKA0KICAgICJJRCIsIA0KICAgICJNZXNzYWdlSUQiLCANCiAgICAiT3JpZ25hbFN0YXRlIiwgDQogICAgIk9yaWduYWxTdGF0ZU5hbWUiLCANCiAgICAiU3RhdGUiLCANCiAgICAiU3RhdGVOYW1lIiwgDQogICAgIkNyZWF0aW9uVGltZSIsIA0KICAgICJDcmVhdGlvblRpbWVTdHJpbmciDQogICkgVkFMVUVTICgNCiAgICAkMSwNCiAgICAkMiwNCiAgICAkMywNCiAgICAkNCwNCiAgICAkNSwNCiAgICAkNiwNCiAgICAkNywNCiAgICAkOCAgDQogICk7DQo=")
+	r.Store("findone_messages_yml", "bmFtZTogRmluZE9uZU1lc3NhZ2UKCnNjcmlwdDoKICBTRUxFQ1QKICAgICJJRCIsIAogICAgIk1lc3NhZ2VUeXBlIiwgCiAgICAiQ29udGVudCIsIAogICAgIlN0YXRlIiwgCiAgICAiU3RhdGVOYW1lIiwgCiAgICAiUmV0cnkiLCAKICAgICJDcmVhdGlvblRpbWUiLCAKICAgICJDcmVhdGlvblRpbWVTdHJpbmciLCAKICAgICJQdWJsaXNoZXIiLCAKICAgICJQdWJsaXNoVGltZSIsIAogICAgIlB1Ymxpc2hUaW1lU3RyaW5nIiwgCiAgICAiRW52IiwKICAgICJUcmFjZVBhcmVudCIsCiAgICAiVHJhY2VTdGF0ZSIKICBGUk9NIAogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiCiAgV0hFUkUKICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIi4iSUQiPSQxCiAgICA=")
 
-	r.Store("insert_subscription_yml", "bmFtZTogSW5zZXJ0U3Vic2NyaXB0aW9uDQoNCnNjcmlwdDoNCiAgSU5TRVJUIElOVE8gIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyIoDQogICAgIklEIiwgDQogICAgIk1lc3NhZ2VJRCIsIA0KICAgICJSZWNlaXZlclRhZyIsIA0KICAgICJFeGNoYW5nZSIsIA0KICAgICJSb3V0ZUtleSIsDQogICAgIlN0YXRlTmFtZSINCiAgKSBWQUxVRVMgKA0KICAgICQxLA0KICAgICQyLA0KICAgICQzLA0KICAgICQ0LA0KICAgICQ1LA0KICAgICQ2DQogICk7DQo=")
+	r.Store("findone_pending_outbox_row_yml", "bmFtZTogRmluZE9uZVBlbmRpbmdPdXRib3hSb3cKCnZhcmlhYmxlczogCiAgVEFCTEU6ICcibWF0Y2hhX291dGJveCInCgpzY3JpcHQ6CiAgU0VMRUNUCiAgICAiSUQiLAogICAgIktpbmQiLAogICAgIlBheWxvYWQiLAogICAgIkF0dGVtcHRzIiwKICAgICJUcmFjZVBhcmVudCIsCiAgICAiVHJhY2VTdGF0ZSIKICBGUk9NCiAgICAke1RBQkxFfQogIFdIRVJFCiAgICAiU3RhdGUiID0gJDEKICBPUkRFUiBCWQogICAgIkF0dGVtcHRzIiBBU0MsCiAgICAiQ3JlYXRpb25UaW1lIiBBU0MKICBMSU1JVCAxCiAgRk9SIFVQREFURSBTS0lQIExPQ0tFRDsK")
 
-	r.Store("list_events_yml", "bmFtZTogTGlzdEV2ZW50cw0KDQpzY3JpcHQ6DQogIFNFTEVDVA0KICAgIG1zZy4iSUQiIEFTICJNZXNzYWdlSUQiLA0KICAgIG1zZy4iU3RhdGVOYW1lIiBBUyAiTWVzc2FnZVN0YXRlIiwNCiAgICBtc2cuIlB1Ymxpc2hlciIsDQogICAgbXNnLiJQdWJsaXNoVGltZVN0cmluZyIsDQogICAgZXZlLiJSb3V0ZUtleSIsDQogICAgZXZlLiJRdWV1ZSIsDQogICAgZXZlLiJFeGNoYW5nZSIsDQogICAgbG9nLiJJRCIgQVMgIkxvZ0lEIiwNCiAgICBsb2cuIk9yaWduYWxTdGF0ZU5hbWUiIEFTICJMb2dPcmlnbmFsIiwNCiAgICBsb2cuIlN0YXRlTmFtZSIgQVMgIkxvZ0N1cnJlbnQiLA0KICAgIGxvZy4iQ3JlYXRpb25UaW1lU3RyaW5nIiBBUyAiTG9nVGltZSIsDQogICAgc3ViLiJJRCIgQVMgIlN1YklEIiwNCiAgICBzdWIuIlJlY2VpdmVyVGFnIiwNCiAgICBzdWIuIlN0YXRlTmFtZSIgQVMgIlN1YlN0YXRlIiwNCiAgICBzdWIuIkxhc3RNb3RpZnlUaW1lU3RyaW5nIiBBUyAiU3ViVGltZSIsDQogICAgZmxvdy4iSUQiIEFTICJGbG93SUQiLA0KICAgIGZsb3cuIlN0YXRlTmFtZSIgQVMgIkZsb3dTdGF0ZSIsDQogICAgZmxvdy4iUmVtYXJrIiwNCiAgICBmbG93LiJDcmVhdGlvblRpbWVTdHJpbmciIEFTICJGbG93VGltZSINCiAgRlJPTSANCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIgQVMgbXNnDQogIElOTkVSIEpPSU4gDQogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZV9sb2dzIiBBUyBsb2cgT04gbXNnLiJJRCIgPSBsb2cuIk1lc3NhZ2VJRCINCiAgSU5ORVIgSk9JTiANCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5ldmVudHMiIEFTIGV2ZSBPTiBtc2cuIklEIiA9IGV2ZS4iTWVzc2FnZUlEIg0KICBMRUZUIEpPSU4gDQogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyIgQVMgc3ViIE9OIG1zZy4iSUQiID0gc3ViLiJNZXNzYWdlSUQiDQogIExFRlQgSk9JTiANCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5mbG93cyIgQVMgZmxvdyBPTiBzdWIuIklEIiA9IGZsb3cuIlN1YnNjcmlwdGlvbklEIg0KICBXSEVSRQ0KICAgIG1zZy4iTWVzc2FnZVR5cGUiPSdFdmVudCc=")
+	r.Store("findone_rollback_message_yml", "bmFtZTogRmluZE9uZVJvbGxiYWNrTWVzc2FnZQoKc2NyaXB0OgogIFNFTEVDVAoJICBtc2cuIklEIiwKCSAgbXNnLiJNZXNzYWdlVHlwZSIsCgkJbXNnLiJQdWJsaXNoZXIiLAoJICBtc2cuIkNvbnRlbnQiLAoJICBldmUuIlJvdXRlS2V5IiwKCSAgZXZlLiJRdWV1ZSIsCgkgIGV2ZS4iRXhjaGFuZ2UiLAoJICBtc2cuIkVudiIsCgkgIG1zZy4iVHJhY2VQYXJlbnQiLAoJICBtc2cuIlRyYWNlU3RhdGUiCiAgRlJPTSAoCiAgICBTRUxFQ1QKCSAgICBpbm5lck1zZy4iSUQiLAoJCQlpbm5lck1zZy4iTWVzc2FnZVR5cGUiLAoJICAgIGlubmVyTXNnLiJQdWJsaXNoZXIiLAoJICAgIGlubmVyTXNnLiJDb250ZW50IiwKCSAgICBpbm5lck1zZy4iRW52IiwKCSAgICBpbm5lck1zZy4iVHJhY2VQYXJlbnQiLAoJICAgIGlubmVyTXNnLiJUcmFjZVN0YXRlIgogICAgRlJPTQoJICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIiBBUyBpbm5lck1zZyAKICAgIFdIRVJFCgkgICAgaW5uZXJNc2cuIk1lc3NhZ2VUeXBlIiA9ICdFdmVudCcgCgkgICAgQU5EIGlubmVyTXNnLiJTdGF0ZSIgPSA0IAoJICAgIEFORCAoaW5uZXJNc2cuIkVudiIgPSAkMSBPUiBpbm5lck1zZy4iRW52IiA9ICcnIE9SICQxID0gJycpCgkgIExJTUlUIDEgRk9SIFVQREFURSBTS0lQIExPQ0tFRCAKCSkgQVMgbXNnCglJTk5FUiBKT0lOICIke1NDSEVNQX0iLiJjaXRhZGVsLmV2ZW50cyIgQVMgZXZlIE9OIG1zZy4iSUQiID0gZXZlLiJNZXNzYWdlSUQiCg==")
 
-	r.Store("list_jobs_yml", "bmFtZTogTGlzdEpvYnMNCg0Kc2NyaXB0Og0KICBTRUxFQ1QNCgkgIG1zZy4iSUQiLA0KCSAgbXNnLiJTdGF0ZU5hbWUiLA0KCSAgbXNnLiJDcmVhdGlvblRpbWVTdHJpbmciLA0KCSAgbXNnLiJQdWJsaXNoZXIiLA0KCSAgbXNnLiJQdWJsaXNoVGltZSIsDQoJICBqb2IuIkV4cHJlc3Npb24iLA0KCSAgam9iLiJLaW5kTmFtZSIsDQoJICBqb2IuIkRlbGF5U2Vjb25kcyIsDQoJICBzdWIuIklEIiBBUyAiU3ViSUQiLA0KCSAgc3ViLiJFeGNoYW5nZSIsDQoJICBzdWIuIlJvdXRlS2V5IiwNCgkgIHN1Yi4iU3RhdGVOYW1lIiBBUyAiU3RhZ2UiLA0KCSAgc3ViLiJMYXN0TW90aWZ5VGltZVN0cmluZyIgQVMgIlN0YWdlVGltZSIsDQoJICBmbG93LiJJRCIgQVMgIkZsb3dJRCIsDQoJICBmbG93LiJTdGF0ZU5hbWUiIEFTICJGbG93U3RhdGUiLA0KCSAgZmxvdy4iUmVtYXJrIiwNCgkgIGZsb3cuIkNyZWF0aW9uVGltZVN0cmluZyIgQVMgIkZsb3dUaW1lIiANCiAgRlJPTQ0KCSAgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIEFTIG1zZw0KCSAgSU5ORVIgSk9JTiANCiAgICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLmpvYnMiIEFTIGpvYiBPTiBtc2cuIklEIiA9IGpvYi4iTWVzc2FnZUlEIg0KCSAgSU5ORVIgSk9JTiANCiAgICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1YnNjcmlwdGlvbnMiIEFTIHN1YiBPTiBtc2cuIklEIiA9IHN1Yi4iTWVzc2FnZUlEIg0KCSAgSU5ORVIgSk9JTiANCiAgICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLmZsb3dzIiBBUyBmbG93IE9OIHN1Yi4iSUQiID0gZmxvdy4iU3Vic2NyaXB0aW9uSUQiIA0KICBXSEVSRQ0KCSAgbXNnLiJNZXNzYWdlVHlwZSIgPSAnQmFja2dyb3VkSm9iJw==")
+	r.Store("findone_subscription_yml", "bmFtZTogRmluZE9uZVN1YnNjcmlwdGlvbgoKc2NyaXB0OgogIFNFTEVDVAogICAgIklEIiwgCiAgICAiTWVzc2FnZUlEIiwgCiAgICAiUmVjZWl2ZXJUYWciLCAKICAgICJFeGNoYW5nZSIsIAogICAgIlJvdXRlS2V5IiwKICAgICJTdGF0ZU5hbWUiCiAgRlJPTSAKICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1YnNjcmlwdGlvbnMiCiAgV0hFUkUKICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1YnNjcmlwdGlvbnMiLiJJRCI9JDEgT1IgKCIke1NDSEVNQX0iLiJjaXRhZGVsLnN1YnNjcmlwdGlvbnMiLiJNZXNzYWdlSUQiPSQyIEFORCAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIi4iUmVjZWl2ZXJUYWciPSQzKQogIDs=")
 
-	r.Store("published_message_yml", "bmFtZTogUHVibGlzaGVkTWVzc2FnZQ0KDQpzY3JpcHQ6IA0KICBVUERBVEUgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIA0KICBTRVQgIlN0YXRlIiA9ICQxLA0KICAgICJTdGF0ZU5hbWUiID0gJDIsDQogICAgIlB1Ymxpc2hUaW1lIiA9ICQzLA0KICAgICJQdWJsaXNoVGltZVN0cmluZyIgPSAkNCANCiAgV0hFUkUNCgkgICJJRCIgPSAkNTs=")
+	r.Store("findone_succeed_message_yml", "bmFtZTogRmluZE9uZVN1Y2NlZWRNZXNzYWdlCgpzY3JpcHQ6CiAgU0VMRUNUCgkgICAgbXNnLiJJRCIsIAogICAgICBtc2cuIk1lc3NhZ2VUeXBlIiwgCiAgICAgIG1zZy4iQ29udGVudCIsIAogICAgICBtc2cuIlN0YXRlIiwgCiAgICAgIG1zZy4iU3RhdGVOYW1lIiwgCiAgICAgIG1zZy4iUmV0cnkiLCAKICAgICAgbXNnLiJDcmVhdGlvblRpbWUiLCAKICAgICAgbXNnLiJDcmVhdGlvblRpbWVTdHJpbmciLCAKICAgICAgbXNnLiJQdWJsaXNoZXIiLCAKICAgICAgbXNnLiJQdWJsaXNoVGltZSIsIAogICAgICBtc2cuIlB1Ymxpc2hUaW1lU3RyaW5nIiwgCiAgICAgIG1zZy4iRW52IgogICAgRlJPTQoJICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIiBBUyBtc2cgCiAgICBXSEVSRQoJICAgICggCiAgICAgICAgU0VMRUNUIAogICAgICAgICAgQ09VTlQgKCAqICkgCiAgICAgICAgRlJPTSAKICAgICAgICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1YnNjcmlwdGlvbnMiIEFTIHN1YiAKICAgICAgICBXSEVSRSAKICAgICAgICAgIHN1Yi4iTWVzc2FnZUlEIiA9IG1zZy4iSUQiIAogICAgICAgICAgQU5EIHN1Yi4iU3RhdGVOYW1lIiA8PiAnU3VjY2VlZGVkJyAKICAgICAgKSA9IDAKICAgICAgQU5EICggCiAgICAgICAgU0VMRUNUIAogICAgICAgICAgQ09VTlQgKCAqICkgCiAgICAgICAgRlJPTSAKICAgICAgICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1YnNjcmlwdGlvbnMiIEFTIHN1YjIgCiAgICAgICAgV0hFUkUgCiAgICAgICAgICBzdWIyLiJNZXNzYWdlSUQiID0gbXNnLiJJRCIgCiAgICAgICkgPiAwCiAgICAgIEFORCAiQ3JlYXRpb25UaW1lIiA8PSAkMQogICAgICBBTkQgKG1zZy4iRW52IiA9ICQyIE9SIG1zZy4iRW52IiA9ICcnIE9SICQyID0gJycpCiAgICAgIEFORCAiU3RhdGUiPTIKICAgICAgQU5EICJNZXNzYWdlVHlwZSIgPSAnRXZlbnQnCiAgICBMSU1JVCAxCiAgICBGT1IgVVBEQVRFIFNLSVAgTE9DS0VEOw==")
+
+	r.Store("findone_template_yml", "bmFtZTogRmluZE9uZVRlbXBsYXRlCgpzY3JpcHQ6CiAgU0VMRUNUCgkgICJJRCIsCgkgICJOYW1lIiwKCSAgIkRlc2NyaXB0aW9uIiwKCSAgIkNyZWF0aW9uVGltZSIsCgkgICJDcmVhdGlvblRpbWVTdHJpbmciIAogIEZST00KCSAgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3ViX3RlbXBsYXRlcyIKICBXSEVSRQoJICAiSUQiPSQxIE9SICJOYW1lIj0kMg==")
+
+	r.Store("increase_message_retry_yml", "bmFtZTogSW5jcmVhc2VNZXNzYWdlUmV0cnkKCnNjcmlwdDoKICBVUERBVEUgCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIgCiAgU0VUIAogICAgIlJldHJ5IiA9ICJSZXRyeSIgKyAxCiAgV0hFUkUgCiAgICAiSUQiID0gJDE7Cg==")
+
+	r.Store("insert_backgroudjob_yml", "bmFtZTogSW5zZXJ0QmFja2dyb3VuZEpvYgoKc2NyaXB0OgogIElOU0VSVCBJTlRPICIke1NDSEVNQX0iLiJjaXRhZGVsLmpvYnMiKAogICAgIklEIiwgCiAgICAiTWVzc2FnZUlEIiwgCiAgICAiRXhwcmVzc2lvbiIsIAogICAgIktpbmQiLCAKICAgICJLaW5kTmFtZSIsIAogICAgIkRlbGF5U2Vjb25kcyIsCiAgICAiTmV4dEZpcmVUaW1lIiwKICAgICJSZXRyeVBvbGljeSIKICApIFZBTFVFUyAoCiAgICAkMSwKICAgICQyLAogICAgJDMsCiAgICAkNCwKICAgICQ1LAogICAgJDYsCiAgICAkNywKICAgICQ4CiAgKTsK")
+
+	r.Store("insert_event_yml", "bmFtZTogSW5zZXJ0RXZlbnQKCnNjcmlwdDoKICBJTlNFUlQgSU5UTyAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5ldmVudHMiKAogICAgIklEIiwgCiAgICAiTWVzc2FnZUlEIiwgCiAgICAiRXhjaGFuZ2UiLCAKICAgICJSb3V0ZUtleSIsCiAgICAiUXVldWUiCiAgKSBWQUxVRVMgKAogICAgJDEsCiAgICAkMiwKICAgICQzLAogICAgJDQsCiAgICAkNQogICk7Cg==")
+
+	r.Store("insert_flow_yml", "bmFtZTogSW5zZXJ0RmxvdwoKc2NyaXB0OgogIElOU0VSVCBJTlRPICIke1NDSEVNQX0iLiJjaXRhZGVsLmZsb3dzIigKICAgICJJRCIsIAogICAgIlN1YnNjcmlwdGlvbklEIiwgCiAgICAiU3RhdGVOYW1lIiwgCiAgICAiUmVtYXJrIiwgCiAgICAiQ3JlYXRpb25UaW1lIiwgCiAgICAiQ3JlYXRpb25UaW1lU3RyaW5nIgogICkgVkFMVUVTICgKICAgICQxLAogICAgJDIsCiAgICAkMywKICAgICQ0LAogICAgJDUsCiAgICAkNgogICk7Cg==")
+
+	r.Store("insert_message_yml", "bmFtZTogSW5zZXJ0TWVzc2FnZQoKc2NyaXB0OgogIElOU0VSVCBJTlRPICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIigKICAgICJJRCIsIAogICAgIk1lc3NhZ2VUeXBlIiwgCiAgICAiQ29udGVudCIsIAogICAgIlN0YXRlIiwgCiAgICAiU3RhdGVOYW1lIiwgCiAgICAiUmV0cnkiLCAKICAgICJDcmVhdGlvblRpbWUiLCAKICAgICJDcmVhdGlvblRpbWVTdHJpbmciLCAKICAgICJQdWJsaXNoZXIiLCAKICAgICJQdWJsaXNoVGltZSIsIAogICAgIlB1Ymxpc2hUaW1lU3RyaW5nIiwgCiAgICAiRW52IiwKICAgICJUcmFjZVBhcmVudCIsCiAgICAiVHJhY2VTdGF0ZSIKICApIFZBTFVFUyAoCiAgICAkMSwgCiAgICAkMiwgCiAgICAkMywgCiAgICAkNCwgCiAgICAkNSwgCiAgICAkNiwgCiAgICAkNywgCiAgICAkOCwKICAgICQ5LAogICAgJDEwLAogICAgJDExLAogICAgJDEyLAogICAgJDEzLAogICAgJDE0CiAgKTsK")
+
+	r.Store("insert_message_log_yml", "bmFtZTogSW5zZXJ0TWVzc2FnZUxvZwoKc2NyaXB0OiAKICBJTlNFUlQgSU5UTyAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlX2xvZ3MiKAogICAgIklEIiwgCiAgICAiTWVzc2FnZUlEIiwgCiAgICAiT3JpZ25hbFN0YXRlIiwgCiAgICAiT3JpZ25hbFN0YXRlTmFtZSIsIAogICAgIlN0YXRlIiwgCiAgICAiU3RhdGVOYW1lIiwgCiAgICAiQ3JlYXRpb25UaW1lIiwgCiAgICAiQ3JlYXRpb25UaW1lU3RyaW5nIiwKICAgICJSZW1hcmsiCiAgKSBWQUxVRVMgKAogICAgJDEsCiAgICAkMiwKICAgICQzLAogICAgJDQsCiAgICAkNSwKICAgICQ2LAogICAgJDcsCiAgICAkOCwKICAgICQ5CiAgKTsK")
+
+	r.Store("insert_outbox_row_yml", "bmFtZTogSW5zZXJ0T3V0Ym94Um93Cgp2YXJpYWJsZXM6IAogIFRBQkxFOiAnIm1hdGNoYV9vdXRib3giJwoKc2NyaXB0OgogIElOU0VSVCBJTlRPICR7VEFCTEV9IAogICAgKCJJRCIsICJLaW5kIiwgIlBheWxvYWQiLCAiU3RhdGUiLCAiQ3JlYXRpb25UaW1lIiwgIlRyYWNlUGFyZW50IiwgIlRyYWNlU3RhdGUiKSAKICBWQUxVRVMgCiAgICAoJDEsICQyLCAkMywgJDQsICQ1LCAkNiwgJDcpOwo=")
+
+	r.Store("insert_sub_template_yml", "bmFtZTogSW5zZXJ0U3ViVGVtcGxhdGUKCnNjcmlwdDoKICBJTlNFUlQgSU5UTyAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJfdGVtcGxhdGVzIigKICAgICJJRCIsCiAgICAiTmFtZSIsCiAgICAiRGVzY3JpcHRpb24iLAogICAgIkNyZWF0aW9uVGltZSIsCiAgICAiQ3JlYXRpb25UaW1lU3RyaW5nIgogICkgVkFMVUVTICgKICAgICQxLAogICAgJDIsCiAgICAkMywKICAgICQ0LAogICAgJDUKICApOwo=")
+
+	r.Store("insert_sub_template_detail_yml", "bmFtZTogSW5zZXJ0U3ViVGVtcGxhdGVEZXRhaWwKCnNjcmlwdDoKICBJTlNFUlQgSU5UTyAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJfdGVtcGxhdGVfZGV0YWlscyIoCiAgICAiSUQiLAogICAgIlRlbXBsYXRlSUQiLAogICAgIlJlY2VpdmVyVGFnIiwKICAgICJFeGNoYW5nZSIsCiAgICAiUm91dGVLZXkiLAogICAgIkNyZWF0aW9uVGltZSIsCiAgICAiQ3JlYXRpb25UaW1lU3RyaW5nIgogICkgVkFMVUVTICgKICAgICQxLAogICAgJDIsCiAgICAkMywKICAgICQ0LAogICAgJDUsCiAgICAkNiwKICAgICQ3CiAgKTsK")
+
+	r.Store("insert_subscription_yml", "bmFtZTogSW5zZXJ0U3Vic2NyaXB0aW9uCgpzY3JpcHQ6CiAgSU5TRVJUIElOVE8gIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyIoCiAgICAiSUQiLCAKICAgICJNZXNzYWdlSUQiLCAKICAgICJSZWNlaXZlclRhZyIsIAogICAgIkV4Y2hhbmdlIiwgCiAgICAiUm91dGVLZXkiLAogICAgIlN0YXRlTmFtZSIKICApIFZBTFVFUyAoCiAgICAkMSwKICAgICQyLAogICAgJDMsCiAgICAkNCwKICAgICQ1LAogICAgJDYKICApOwo=")
+
+	r.Store("list_events_yml", "bmFtZTogTGlzdEV2ZW50cwoKc2NyaXB0OgogIFNFTEVDVAogICAgbXNnLiJJRCIgQVMgIk1lc3NhZ2VJRCIsCiAgICBtc2cuIlN0YXRlTmFtZSIgQVMgIk1lc3NhZ2VTdGF0ZSIsCiAgICBtc2cuIlB1Ymxpc2hlciIsCiAgICBtc2cuIlB1Ymxpc2hUaW1lU3RyaW5nIiwKICAgIGV2ZS4iUm91dGVLZXkiLAogICAgZXZlLiJRdWV1ZSIsCiAgICBldmUuIkV4Y2hhbmdlIiwKICAgIGxvZy4iSUQiIEFTICJMb2dJRCIsCiAgICBsb2cuIk9yaWduYWxTdGF0ZU5hbWUiIEFTICJMb2dPcmlnbmFsIiwKICAgIGxvZy4iU3RhdGVOYW1lIiBBUyAiTG9nQ3VycmVudCIsCiAgICBsb2cuIkNyZWF0aW9uVGltZVN0cmluZyIgQVMgIkxvZ1RpbWUiLAogICAgc3ViLiJJRCIgQVMgIlN1YklEIiwKICAgIHN1Yi4iUmVjZWl2ZXJUYWciLAogICAgc3ViLiJTdGF0ZU5hbWUiIEFTICJTdWJTdGF0ZSIsCiAgICBzdWIuIkxhc3RNb3RpZnlUaW1lU3RyaW5nIiBBUyAiU3ViVGltZSIsCiAgICBmbG93LiJJRCIgQVMgIkZsb3dJRCIsCiAgICBmbG93LiJTdGF0ZU5hbWUiIEFTICJGbG93U3RhdGUiLAogICAgZmxvdy4iUmVtYXJrIiwKICAgIGZsb3cuIkNyZWF0aW9uVGltZVN0cmluZyIgQVMgIkZsb3dUaW1lIgogIEZST00gCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIgQVMgbXNnCiAgSU5ORVIgSk9JTiAKICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VfbG9ncyIgQVMgbG9nIE9OIG1zZy4iSUQiID0gbG9nLiJNZXNzYWdlSUQiCiAgSU5ORVIgSk9JTiAKICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLmV2ZW50cyIgQVMgZXZlIE9OIG1zZy4iSUQiID0gZXZlLiJNZXNzYWdlSUQiCiAgTEVGVCBKT0lOIAogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyIgQVMgc3ViIE9OIG1zZy4iSUQiID0gc3ViLiJNZXNzYWdlSUQiCiAgTEVGVCBKT0lOIAogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuZmxvd3MiIEFTIGZsb3cgT04gc3ViLiJJRCIgPSBmbG93LiJTdWJzY3JpcHRpb25JRCIKICBXSEVSRQogICAgbXNnLiJNZXNzYWdlVHlwZSI9J0V2ZW50Jw==")
+
+	r.Store("list_jobs_yml", "bmFtZTogTGlzdEpvYnMKCnNjcmlwdDoKICBTRUxFQ1QKCSAgbXNnLiJJRCIsCgkgIG1zZy4iU3RhdGVOYW1lIiwKCSAgbXNnLiJDcmVhdGlvblRpbWVTdHJpbmciLAoJICBtc2cuIlB1Ymxpc2hlciIsCgkgIG1zZy4iUHVibGlzaFRpbWUiLAoJICBqb2IuIkV4cHJlc3Npb24iLAoJICBqb2IuIktpbmROYW1lIiwKCSAgam9iLiJEZWxheVNlY29uZHMiLAoJICBzdWIuIklEIiBBUyAiU3ViSUQiLAoJICBzdWIuIkV4Y2hhbmdlIiwKCSAgc3ViLiJSb3V0ZUtleSIsCgkgIHN1Yi4iU3RhdGVOYW1lIiBBUyAiU3RhZ2UiLAoJICBzdWIuIkxhc3RNb3RpZnlUaW1lU3RyaW5nIiBBUyAiU3RhZ2VUaW1lIiwKCSAgZmxvdy4iSUQiIEFTICJGbG93SUQiLAoJICBmbG93LiJTdGF0ZU5hbWUiIEFTICJGbG93U3RhdGUiLAoJICBmbG93LiJSZW1hcmsiLAoJICBmbG93LiJDcmVhdGlvblRpbWVTdHJpbmciIEFTICJGbG93VGltZSIgCiAgRlJPTQoJICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIgQVMgbXNnCgkgIElOTkVSIEpPSU4gCiAgICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLmpvYnMiIEFTIGpvYiBPTiBtc2cuIklEIiA9IGpvYi4iTWVzc2FnZUlEIgoJICBJTk5FUiBKT0lOIAogICAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIiBBUyBzdWIgT04gbXNnLiJJRCIgPSBzdWIuIk1lc3NhZ2VJRCIKCSAgSU5ORVIgSk9JTiAKICAgICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuZmxvd3MiIEFTIGZsb3cgT04gc3ViLiJJRCIgPSBmbG93LiJTdWJzY3JpcHRpb25JRCIgCiAgV0hFUkUKCSAgbXNnLiJNZXNzYWdlVHlwZSIgPSAnQmFja2dyb3VkSm9iJw==")
+
+	r.Store("list_sub_templates_yml", "bmFtZTogTGlzdFN1YlRlbXBsYXRlcwoKc2NyaXB0OgogIFNFTEVDVAoJICAiSUQiLAoJICAiTmFtZSIsCgkgICJEZXNjcmlwdGlvbiIsCgkgICJDcmVhdGlvblRpbWUiLAoJICAiQ3JlYXRpb25UaW1lU3RyaW5nIiAKICBGUk9NCgkgICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1Yl90ZW1wbGF0ZXMiCiAgT1JERVIgQlkKCSAgIk5hbWUiCg==")
+
+	r.Store("outbox_row_failed_yml", "bmFtZTogT3V0Ym94Um93RmFpbGVkCgp2YXJpYWJsZXM6IAogIFRBQkxFOiAnIm1hdGNoYV9vdXRib3giJwoKc2NyaXB0OgogIFVQREFURSAKICAgICR7VEFCTEV9IAogIFNFVCAKICAgICJTdGF0ZSIgPSAkMSwgCiAgICAiQXR0ZW1wdHMiID0gIkF0dGVtcHRzIiArIDEsIAogICAgIlJlbWFyayIgPSAkMiAKICBXSEVSRSAKICAgICJJRCIgPSAkMzsK")
+
+	r.Store("outbox_row_relayed_yml", "bmFtZTogT3V0Ym94Um93UmVsYXllZAoKdmFyaWFibGVzOiAKICBUQUJMRTogJyJtYXRjaGFfb3V0Ym94IicKCnNjcmlwdDoKICBVUERBVEUgCiAgICAke1RBQkxFfSAKICBTRVQgCiAgICAiU3RhdGUiID0gJDEsIAogICAgIlJlbGF5VGltZSIgPSAkMiAKICBXSEVSRSAKICAgICJJRCIgPSAkMzsK")
+
+	r.Store("published_message_yml", "bmFtZTogUHVibGlzaGVkTWVzc2FnZQoKc2NyaXB0OiAKICBVUERBVEUgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIAogIFNFVCAiU3RhdGUiID0gJDEsCiAgICAiU3RhdGVOYW1lIiA9ICQyLAogICAgIlB1Ymxpc2hUaW1lIiA9ICQzLAogICAgIlB1Ymxpc2hUaW1lU3RyaW5nIiA9ICQ0IAogIFdIRVJFCgkgICJJRCIgPSAkNTs=")
+
+	r.Store("reset_message_retry_yml", "bmFtZTogUmVzZXRNZXNzYWdlUmV0cnkKCnNjcmlwdDoKICBVUERBVEUgCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIgCiAgU0VUIAogICAgIlJldHJ5IiA9IDAKICBXSEVSRSAKICAgICJJRCIgPSAkMTsK")
+
+	r.Store("reset_subscription_state_yml", "bmFtZTogUmVzZXRTdWJzY3JpcHRpb25TdGF0ZQoKc2NyaXB0OgogIFVQREFURSAKICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLnN1YnNjcmlwdGlvbnMiIAogIFNFVCAKICAgICJTdGF0ZU5hbWUiID0gJDEsIAogICAgIkxhc3RNb3RpZnlUaW1lIiA9ICQyLCAKICAgICJMYXN0TW90aWZ5VGltZVN0cmluZyIgPSAkMwogIFdIRVJFIAogICAgIklEIiA9ICQ0Owo=")
+
+	r.Store("update_sub_template_yml", "bmFtZTogVXBkYXRlU3ViVGVtcGxhdGUKCnNjcmlwdDoKICBVUERBVEUgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3ViX3RlbXBsYXRlcyIKICBTRVQKICAgICJEZXNjcmlwdGlvbiIgPSAkMQogIFdIRVJFCiAgICAiSUQiID0gJDI7Cg==")
+
+	r.Store("mysql/create_outbox_table_yml", "IyBNeVNRTCBoYXMgbm8gQ1JFQVRFIElOREVYIElGIE5PVCBFWElTVFM7IHRoZSBpbmRleCBpcyBkZWNsYXJlZCB3aXRoIHRoZQojIHRhYmxlIGluc3RlYWQuCm5hbWU6IENyZWF0ZU91dGJveFRhYmxlCgp2YXJpYWJsZXM6IAogIFRBQkxFOiAnIm1hdGNoYV9vdXRib3giJwogIElOREVYOiBtYXRjaGFfb3V0Ym94CgpzY3JpcHQ6CiAgQ1JFQVRFIFRBQkxFIElGIE5PVCBFWElTVFMgJHtUQUJMRX0gKAogICAgIklEIiBWQVJDSEFSKDM2KSBOT1QgTlVMTCBQUklNQVJZIEtFWSwKICAgICJLaW5kIiBWQVJDSEFSKDE2KSBOT1QgTlVMTCwKICAgICJQYXlsb2FkIiBURVhUIE5PVCBOVUxMLAogICAgIlN0YXRlIiBTTUFMTElOVCBOT1QgTlVMTCBERUZBVUxUIDAsCiAgICAiQXR0ZW1wdHMiIElOVCBOT1QgTlVMTCBERUZBVUxUIDAsCiAgICAiUmVtYXJrIiBURVhUIE5VTEwsCiAgICAiQ3JlYXRpb25UaW1lIiBCSUdJTlQgTk9UIE5VTEwsCiAgICAiUmVsYXlUaW1lIiBCSUdJTlQgTlVMTCwKICAgICJUcmFjZVBhcmVudCIgVkFSQ0hBUig2NCkgTk9UIE5VTEwgREVGQVVMVCAnJywKICAgICJUcmFjZVN0YXRlIiBWQVJDSEFSKDUxMikgTk9UIE5VTEwgREVGQVVMVCAnJywKICAgIElOREVYICJJWF8ke0lOREVYfV9TdGF0ZSIgKCJTdGF0ZSIsICJBdHRlbXB0cyIsICJDcmVhdGlvblRpbWUiKQogICk7Cg==")
+
+	r.Store("sqlite/findone_due_job_yml", "IyBTUUxpdGUgaGFzIG5vIHJvdyBsb2NrczogdHJhbnNhY3Rpb25zIHRha2UgdGhlIGRhdGFiYXNlIHdyaXRlIGxvY2sgd2hlbgojIHRoZXkgYmVnaW4gKF90eGxvY2s9aW1tZWRpYXRlKSwgc28gdGhlIGxvY2tpbmcgY2xhdXNlIGlzIGRyb3BwZWQuCm5hbWU6IEZpbmRPbmVEdWVKb2IKCnZhcmlhYmxlczogCiAgU1RBVEU6IDIKCnNjcmlwdDoKICBTRUxFQ1QKICAgIGpvYi4iSUQiLAogICAgam9iLiJNZXNzYWdlSUQiLAogICAgam9iLiJFeHByZXNzaW9uIiwKICAgIGpvYi4iS2luZCIsCiAgICBqb2IuIktpbmROYW1lIiwKICAgIGpvYi4iRGVsYXlTZWNvbmRzIiwKICAgIGpvYi4iTmV4dEZpcmVUaW1lIiwKICAgIGpvYi4iUmV0cnlQb2xpY3kiLAogICAgam9iLiJPY2N1cnJlbmNlVGltZSIKICBGUk9NCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5qb2JzIiBBUyBqb2IKICBJTk5FUiBKT0lOIAogICAgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIEFTIG1zZyBPTiBtc2cuIklEIiA9IGpvYi4iTWVzc2FnZUlEIgogIFdIRVJFCiAgICBqb2IuIk5leHRGaXJlVGltZSIgPD0gJDEKICAgIEFORCBtc2cuIlN0YXRlIiA9ICR7U1RBVEV9CiAgICBBTkQgKG1zZy4iRW52IiA9ICQyIE9SIG1zZy4iRW52IiA9ICcnIE9SICQyID0gJycpCiAgT1JERVIgQlkKICAgIGpvYi4iTmV4dEZpcmVUaW1lIiBBU0MKICBMSU1JVCAxOwo=")
+
+	r.Store("sqlite/findone_failed_message_yml", "IyBTUUxpdGUgaGFzIG5vIHJvdyBsb2NrczogdHJhbnNhY3Rpb25zIHRha2UgdGhlIGRhdGFiYXNlIHdyaXRlIGxvY2sgd2hlbgojIHRoZXkgYmVnaW4gKF90eGxvY2s9aW1tZWRpYXRlKSwgc28gdGhlIGxvY2tpbmcgY2xhdXNlIGlzIGRyb3BwZWQuCm5hbWU6IEZpbmRPbmVGYWlsZWRNZXNzYWdlCgpzY3JpcHQ6CiAgICBTRUxFQ1QKCSAgICBtc2cuIklEIiwgCiAgICAgIG1zZy4iTWVzc2FnZVR5cGUiLCAKICAgICAgbXNnLiJDb250ZW50IiwgCiAgICAgIG1zZy4iU3RhdGUiLCAKICAgICAgbXNnLiJTdGF0ZU5hbWUiLCAKICAgICAgbXNnLiJSZXRyeSIsIAogICAgICBtc2cuIkNyZWF0aW9uVGltZSIsIAogICAgICBtc2cuIkNyZWF0aW9uVGltZVN0cmluZyIsIAogICAgICBtc2cuIlB1Ymxpc2hlciIsIAogICAgICBtc2cuIlB1Ymxpc2hUaW1lIiwgCiAgICAgIG1zZy4iUHVibGlzaFRpbWVTdHJpbmciLCAKICAgICAgbXNnLiJFbnYiCiAgICBGUk9NCgkgICAgIiR7U0NIRU1BfSIuImNpdGFkZWwubWVzc2FnZXMiIEFTIG1zZwoJICBJTk5FUiBKT0lOICgKICAgICAgU0VMRUNUCgkgICAgICBpbm5lclN1Yi4iSUQiLAoJICAgICAgaW5uZXJTdWIuIk1lc3NhZ2VJRCIsCgkgICAgICBpbm5lckZsb3cuIlN0YXRlTmFtZSIsCgkgICAgICBpbm5lckZsb3cuIkNyZWF0aW9uVGltZSIgQVMgIkxhc3RNb3RpZnlUaW1lIiwKCSAgICAgIGlubmVyRmxvdy4iQ3JlYXRpb25UaW1lU3RyaW5nIiBBUyAiTGFzdE1vdGlmeVRpbWVTdHJpbmciIAogICAgICBGUk9NCgkgICAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIiBBUyBpbm5lclN1YgoJICAgIElOTkVSIEpPSU4gCiAgICAgICAgIiR7U0NIRU1BfSIuImNpdGFkZWwuZmxvd3MiIEFTIGlubmVyRmxvdyBPTiBpbm5lclN1Yi4iSUQiID0gaW5uZXJGbG93LiJTdWJzY3JpcHRpb25JRCIgCiAgICAgIFdIRVJFCgkgICAgICBpbm5lckZsb3cuIkNyZWF0aW9uVGltZSIgPSAoCiAgICAgICAgICBTRUxFQ1QKCSAgICAgICAgICBpbm5lcjEuIkNyZWF0aW9uVGltZSIgCiAgICAgICAgICBGUk9NICggCiAgICAgICAgICAgICAgU0VMRUNUIAogICAgICAgICAgICAgICAgc3ViSW5uZXIxLiJTdWJzY3JpcHRpb25JRCIsIE1BWChzdWJJbm5lcjEuIkNyZWF0aW9uVGltZSIpIEFTICJDcmVhdGlvblRpbWUiIAogICAgICAgICAgICAgIEZST00gCiAgICAgICAgICAgICAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5mbG93cyIgQVMgc3ViSW5uZXIxIAogICAgICAgICAgICAgIEdST1VQIEJZIHN1YklubmVyMS4iU3Vic2NyaXB0aW9uSUQiIAogICAgICAgICAgICApIEFTIGlubmVyMSAKICAgICAgICAgIFdIRVJFCgkgICAgICAgICAgaW5uZXIxLiJTdWJzY3JpcHRpb25JRCIgPSBpbm5lclN1Yi4iSUQiIAoJICAgICAgICApIAoJICAgICkgQVMgc3ViIE9OIG1zZy4iSUQiID0gc3ViLiJNZXNzYWdlSUQiIAogICAgV0hFUkUKICAgICAgbXNnLiJNZXNzYWdlVHlwZSI9ICdFdmVudCcKCSAgICBBTkQgbXNnLiJTdGF0ZSIgPSAyCiAgICAgIEFORCBtc2cuIkNyZWF0aW9uVGltZSIgPD0gJDEKICAgICAgQU5EIChtc2cuIkVudiIgPSAkMiBPUiBtc2cuIkVudiIgPSAnJyBPUiAkMiA9ICcnKQoJICAgIEFORCAoIAogICAgICAgIHN1Yi4iU3RhdGVOYW1lIiA9ICdGYWlsZWQnIAogICAgICAgIE9SICggCiAgICAgICAgICBzdWIuIlN0YXRlTmFtZSIgPD4gJ1N1Y2NlZWRlZCcgCiAgICAgICAgICBBTkQgc3ViLiJTdGF0ZU5hbWUiIDw+ICdGYWlsZWQnIAogICAgICAgICAgQU5EIHN1Yi4iTGFzdE1vdGlmeVRpbWUiIDw9ICQxIAogICAgICAgICkgCiAgICAgICAgT1IgKCAKICAgICAgICAgIFNFTEVDVCAKICAgICAgICAgICAgQ09VTlQgKCAqICkgCiAgICAgICAgICBGUk9NIAogICAgICAgICAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIiBBUyBzdWIyIAogICAgICAgICAgV0hFUkUgCiAgICAgICAgICAgIHN1YjIuIk1lc3NhZ2VJRCIgPSBtc2cuIklEIiAKICAgICAgICApID0gMAogICAgICApCgkgIExJTUlUIDE7Cg==")
+
+	r.Store("sqlite/findone_locked_job_yml", "IyBTUUxpdGUgaGFzIG5vIHJvdyBsb2NrczogdHJhbnNhY3Rpb25zIHRha2UgdGhlIGRhdGFiYXNlIHdyaXRlIGxvY2sgd2hlbgojIHRoZXkgYmVnaW4gKF90eGxvY2s9aW1tZWRpYXRlKSwgc28gdGhlIGxvY2tpbmcgY2xhdXNlIGlzIGRyb3BwZWQuCm5hbWU6IEZpbmRPbmVMb2NrZWRKb2IKCnNjcmlwdDoKICBTRUxFQ1QKICAgICJJRCIsCiAgICAiTWVzc2FnZUlEIiwKICAgICJFeHByZXNzaW9uIiwKICAgICJLaW5kIiwKICAgICJLaW5kTmFtZSIsCiAgICAiRGVsYXlTZWNvbmRzIiwKICAgICJOZXh0RmlyZVRpbWUiLAogICAgIlJldHJ5UG9saWN5IiwKICAgICJPY2N1cnJlbmNlVGltZSIKICBGUk9NCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5qb2JzIgogIFdIRVJFCiAgICAiTWVzc2FnZUlEIiA9ICQxOwo=")
+
+	r.Store("sqlite/findone_locked_message_yml", "IyBTUUxpdGUgaGFzIG5vIHJvdyBsb2NrczogdHJhbnNhY3Rpb25zIHRha2UgdGhlIGRhdGFiYXNlIHdyaXRlIGxvY2sgd2hlbgojIHRoZXkgYmVnaW4gKF90eGxvY2s9aW1tZWRpYXRlKSwgc28gdGhlIGxvY2tpbmcgY2xhdXNlIGlzIGRyb3BwZWQuCm5hbWU6IEZpbmRPbmVMb2NrZWRNZXNzYWdlCgpzY3JpcHQ6CiAgU0VMRUNUCiAgICAiSUQiLCAKICAgICJNZXNzYWdlVHlwZSIsIAogICAgIkNvbnRlbnQiLCAKICAgICJTdGF0ZSIsIAogICAgIlN0YXRlTmFtZSIsIAogICAgIlJldHJ5IiwgCiAgICAiQ3JlYXRpb25UaW1lIiwgCiAgICAiQ3JlYXRpb25UaW1lU3RyaW5nIiwgCiAgICAiUHVibGlzaGVyIiwgCiAgICAiUHVibGlzaFRpbWUiLCAKICAgICJQdWJsaXNoVGltZVN0cmluZyIsIAogICAgIkVudiIsCiAgICAiVHJhY2VQYXJlbnQiLAogICAgIlRyYWNlU3RhdGUiCiAgRlJPTSAKICAgICIke1NDSEVNQX0iLiJjaXRhZGVsLm1lc3NhZ2VzIgogIFdIRVJFCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIuIklEIj0kMQo=")
+
+	r.Store("sqlite/findone_locked_subscription_yml", "IyBTUUxpdGUgaGFzIG5vIHJvdyBsb2NrczogdHJhbnNhY3Rpb25zIHRha2UgdGhlIGRhdGFiYXNlIHdyaXRlIGxvY2sgd2hlbgojIHRoZXkgYmVnaW4gKF90eGxvY2s9aW1tZWRpYXRlKSwgc28gdGhlIGxvY2tpbmcgY2xhdXNlIGlzIGRyb3BwZWQuCm5hbWU6IEZpbmRPbmVMb2NrZWRTdWJzY3JpcHRpb24KCnNjcmlwdDoKICBTRUxFQ1QKICAgICJJRCIsIAogICAgIk1lc3NhZ2VJRCIsIAogICAgIlJlY2VpdmVyVGFnIiwgCiAgICAiRXhjaGFuZ2UiLCAKICAgICJSb3V0ZUtleSIsCiAgICAiU3RhdGVOYW1lIgogIEZST00gCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIgogIFdIRVJFCiAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIi4iSUQiPSQxIE9SICgiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIi4iTWVzc2FnZUlEIj0kMiBBTkQgIiR7U0NIRU1BfSIuImNpdGFkZWwuc3Vic2NyaXB0aW9ucyIuIlJlY2VpdmVyVGFnIj0kMyk7Cg==")
+
+	r.Store("sqlite/findone_pending_outbox_row_yml", "IyBTUUxpdGUgaGFzIG5vIHJvdyBsb2NrczogdHJhbnNhY3Rpb25zIHRha2UgdGhlIGRhdGFiYXNlIHdyaXRlIGxvY2sgd2hlbgojIHRoZXkgYmVnaW4gKF90eGxvY2s9aW1tZWRpYXRlKSwgc28gdGhlIGxvY2tpbmcgY2xhdXNlIGlzIGRyb3BwZWQuCm5hbWU6IEZpbmRPbmVQZW5kaW5nT3V0Ym94Um93Cgp2YXJpYWJsZXM6IAogIFRBQkxFOiAnIm1hdGNoYV9vdXRib3giJwoKc2NyaXB0OgogIFNFTEVDVAogICAgIklEIiwKICAgICJLaW5kIiwKICAgICJQYXlsb2FkIiwKICAgICJBdHRlbXB0cyIsCiAgICAiVHJhY2VQYXJlbnQiLAogICAgIlRyYWNlU3RhdGUiCiAgRlJPTQogICAgJHtUQUJMRX0KICBXSEVSRQogICAgIlN0YXRlIiA9ICQxCiAgT1JERVIgQlkKICAgICJBdHRlbXB0cyIgQVNDLAogICAgIkNyZWF0aW9uVGltZSIgQVNDCiAgTElNSVQgMTsK")
+
+	r.Store("sqlite/findone_rollback_message_yml", "IyBTUUxpdGUgaGFzIG5vIHJvdyBsb2NrczogdHJhbnNhY3Rpb25zIHRha2UgdGhlIGRhdGFiYXNlIHdyaXRlIGxvY2sgd2hlbgojIHRoZXkgYmVnaW4gKF90eGxvY2s9aW1tZWRpYXRlKSwgc28gdGhlIGxvY2tpbmcgY2xhdXNlIGlzIGRyb3BwZWQuCm5hbWU6IEZpbmRPbmVSb2xsYmFja01lc3NhZ2UKCnNjcmlwdDoKICBTRUxFQ1QKCSAgbXNnLiJJRCIsCgkgIG1zZy4iTWVzc2FnZVR5cGUiLAoJCW1zZy4iUHVibGlzaGVyIiwKCSAgbXNnLiJDb250ZW50IiwKCSAgZXZlLiJSb3V0ZUtleSIsCgkgIGV2ZS4iUXVldWUiLAoJICBldmUuIkV4Y2hhbmdlIiwKCSAgbXNnLiJFbnYiLAoJICBtc2cuIlRyYWNlUGFyZW50IiwKCSAgbXNnLiJUcmFjZVN0YXRlIgogIEZST00gKAogICAgU0VMRUNUCgkgICAgaW5uZXJNc2cuIklEIiwKCQkJaW5uZXJNc2cuIk1lc3NhZ2VUeXBlIiwKCSAgICBpbm5lck1zZy4iUHVibGlzaGVyIiwKCSAgICBpbm5lck1zZy4iQ29udGVudCIsCgkgICAgaW5uZXJNc2cuIkVudiIsCgkgICAgaW5uZXJNc2cuIlRyYWNlUGFyZW50IiwKCSAgICBpbm5lck1zZy4iVHJhY2VTdGF0ZSIKICAgIEZST00KCSAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIgQVMgaW5uZXJNc2cgCiAgICBXSEVSRQoJICAgIGlubmVyTXNnLiJNZXNzYWdlVHlwZSIgPSAnRXZlbnQnIAoJICAgIEFORCBpbm5lck1zZy4iU3RhdGUiID0gNCAKCSAgICBBTkQgKGlubmVyTXNnLiJFbnYiID0gJDEgT1IgaW5uZXJNc2cuIkVudiIgPSAnJyBPUiAkMSA9ICcnKQoJICBMSU1JVCAxCgkpIEFTIG1zZwoJSU5ORVIgSk9JTiAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5ldmVudHMiIEFTIGV2ZSBPTiBtc2cuIklEIiA9IGV2ZS4iTWVzc2FnZUlEIgo=")
+
+	r.Store("sqlite/findone_succeed_message_yml", "IyBTUUxpdGUgaGFzIG5vIHJvdyBsb2NrczogdHJhbnNhY3Rpb25zIHRha2UgdGhlIGRhdGFiYXNlIHdyaXRlIGxvY2sgd2hlbgojIHRoZXkgYmVnaW4gKF90eGxvY2s9aW1tZWRpYXRlKSwgc28gdGhlIGxvY2tpbmcgY2xhdXNlIGlzIGRyb3BwZWQuCm5hbWU6IEZpbmRPbmVTdWNjZWVkTWVzc2FnZQoKc2NyaXB0OgogIFNFTEVDVAoJICAgIG1zZy4iSUQiLCAKICAgICAgbXNnLiJNZXNzYWdlVHlwZSIsIAogICAgICBtc2cuIkNvbnRlbnQiLCAKICAgICAgbXNnLiJTdGF0ZSIsIAogICAgICBtc2cuIlN0YXRlTmFtZSIsIAogICAgICBtc2cuIlJldHJ5IiwgCiAgICAgIG1zZy4iQ3JlYXRpb25UaW1lIiwgCiAgICAgIG1zZy4iQ3JlYXRpb25UaW1lU3RyaW5nIiwgCiAgICAgIG1zZy4iUHVibGlzaGVyIiwgCiAgICAgIG1zZy4iUHVibGlzaFRpbWUiLCAKICAgICAgbXNnLiJQdWJsaXNoVGltZVN0cmluZyIsIAogICAgICBtc2cuIkVudiIKICAgIEZST00KCSAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5tZXNzYWdlcyIgQVMgbXNnIAogICAgV0hFUkUKCSAgICAoIAogICAgICAgIFNFTEVDVCAKICAgICAgICAgIENPVU5UICggKiApIAogICAgICAgIEZST00gCiAgICAgICAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIiBBUyBzdWIgCiAgICAgICAgV0hFUkUgCiAgICAgICAgICBzdWIuIk1lc3NhZ2VJRCIgPSBtc2cuIklEIiAKICAgICAgICAgIEFORCBzdWIuIlN0YXRlTmFtZSIgPD4gJ1N1Y2NlZWRlZCcgCiAgICAgICkgPSAwCiAgICAgIEFORCAoIAogICAgICAgIFNFTEVDVCAKICAgICAgICAgIENPVU5UICggKiApIAogICAgICAgIEZST00gCiAgICAgICAgICAiJHtTQ0hFTUF9Ii4iY2l0YWRlbC5zdWJzY3JpcHRpb25zIiBBUyBzdWIyIAogICAgICAgIFdIRVJFIAogICAgICAgICAgc3ViMi4iTWVzc2FnZUlEIiA9IG1zZy4iSUQiIAogICAgICApID4gMAogICAgICBBTkQgIkNyZWF0aW9uVGltZSIgPD0gJDEKICAgICAgQU5EIChtc2cuIkVudiIgPSAkMiBPUiBtc2cuIkVudiIgPSAnJyBPUiAkMiA9ICcnKQogICAgICBBTkQgIlN0YXRlIj0yCiAgICAgIEFORCAiTWVzc2FnZVR5cGUiID0gJ0V2ZW50JwogICAgTElNSVQgMTsK")
 
 	return r
 }
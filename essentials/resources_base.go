@@ -29,6 +29,21 @@ func (r *ScriptResources) GetResources() map[string][]byte {
 	return result
 }
 
+// GetResourcesOf returns the resources embedded from the sub directory dir,
+// keyed without the directory, or the top level ones when dir is empty.
+func (r *ScriptResources) GetResourcesOf(dir string) map[string][]byte {
+	result := make(map[string][]byte)
+	for key, value := range r.GetResources() {
+		idx := strings.LastIndex(key, "/")
+		if idx < 0 && dir == "" {
+			result[key] = value
+		} else if idx >= 0 && key[:idx] == dir {
+			result[key[idx+1:]] = value
+		}
+	}
+	return result
+}
+
 func (r *ScriptResources) Store(key string, data string) error {
 	d, err := base64.StdEncoding.DecodeString(data)
 	str := strings.Replace(string(d), "	", "  ", -1)
@@ -16,18 +16,12 @@ func NewRollbackMessageProcessor(sess *Session) Processor {
 }
 
 func (p *RollbackMessageProcessor) Process() error {
-	//enablishing mq connection
-	mqConn, err := p.sess.CreateConnectionFactory().RabbitMQ()
-	if err != nil {
-		return WrapError("RollbackMessageProcessor", err)
-	}
-	defer mqConn.Close()
-	channel, err := mqConn.Channel()
-	if err != nil {
-		return WrapError("RollbackMessageProcessor", err)
-	}
-	defer channel.Close()
-	err = channel.ExchangeDeclare("rollback@exchange.matcha.message", "direct", true, false, false, false, make(amqp.Table))
+	// the channel is put in transaction mode, so it is not taken from the pool
+	return p.sess.AMQP().WithChannel(p.process)
+}
+
+func (p *RollbackMessageProcessor) process(channel *amqp.Channel) error {
+	err := channel.ExchangeDeclare("rollback@exchange.matcha.message", "direct", true, false, false, false, make(amqp.Table))
 	if err != nil {
 		return WrapError("RollbackMessageProcessor", err)
 	}
@@ -47,14 +41,16 @@ func (p *RollbackMessageProcessor) Process() error {
 		return WrapError("RollbackMessageProcessor", err)
 	}
 	defer transact.Rollback()
-	row, err := transact.QueryScriptRow("FindOneRollbackMessage")
+	row, err := transact.QueryScriptRow("FindOneRollbackMessage", p.sess.Env())
 	if err != nil {
 		return WrapError("RollbackMessageProcessor", err)
 	}
 
 	var payload DeliveryMessage
-	var exchange, routeKey, queue, publisher string
-	err = row.Scan(&payload.MessageID, &payload.MessageType, &publisher, &payload.Content, &routeKey, &queue, &exchange)
+	var exchange, routeKey, queue, publisher, env string
+	msg := &Message{State: MessageFailed, StateName: MessageFailed.String()}
+	err = row.Scan(&payload.MessageID, &payload.MessageType, &publisher, &payload.Content, &routeKey, &queue, &exchange, &env,
+		&msg.TraceParent, &msg.TraceState)
 	if err == sql.ErrNoRows {
 		p.sess.Logger().Debugln(WrapError("RollbackMessageProcessor", err))
 		return nil
@@ -70,13 +66,29 @@ func (p *RollbackMessageProcessor) Process() error {
 	payload.Extensions["x-matcha-routekey"] = routeKey
 	payload.Extensions["x-matcha-publisher"] = publisher
 	payload.Extensions["x-matcha-tag"] = "rollback_processor"
+	payload.Extensions["x-matcha-env"] = env
 
-	content, err := json.Marshal(&payload)
+	span := p.sess.StartSpan("publish rollback", msg.Trace())
+	span.SetAttribute("message_id", payload.MessageID).SetAttribute("exchange", "rollback@exchange.matcha.message")
+	err = p.publish(channel, msg, &payload, span, transact)
+	span.Finish(err)
+	if err != nil {
+		return err
+	}
+	return ProcessorWaitNext
+}
+
+// publish marks the message rolled back and sends it to every subscriber, in
+// one database and one channel transaction.
+func (p *RollbackMessageProcessor) publish(channel *amqp.Channel, msg *Message, payload *DeliveryMessage, span *Span, transact *DbTransaction) error {
+	headers := make(amqp.Table)
+	span.Context().Inject(payload.Extensions, headers)
+	content, err := json.Marshal(payload)
 	if err != nil {
 		return WrapError("RollbackMessageProcessor", err)
 	}
 
-	msg := &Message{ID: payload.MessageID, State: MessageFailed, StateName: MessageFailed.String()}
+	msg.ID = payload.MessageID
 	err = msg.ChangeState(MessageRollback, transact)
 	if err != nil {
 		return WrapError("RollbackMessageProcessor", err)
@@ -88,11 +100,13 @@ func (p *RollbackMessageProcessor) Process() error {
 	}
 
 	pub := amqp.Publishing{
-		Body: content,
+		Headers: headers,
+		Body:    content,
 	}
 
 	for _, sub := range subs {
 		err = channel.Publish("rollback@exchange.matcha.message", sub.ReceiverTag, false, false, pub)
+		Publishes.Inc("rollback@exchange.matcha.message", publishResult(err))
 		if err != nil {
 			return WrapError("RollbackMessageProcessor", err)
 		}
@@ -118,5 +132,5 @@ func (p *RollbackMessageProcessor) Process() error {
 		return WrapError("RollbackMessageProcessor", err)
 	}
 
-	return ProcessorWaitNext
+	return nil
 }
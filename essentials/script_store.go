@@ -0,0 +1,181 @@
+package essentials
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	ymsql "github.com/standardcore/go-ymsql"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// RequiredScripts lists every script the code executes by name. They are
+// checked to exist and compile whenever the scripts are loaded.
+var RequiredScripts = []string{
+	"ChangeJobFireTime",
+	"ChangeJobOccurrence",
+	"ChangeMessageState",
+	"ChangeSubscriptionState",
+	"CreateOutboxTable",
+	"DeleteSubTemplate",
+	"DeleteSubTemplateDetails",
+	"FetchFlows",
+	"FetchMessageLogs",
+	"FetchOccurrenceDeliveries",
+	"FetchSubTemplateDetails",
+	"FetchSubscriptions",
+	"FindMessageRetry",
+	"FindOneDueJob",
+	"FindOneEvent",
+	"FindOneFailedMessage",
+	"FindOneLockedJob",
+	"FindOneLockedSubscription",
+	"FindOneMessage",
+	"FindOnePendingOutboxRow",
+	"FindOneRollbackMessage",
+	"FindOneSucceedMessage",
+	"FindOneTemplate",
+	"FindProcessingMessage",
+	"FindSubscription",
+	"FindUnscheduledJobs",
+	"IncreaseMessageRetry",
+	"InsertBackgroundJob",
+	"InsertEvent",
+	"InsertFlow",
+	"InsertMessage",
+	"InsertMessageLog",
+	"InsertOutboxRow",
+	"InsertSubTemplate",
+	"InsertSubTemplateDetail",
+	"InsertSubscription",
+	"ListEvents",
+	"ListJobs",
+	"ListSubTemplates",
+	"OutboxRowFailed",
+	"OutboxRowRelayed",
+	"PublishedMessage",
+	"ResetMessageRetry",
+	"ResetSubscriptionState",
+	"UpdateSubTemplate",
+}
+
+// loadScripts builds a script store from the embedded resources, overridden
+// by name by the embedded scripts of the session dialect and then by the
+// *.yml files of the `scripts` directory when that parameter is set, and
+// checks it. It returns the names of the scripts overridden by the directory.
+func (sess *Session) loadScripts() (ymsql.Store, []string, error) {
+	schema, err := sess.Require("dbprefix")
+	if err != nil {
+		return nil, nil, err
+	}
+	store := ymsql.NewYMLStore()
+	store.SETEnv("SCHEMA", sess.dialect.Schema(schema))
+	err = storeEmbeddedScripts(store, sess.res, sess.dialect)
+	if err != nil {
+		return nil, nil, WrapError("Session.loadScripts", err)
+	}
+
+	overrides := make([]string, 0)
+	if dir := sess.LoadOrEmpty("scripts"); dir != "" {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return nil, nil, WrapError("Session.loadScripts", err)
+		}
+		for _, file := range files {
+			if file.IsDir() || !strings.HasSuffix(file.Name(), ".yml") {
+				continue
+			}
+			data, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+			if err != nil {
+				return nil, nil, WrapError(fmt.Sprintf("Session.loadScripts:%s", file.Name()), err)
+			}
+			var m ymsql.YMLModel
+			err = yaml.Unmarshal(data, &m)
+			if err == nil {
+				err = store.Store(data)
+			}
+			if err != nil {
+				return nil, nil, WrapError(fmt.Sprintf("Session.loadScripts:%s", file.Name()), err)
+			}
+			overrides = append(overrides, m.Name)
+		}
+	}
+	sort.Strings(overrides)
+
+	for _, name := range append(RequiredScripts, overrides...) {
+		script, err := store.Load(name)
+		if err == nil {
+			_, err = script.Compile()
+		}
+		if err != nil {
+			return nil, nil, WrapError(fmt.Sprintf("Session.loadScripts:%s", name), err)
+		}
+	}
+	return store, overrides, nil
+}
+
+// storeEmbeddedScripts stores the embedded scripts in store, overridden by
+// name by the embedded scripts of dialect.
+func storeEmbeddedScripts(store ymsql.Store, res *ScriptResources, dialect Dialect) error {
+	for k, v := range res.GetResourcesOf("") {
+		err := store.Store(v)
+		if err != nil {
+			return WrapError(k, err)
+		}
+	}
+	if dir := dialect.resourceDir(); dir != "" {
+		for k, v := range res.GetResourcesOf(dir) {
+			err := store.Store(v)
+			if err != nil {
+				return WrapError(dir+"/"+k, err)
+			}
+		}
+	}
+	return nil
+}
+
+var embeddedScripts sync.Map
+
+// CompileScript compiles the embedded script name of dialect with variables
+// set over its own. It serves SQL run outside the matcha database, such as
+// on the outbox tables of services, so `scripts` overrides do not apply.
+func CompileScript(dialect Dialect, name string, variables map[string]string) (string, error) {
+	v, ok := embeddedScripts.Load(dialect)
+	if !ok {
+		store := ymsql.NewYMLStore()
+		err := storeEmbeddedScripts(store, NewScriptResources(), dialect)
+		if err != nil {
+			return "", WrapError("CompileScript", err)
+		}
+		v, _ = embeddedScripts.LoadOrStore(dialect, store)
+	}
+	script, err := v.(ymsql.Store).Load(name)
+	if err != nil {
+		return "", WrapError("CompileScript:"+name, err)
+	}
+	for k, value := range variables {
+		script.Variables()[k] = value
+	}
+	query, err := script.Compile()
+	if err != nil {
+		return "", WrapError("CompileScript:"+name, err)
+	}
+	return query, nil
+}
+
+// ReloadScripts reloads the `scripts` directory over the embedded scripts.
+// The running scripts are only replaced when every script loads and compiles.
+func (sess *Session) ReloadScripts() ([]string, error) {
+	store, overrides, err := sess.loadScripts()
+	if err != nil {
+		return nil, err
+	}
+	sess.scriptsMu.Lock()
+	sess.scripts = store
+	sess.scriptsMu.Unlock()
+	sess.logger.Infoln(fmt.Sprintf("scripts reloaded, %d overridden : %s", len(overrides), strings.Join(overrides, ", ")))
+	return overrides, nil
+}
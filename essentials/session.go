@@ -1,8 +1,11 @@
 package essentials
 
 import (
+	"database/sql"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/standardcore/go-logging"
 	ymsql "github.com/standardcore/go-ymsql"
@@ -13,16 +16,26 @@ import (
 type Session struct {
 	parameters   *Parameters
 	declarations *DeclarationMap
-	scripts      ymsql.Store
 	res          *ScriptResources
 	logger       logging.Logger
+	amqp         *AmqpConnectionManager
+	dialect      Dialect
+	env          string
+
+	scriptsMu sync.RWMutex
+	scripts   ymsql.Store
+
+	dbMu sync.Mutex
+	db   *sql.DB
+
+	spanMu       sync.RWMutex
+	spanExporter SpanExporter
 }
 
 func NewSession(parameters map[string]string, declarations *DeclarationsConfig) (*Session, error) {
 	sess := &Session{
 		parameters:   &Parameters{},
 		declarations: &DeclarationMap{},
-		scripts:      ymsql.NewYMLStore(),
 		res:          NewScriptResources(),
 		logger:       logging.NewLogger(),
 	}
@@ -34,31 +47,20 @@ func NewSession(parameters map[string]string, declarations *DeclarationsConfig)
 		sess.declarations.ConfigureExchangeDeclarations(declarations.Exchanges)
 		sess.declarations.ConfigureQueueDeclarations(declarations.Queues)
 	}
-	// scriptDirectory, ok := sess.parameters.Load("scripts")
-	// if ok {
-	// 	err := sess.scripts.StoreFromDirectory(scriptDirectory.(string))
-	// 	if err != nil {
-	// 		return nil, err
-	// 	}
-	// }
-	var wg sync.WaitGroup
-	if res := sess.res.GetResources(); res != nil && len(res) > 1 {
-		wg.Add(len(res))
-		for k, v := range res {
-			err := sess.scripts.Store(v)
-			if err != nil {
-				return nil, WrapError(fmt.Sprintf("NewSession:%s", k), err)
-			}
-			wg.Done()
-		}
+	dialect, err := DialectOf(sess.LoadOrEmpty("db_driver_name"))
+	if err != nil {
+		return nil, err
 	}
-	wg.Wait()
-
-	schema, err := sess.Require("dbprefix")
+	sess.dialect = dialect
+	scripts, overrides, err := sess.loadScripts()
 	if err != nil {
 		return nil, err
 	}
-	sess.scripts.SETEnv("SCHEMA", schema)
+	sess.scripts = scripts
+	if len(overrides) > 0 {
+		sess.logger.Infoln(fmt.Sprintf("%d scripts overridden : %s", len(overrides), strings.Join(overrides, ", ")))
+	}
+	sess.amqp = NewAmqpConnectionManager(sess)
 	return sess, nil
 }
 
@@ -71,6 +73,8 @@ func (sess *Session) Logger() logging.Logger {
 }
 
 func (sess *Session) Script(name string) (ymsql.Scripting, error) {
+	sess.scriptsMu.RLock()
+	defer sess.scriptsMu.RUnlock()
 	return sess.scripts.Load(name)
 }
 
@@ -104,51 +108,96 @@ func (sess *Session) GetQueue(key string) (*Queue, error) {
 }
 
 func (sess *Session) ExchangeDeclare(key string) error {
-	factory := sess.CreateConnectionFactory()
-	conn, err := factory.RabbitMQ()
+	err := sess.amqp.WithChannel(func(channel *amqp.Channel) error {
+		return sess.declarations.ExchangeDeclare(channel, key)
+	})
 	if err != nil {
 		return WrapError("Session.ExchangeDeclare", err)
 	}
-	defer conn.Close()
-
-	channel, err := conn.Channel()
-	if err != nil {
-		return WrapError("Session.ExchangeDeclare", err)
-	}
-	defer channel.Close()
-
-	return sess.declarations.ExchangeDeclare(channel, key)
+	return nil
 }
 
 func (sess *Session) QueueDeclare(key string) (amqp.Queue, error) {
-	factory := sess.CreateConnectionFactory()
-	conn, err := factory.RabbitMQ()
+	var queue amqp.Queue
+	err := sess.amqp.WithChannel(func(channel *amqp.Channel) error {
+		var err error
+		queue, err = sess.declarations.QueueDeclare(channel, key)
+		return err
+	})
 	if err != nil {
-		return amqp.Queue{}, WrapError("Session.QueueDeclare", err)
+		return queue, WrapError("Session.QueueDeclare", err)
 	}
-	defer conn.Close()
-
-	channel, err := conn.Channel()
-	if err != nil {
-		return amqp.Queue{}, WrapError("Session.QueueDeclare", err)
-	}
-	defer channel.Close()
-	return sess.declarations.QueueDeclare(channel, key)
+	return queue, nil
 }
 
 func (sess *Session) TryExchangeDeclare(key string) {
-	sess.declarations.TryExchangeDeclare(sess.CreateConnectionFactory(), key)
+	_ = sess.ExchangeDeclare(key)
 }
 
 func (sess *Session) TryQueueDeclare(key string) {
-	sess.declarations.TryQueueDeclare(sess.CreateConnectionFactory(), key)
+	_, _ = sess.QueueDeclare(key)
 }
 
-//-------- helpers
-func (sess *Session) CreateAtJobFactory() *AtJobFactory {
-	return &AtJobFactory{sess: sess}
+// Dialect returns the SQL dialect of the `db_driver_name` parameter.
+func (sess *Session) Dialect() Dialect {
+	return sess.dialect
+}
+
+// AMQP returns the connection manager shared by every publisher and consumer
+// of the session.
+func (sess *Session) AMQP() *AmqpConnectionManager {
+	return sess.amqp
 }
 
+// database returns the connection pool of the session, opening it on first
+// use with the `db_max_open_conns`, `db_max_idle_conns` and
+// `db_conn_max_lifetime` (seconds) parameters.
+func (sess *Session) database() (*sql.DB, error) {
+	sess.dbMu.Lock()
+	defer sess.dbMu.Unlock()
+	if sess.db != nil {
+		return sess.db, nil
+	}
+	dbDriverName, err := sess.Require("db_driver_name")
+	if err != nil {
+		return nil, err
+	}
+	dbConnStr, err := sess.Require("datasource")
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(dbDriverName, sess.dialect.DataSource(dbConnStr))
+	if err != nil {
+		return nil, err
+	}
+	if v := loadPositiveInt(sess, "db_max_open_conns", 0); v > 0 {
+		db.SetMaxOpenConns(v)
+	}
+	if v := loadPositiveInt(sess, "db_max_idle_conns", 0); v > 0 {
+		db.SetMaxIdleConns(v)
+	}
+	if v := loadPositiveInt(sess, "db_conn_max_lifetime", 0); v > 0 {
+		db.SetConnMaxLifetime(time.Duration(v) * time.Second)
+	}
+	sess.db = db
+	return db, nil
+}
+
+// Close releases the database pool and the AMQP connection of the session.
+func (sess *Session) Close() error {
+	err := sess.amqp.Close()
+	sess.dbMu.Lock()
+	defer sess.dbMu.Unlock()
+	if sess.db != nil {
+		if dbErr := sess.db.Close(); err == nil {
+			err = dbErr
+		}
+		sess.db = nil
+	}
+	return err
+}
+
+//-------- helpers
 func (sess *Session) CreateConnectionFactory() *ConnectionFactory {
 	return &ConnectionFactory{sess: sess}
 }
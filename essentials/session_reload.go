@@ -0,0 +1,164 @@
+package essentials
+
+import (
+	"fmt"
+	"sort"
+)
+
+// restartParameters are read once when the session or its workers start.
+// Reload keeps their running value and reports them.
+var restartParameters = map[string]bool{
+	"db_driver_name":               true,
+	"dbprefix":                     true,
+	"amqp_channel_pool_size":       true,
+	"amqp_channel_acquire_timeout": true,
+	"amqp_reconnect_max_delay":     true,
+	"scheduler_interval":           true,
+	"outbox_interval":              true,
+	"outbox_max_attempts":          true,
+	"trace_exporter":               true,
+}
+
+// databaseParameters configure the connection pool, which is reopened when
+// one of them changes.
+var databaseParameters = map[string]bool{
+	"datasource":           true,
+	"db_max_open_conns":    true,
+	"db_max_idle_conns":    true,
+	"db_conn_max_lifetime": true,
+}
+
+// ReloadReport tells what a reload changed. RestartRequired lists the
+// settings whose new value only takes effect after a restart.
+type ReloadReport struct {
+	Applied         []string `json:"applied"`
+	RestartRequired []string `json:"restart_required"`
+	Declared        []string `json:"declared"`
+	Errors          []string `json:"errors"`
+}
+
+func NewReloadReport() *ReloadReport {
+	return &ReloadReport{
+		Applied:         make([]string, 0),
+		RestartRequired: make([]string, 0),
+		Declared:        make([]string, 0),
+		Errors:          make([]string, 0),
+	}
+}
+
+// Reload applies a new set of parameters and declarations to the running
+// session. Changed parameters are used from the next read on: the database
+// pool is reopened when `datasource` or a `db_*` setting changes, the AMQP
+// connection is dialed again when `rabbitmq` changes and the scripts are
+// reloaded when `scripts` changes. New or changed exchanges and queues are
+// declared. Consumers keep the queue they consume until a restart.
+func (sess *Session) Reload(parameters map[string]string, declarations *DeclarationsConfig) *ReloadReport {
+	report := NewReloadReport()
+
+	keys := make(map[string]bool)
+	for k := range parameters {
+		keys[k] = true
+	}
+	sess.parameters.Range(func(k, _ interface{}) bool {
+		keys[k.(string)] = true
+		return true
+	})
+	changed := make([]string, 0)
+	for k := range keys {
+		old, hadOld := sess.parameters.Load(k)
+		v, hasNew := parameters[k]
+		if hadOld == hasNew && (!hasNew || old.(string) == v) {
+			continue
+		}
+		changed = append(changed, k)
+	}
+	sort.Strings(changed)
+
+	reopenDatabase, redialAMQP, reloadScripts := false, false, false
+	renamedExchanges, renamedQueues := make([]string, 0), make([]string, 0)
+	for _, k := range changed {
+		if restartParameters[k] {
+			report.RestartRequired = append(report.RestartRequired, k)
+			continue
+		}
+		if v, ok := parameters[k]; ok {
+			sess.parameters.Store(k, v)
+		} else {
+			sess.parameters.Delete(k)
+		}
+		report.Applied = append(report.Applied, k)
+		if _, ok := sess.declarations.exchanges.Load(k); ok {
+			renamedExchanges = append(renamedExchanges, k)
+		}
+		if _, ok := sess.declarations.queues.Load(k); ok {
+			renamedQueues = append(renamedQueues, k)
+			report.RestartRequired = append(report.RestartRequired, k)
+		}
+		switch {
+		case databaseParameters[k]:
+			reopenDatabase = true
+		case k == "rabbitmq":
+			redialAMQP = true
+		case k == "scripts":
+			reloadScripts = true
+		}
+	}
+
+	if reopenDatabase {
+		sess.resetDatabase()
+	}
+	if redialAMQP {
+		sess.amqp.Reset()
+	}
+	if reloadScripts {
+		_, err := sess.ReloadScripts()
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+		}
+	}
+
+	sess.declarations.Restore(renamedExchanges, renamedQueues)
+	exchanges, queues := sess.declarations.Reconfigure(declarations)
+	for _, k := range mergeKeys(exchanges, renamedExchanges) {
+		err := sess.ExchangeDeclare(k)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("exchange %s : %s", k, err.Error()))
+			continue
+		}
+		report.Declared = append(report.Declared, "exchange:"+k)
+	}
+	for _, k := range mergeKeys(queues, renamedQueues) {
+		_, err := sess.QueueDeclare(k)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("queue %s : %s", k, err.Error()))
+			continue
+		}
+		report.Declared = append(report.Declared, "queue:"+k)
+	}
+	return report
+}
+
+func mergeKeys(a []string, b []string) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(a)+len(b))
+	for _, k := range append(a, b...) {
+		if !seen[k] {
+			seen[k] = true
+			result = append(result, k)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// resetDatabase makes the next use open a new connection pool. The old pool
+// is closed in the background once its queries are done.
+func (sess *Session) resetDatabase() {
+	sess.dbMu.Lock()
+	db := sess.db
+	sess.db = nil
+	sess.dbMu.Unlock()
+	if db != nil {
+		go db.Close()
+	}
+}
@@ -0,0 +1,232 @@
+//go:build cgo
+// +build cgo
+
+package essentials
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// newSQLiteSession returns a session on a fresh SQLite database with every
+// migration applied.
+func newSQLiteSession(t *testing.T) *Session {
+	t.Helper()
+	sess, err := NewSession(map[string]string{
+		"dbprefix":       "matcha",
+		"db_driver_name": "sqlite3",
+		"datasource":     "file:" + filepath.Join(t.TempDir(), "matcha.db"),
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sess.Close() })
+	migrator, err := NewMigrator(sess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = migrator.Up()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sess
+}
+
+func beginTx(t *testing.T, sess *Session) *DbTransaction {
+	t.Helper()
+	conn, err := sess.CreateConnectionFactory().Database()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := conn.BeginTx(sql.LevelDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestDialectRebind(t *testing.T) {
+	args := []interface{}{"a", "b"}
+	query, bound := DialectSQLite.Rebind(`SELECT $2, $1, $2`, args)
+	if query != `SELECT ?2, ?1, ?2` || !reflect.DeepEqual(bound, args) {
+		t.Errorf("sqlite: got %q %v", query, bound)
+	}
+	query, bound = DialectMySQL.Rebind(`SELECT $2, $1, $2`, args)
+	if query != `SELECT ?, ?, ?` || !reflect.DeepEqual(bound, []interface{}{"b", "a", "b"}) {
+		t.Errorf("mysql: got %q %v", query, bound)
+	}
+	query, bound = DialectPostgres.Rebind(`SELECT $2, $1`, args)
+	if query != `SELECT $2, $1` || !reflect.DeepEqual(bound, args) {
+		t.Errorf("postgres: got %q %v", query, bound)
+	}
+}
+
+func TestSQLiteMigrations(t *testing.T) {
+	sess := newSQLiteSession(t)
+	migrator, err := NewMigrator(sess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := migrator.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+	for _, s := range status {
+		if !s.Applied {
+			t.Errorf("migration %04d_%s not applied", s.Version, s.Name)
+		}
+	}
+
+	reverted, err := migrator.Down(len(status))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reverted) != len(status) {
+		t.Fatalf("reverted %d of %d migrations", len(reverted), len(status))
+	}
+	applied, err := migrator.Up()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(status) {
+		t.Fatalf("applied %d of %d migrations", len(applied), len(status))
+	}
+}
+
+func TestSQLiteMessageScripts(t *testing.T) {
+	sess := newSQLiteSession(t)
+	tx := beginTx(t, sess)
+	defer tx.Rollback()
+
+	payload := &Payload{
+		Env:         "test",
+		MessageType: "order.created",
+		Content:     `{"id":1}`,
+		Subscriptions: []*SubscriptionPayload{
+			{Tag: "billing", Exchange: "matcha", RouteKey: "billing"},
+			{Tag: "shipping", Exchange: "matcha", RouteKey: "shipping"},
+		},
+		Headers: map[string]interface{}{"x-matcha-client": "shop", "x-matcha-time": "1500000000"},
+	}
+	msg, err := AppendMessage(payload, nil, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := FindOneMessage(msg.ID, true, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.MessageType != "order.created" || found.Publisher != "shop" || found.Env != "test" || found.State != MessageScheduled {
+		t.Errorf("unexpected message %+v", found)
+	}
+
+	err = found.Published(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, err := FindProcessingMessageIDs(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []string{msg.ID}) {
+		t.Errorf("processing messages %v, want [%s]", ids, msg.ID)
+	}
+	found.State = MessageProcessing
+
+	retry, err := found.IncreaseRetry(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retry != 1 {
+		t.Errorf("retry %d, want 1", retry)
+	}
+	err = found.ResetRetry(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = found.ChangeStateWithRemark(MessageSucceeded, "done", tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs, err := found.FetchLogs(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 3 || logs[len(logs)-1].Remark != "done" {
+		t.Errorf("unexpected logs %+v", logs)
+	}
+	found, err = FindOneMessage(msg.ID, false, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.State != MessageSucceeded || found.Retry != 0 {
+		t.Errorf("state %s retry %d, want Succeeded 0", found.StateName, found.Retry)
+	}
+}
+
+func TestSQLiteSubscriptionScripts(t *testing.T) {
+	sess := newSQLiteSession(t)
+	tx := beginTx(t, sess)
+	defer tx.Rollback()
+
+	msg, err := AppendMessage(&Payload{
+		MessageType:   "order.created",
+		Content:       "{}",
+		Subscriptions: []*SubscriptionPayload{{Tag: "billing", Exchange: "matcha", RouteKey: "billing"}},
+	}, nil, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subs, err := msg.FetchSubscriptions(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subs) != 1 || subs[0].ReceiverTag != "billing" || subs[0].StateName != "Scheduled" {
+		t.Fatalf("unexpected subscriptions %+v", subs)
+	}
+	sub, err := FineOneLockSubscription(subs[0].ID, msg.ID, "billing", tx)
+	if err != nil || sub == nil {
+		t.Fatalf("locking subscription: %v", err)
+	}
+
+	err = msg.ChangeSubscriptionState("Succeeded", "billing", tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err = FindSubscription(sub.ID, msg.ID, "billing", tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.StateName != "Succeeded" {
+		t.Errorf("state %s, want Succeeded", sub.StateName)
+	}
+
+	err = sub.Reset("Scheduled", tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flows, err := sub.FetchFlows(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(flows) == 0 {
+		t.Error("no flows recorded")
+	}
+	sub, err = FindSubscription(sub.ID, msg.ID, "billing", tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.StateName != "Scheduled" {
+		t.Errorf("state %s after reset, want Scheduled", sub.StateName)
+	}
+}
@@ -0,0 +1,186 @@
+package essentials
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/standardcore/go-logging"
+)
+
+// Fields are the structured fields of a log entry, such as `message_id`,
+// `receiver_tag` or `processor`.
+type Fields map[string]interface{}
+
+// FieldsProvider is a provider that records the fields of an entry apart
+// from its message. Providers that are not get the fields appended to the
+// message as key=value pairs.
+type FieldsProvider interface {
+	logging.LoggerProvider
+	LogFields(level logging.Level, msg string, fields Fields)
+}
+
+// StructuredLogger is a logging.Logger whose entries carry fields, added
+// with With.
+type StructuredLogger struct {
+	shared *loggerProviders
+	fields Fields
+}
+
+type loggerProviders struct {
+	mu        sync.RWMutex
+	providers []logging.LoggerProvider
+}
+
+func NewStructuredLogger() *StructuredLogger {
+	return &StructuredLogger{shared: &loggerProviders{providers: make([]logging.LoggerProvider, 0)}}
+}
+
+// With returns a logger writing to the same providers whose entries also
+// carry fields.
+func (l *StructuredLogger) With(fields Fields) logging.Logger {
+	merged := make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &StructuredLogger{shared: l.shared, fields: merged}
+}
+
+// LogWith returns logger with fields added to its entries. Loggers other than
+// StructuredLogger are returned as they are.
+func LogWith(logger logging.Logger, fields Fields) logging.Logger {
+	if l, ok := logger.(interface {
+		With(fields Fields) logging.Logger
+	}); ok {
+		return l.With(fields)
+	}
+	return logger
+}
+
+func (l *StructuredLogger) AddProvider(provider logging.LoggerProvider) {
+	l.shared.mu.Lock()
+	l.shared.providers = append(l.shared.providers, provider)
+	l.shared.mu.Unlock()
+}
+
+func (l *StructuredLogger) TryAddProvider(provider logging.LoggerProvider, err error) error {
+	if err != nil {
+		return err
+	}
+	l.AddProvider(provider)
+	return nil
+}
+
+func (l *StructuredLogger) log(level logging.Level, msg string) {
+	msg = strings.TrimRight(msg, "\n")
+	l.shared.mu.RLock()
+	defer l.shared.mu.RUnlock()
+	for _, p := range l.shared.providers {
+		logToProvider(p, level, msg, l.fields)
+	}
+}
+
+func logToProvider(p logging.LoggerProvider, level logging.Level, msg string, fields Fields) {
+	if len(fields) == 0 {
+		p.Log(level, msg)
+		return
+	}
+	if fp, ok := p.(FieldsProvider); ok {
+		fp.LogFields(level, msg, fields)
+		return
+	}
+	p.Log(level, msg+" "+formatFields(fields))
+}
+
+// formatFields formats fields as key=value pairs sorted by key.
+func formatFields(fields Fields) string {
+	pairs := make([]string, 0, len(fields))
+	for _, k := range sortedFieldKeys(fields) {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, fields[k]))
+	}
+	return strings.Join(pairs, " ")
+}
+
+func sortedFieldKeys(fields Fields) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// jsonLogEntry encodes an entry as a JSON object with `time`, `level`, `msg`
+// and the fields.
+func jsonLogEntry(level logging.Level, msg string, fields Fields) []byte {
+	entry := make(map[string]interface{}, len(fields)+3)
+	for k, v := range fields {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		entry[k] = v
+	}
+	entry["time"] = time.Now().Format(time.RFC3339Nano)
+	entry["level"] = strings.ToLower(level.String())
+	entry["msg"] = msg
+	line, err := json.Marshal(entry)
+	if err != nil {
+		line, _ = json.Marshal(map[string]string{"time": entry["time"].(string), "level": entry["level"].(string), "msg": msg})
+	}
+	return line
+}
+
+func (l *StructuredLogger) Print(v ...interface{}) { l.log(logging.LevelInfo, fmt.Sprint(v...)) }
+func (l *StructuredLogger) Printf(format string, args ...interface{}) {
+	l.log(logging.LevelInfo, fmt.Sprintf(format, args...))
+}
+func (l *StructuredLogger) Println(v ...interface{}) { l.log(logging.LevelInfo, fmt.Sprintln(v...)) }
+func (l *StructuredLogger) Debug(v ...interface{})   { l.log(logging.LevelDebug, fmt.Sprint(v...)) }
+func (l *StructuredLogger) Debugf(format string, args ...interface{}) {
+	l.log(logging.LevelDebug, fmt.Sprintf(format, args...))
+}
+func (l *StructuredLogger) Debugln(v ...interface{}) { l.log(logging.LevelDebug, fmt.Sprintln(v...)) }
+func (l *StructuredLogger) Info(v ...interface{})    { l.log(logging.LevelInfo, fmt.Sprint(v...)) }
+func (l *StructuredLogger) Infof(format string, args ...interface{}) {
+	l.log(logging.LevelInfo, fmt.Sprintf(format, args...))
+}
+func (l *StructuredLogger) Infoln(v ...interface{}) { l.log(logging.LevelInfo, fmt.Sprintln(v...)) }
+func (l *StructuredLogger) Warn(v ...interface{})   { l.log(logging.LevelWarn, fmt.Sprint(v...)) }
+func (l *StructuredLogger) Warnf(format string, args ...interface{}) {
+	l.log(logging.LevelWarn, fmt.Sprintf(format, args...))
+}
+func (l *StructuredLogger) Warnln(v ...interface{}) { l.log(logging.LevelWarn, fmt.Sprintln(v...)) }
+func (l *StructuredLogger) Error(v ...interface{})  { l.log(logging.LevelError, fmt.Sprint(v...)) }
+func (l *StructuredLogger) Errorf(format string, args ...interface{}) {
+	l.log(logging.LevelError, fmt.Sprintf(format, args...))
+}
+func (l *StructuredLogger) Errorln(v ...interface{}) { l.log(logging.LevelError, fmt.Sprintln(v...)) }
+func (l *StructuredLogger) Fatal(v ...interface{})   { l.log(logging.LevelFatal, fmt.Sprint(v...)) }
+func (l *StructuredLogger) Fatalf(format string, args ...interface{}) {
+	l.log(logging.LevelFatal, fmt.Sprintf(format, args...))
+}
+func (l *StructuredLogger) Fatalln(v ...interface{}) { l.log(logging.LevelFatal, fmt.Sprintln(v...)) }
+func (l *StructuredLogger) Panic(v ...interface{})   { l.log(logging.LevelPanic, fmt.Sprint(v...)) }
+func (l *StructuredLogger) Panicf(format string, args ...interface{}) {
+	l.log(logging.LevelPanic, fmt.Sprintf(format, args...))
+}
+func (l *StructuredLogger) Panicln(v ...interface{}) { l.log(logging.LevelPanic, fmt.Sprintln(v...)) }
+
+func (l *StructuredLogger) Sync() error {
+	l.shared.mu.RLock()
+	defer l.shared.mu.RUnlock()
+	var first error
+	for _, p := range l.shared.providers {
+		err := p.Sync()
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
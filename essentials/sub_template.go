@@ -3,8 +3,11 @@ package essentials
 import (
 	"database/sql"
 	"errors"
+	"time"
 )
 
+// SubTemplate is a named set of subscribers a message can be published to
+// instead of listing them in every payload.
 type SubTemplate struct {
 	ID                 string
 	Name               string
@@ -13,6 +16,62 @@ type SubTemplate struct {
 	CreationTimeString string
 }
 
+func (s *SubTemplate) Append(executor DbExecutor) (string, error) {
+	if s.Name == "" {
+		return "", WrapError("SubTemplate.Append", errors.New("field Name is required"))
+	}
+	if s.ID == "" {
+		s.ID = NewOrderedUUID()
+	}
+	s.CreationTime = time.Now().Unix()
+	s.CreationTimeString = FormatTime(time.Now())
+	_, err := executor.ExecScript("InsertSubTemplate", s.ID, s.Name, s.Description, s.CreationTime, s.CreationTimeString)
+	if err != nil {
+		return "", err
+	}
+	return s.ID, nil
+}
+
+func (s *SubTemplate) Update(executor DbExecutor) error {
+	if s.ID == "" {
+		return WrapError("SubTemplate.Update", errors.New("field ID is required"))
+	}
+	_, err := executor.ExecScript("UpdateSubTemplate", s.Description, s.ID)
+	return err
+}
+
+// Delete removes the template and its details.
+func (s *SubTemplate) Delete(executor DbExecutor) error {
+	if s.ID == "" {
+		return WrapError("SubTemplate.Delete", errors.New("field ID is required"))
+	}
+	_, err := executor.ExecScript("DeleteSubTemplateDetails", s.ID)
+	if err != nil {
+		return err
+	}
+	_, err = executor.ExecScript("DeleteSubTemplate", s.ID)
+	return err
+}
+
+// ReplaceDetails replaces the subscribers of the template with details.
+func (s *SubTemplate) ReplaceDetails(details []*SubTemplateDetails, executor DbExecutor) error {
+	if s.ID == "" {
+		return WrapError("SubTemplate.ReplaceDetails", errors.New("field ID is required"))
+	}
+	_, err := executor.ExecScript("DeleteSubTemplateDetails", s.ID)
+	if err != nil {
+		return err
+	}
+	for _, item := range details {
+		item.TemplateID = s.ID
+		_, err = item.Append(executor)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *SubTemplate) Details(executor DbExecutor) ([]*SubTemplateDetails, error) {
 	if s.ID == "" {
 		return nil, WrapError("SubTemplate", errors.New("field ID is required"))
@@ -44,6 +103,27 @@ type SubTemplateDetails struct {
 	CreationTimeString string
 }
 
+func (d *SubTemplateDetails) Append(executor DbExecutor) (string, error) {
+	if d.TemplateID == "" {
+		return "", WrapError("SubTemplateDetails.Append", errors.New("field TemplateID is required"))
+	}
+	if d.ReceiverTag == "" || d.Exchange == "" {
+		return "", WrapError("SubTemplateDetails.Append", errors.New("fields ReceiverTag and Exchange are required"))
+	}
+	if d.ID == "" {
+		d.ID = NewOrderedUUID()
+	}
+	d.CreationTime = time.Now().Unix()
+	d.CreationTimeString = FormatTime(time.Now())
+	_, err := executor.ExecScript("InsertSubTemplateDetail", d.ID, d.TemplateID, d.ReceiverTag, d.Exchange, d.RouteKey, d.CreationTime, d.CreationTimeString)
+	if err != nil {
+		return "", err
+	}
+	return d.ID, nil
+}
+
+// FindOneTemplate finds the template whose ID is id or whose name is name. It
+// returns nil when there is none.
 func FindOneTemplate(id string, name string, executor DbExecutor) (*SubTemplate, error) {
 	row, err := executor.QueryScriptRow("FindOneTemplate", id, name)
 	if err != nil {
@@ -51,7 +131,7 @@ func FindOneTemplate(id string, name string, executor DbExecutor) (*SubTemplate,
 	}
 
 	var template SubTemplate
-	err = row.Scan(&template.ID, template.Name, template.Description, template.CreationTime, template.CreationTimeString)
+	err = row.Scan(&template.ID, &template.Name, &template.Description, &template.CreationTime, &template.CreationTimeString)
 	if sql.ErrNoRows == err {
 		return nil, nil
 	} else if err != nil {
@@ -59,3 +139,22 @@ func FindOneTemplate(id string, name string, executor DbExecutor) (*SubTemplate,
 	}
 	return &template, nil
 }
+
+// ListTemplates returns every template, ordered by name.
+func ListTemplates(executor DbExecutor) ([]*SubTemplate, error) {
+	rows, err := executor.QueryScript("ListSubTemplates")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	results := make([]*SubTemplate, 0)
+	for rows.Next() {
+		var item SubTemplate
+		err = rows.Scan(&item.ID, &item.Name, &item.Description, &item.CreationTime, &item.CreationTimeString)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &item)
+	}
+	return results, nil
+}
@@ -2,6 +2,7 @@ package essentials
 
 import (
 	"database/sql"
+	"fmt"
 	"time"
 )
 
@@ -24,7 +25,7 @@ func (p *SucceedMessageProcessor) Process() error {
 		return WrapError("SucceedMessageProcessor", err)
 	}
 	defer transact.Rollback()
-	row, err := transact.QueryScriptRow("FindOneSucceedMessage", time.Now().Add(time.Minute*-1).Unix())
+	row, err := transact.QueryScriptRow("FindOneSucceedMessage", time.Now().Add(time.Minute*-1).Unix(), p.sess.Env())
 	if err != nil {
 		return WrapError("SucceedMessageProcessor", err)
 	}
@@ -49,7 +50,7 @@ func (p *SucceedMessageProcessor) Process() error {
 		return WrapError("SucceedMessageProcessor", err)
 	}
 
-	p.sess.Logger().Infof("message %s succeeded", message.ID)
+	LogWith(p.sess.Logger(), Fields{"message_id": message.ID, "processor": "SucceedMessageProcessor"}).Infoln(fmt.Sprintf("message %s succeeded", message.ID))
 
 	return ProcessorWaitNext
 }
@@ -0,0 +1,285 @@
+package essentials
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+const (
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+)
+
+// TraceContext is a W3C trace context: the trace a message belongs to and
+// the span it was last handled by.
+type TraceContext struct {
+	TraceID string
+	SpanID  string
+	Flags   string
+	State   string
+}
+
+// ParseTraceContext reads a `traceparent` and `tracestate` pair. It reports
+// false when traceparent is missing or malformed, in which case tracestate is
+// ignored too.
+func ParseTraceContext(traceparent string, tracestate string) (TraceContext, bool) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 {
+		return TraceContext{}, false
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if !isHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return TraceContext{}, false
+	}
+	if !isHex(traceID, 32) || traceID == strings.Repeat("0", 32) {
+		return TraceContext{}, false
+	}
+	if !isHex(spanID, 16) || spanID == strings.Repeat("0", 16) {
+		return TraceContext{}, false
+	}
+	if !isHex(flags, 2) {
+		return TraceContext{}, false
+	}
+	return TraceContext{TraceID: traceID, SpanID: spanID, Flags: flags, State: strings.TrimSpace(tracestate)}, true
+}
+
+func isHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func (t TraceContext) Valid() bool {
+	return t.TraceID != "" && t.SpanID != ""
+}
+
+// TraceParent formats the context as a version 00 `traceparent`, or "" when
+// it is not valid.
+func (t TraceContext) TraceParent() string {
+	if !t.Valid() {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-%s", t.TraceID, t.SpanID, t.Flags)
+}
+
+// Inject adds the context to the extensions of a delivery and the headers
+// of its AMQP publishing. Either may be nil.
+func (t TraceContext) Inject(exts map[string]string, headers amqp.Table) {
+	if !t.Valid() {
+		return
+	}
+	if exts != nil {
+		exts[TraceParentHeader] = t.TraceParent()
+		if t.State != "" {
+			exts[TraceStateHeader] = t.State
+		}
+	}
+	if headers != nil {
+		headers[TraceParentHeader] = t.TraceParent()
+		if t.State != "" {
+			headers[TraceStateHeader] = t.State
+		}
+	}
+}
+
+// RequestTrace returns the trace context of the `traceparent` and
+// `tracestate` headers of request.
+func RequestTrace(request *http.Request) TraceContext {
+	if request == nil {
+		return TraceContext{}
+	}
+	t, _ := ParseTraceContext(request.Header.Get(TraceParentHeader), request.Header.Get(TraceStateHeader))
+	return t
+}
+
+// Trace returns the trace context the payload carries in its headers.
+func (p *Payload) Trace() TraceContext {
+	traceparent, _ := p.Headers[TraceParentHeader].(string)
+	tracestate, _ := p.Headers[TraceStateHeader].(string)
+	t, _ := ParseTraceContext(traceparent, tracestate)
+	return t
+}
+
+// SetTrace makes the payload carry t, which is stored with its message.
+func (p *Payload) SetTrace(t TraceContext) {
+	if p.Headers == nil {
+		p.Headers = make(map[string]interface{})
+	}
+	p.Headers[TraceParentHeader] = t.TraceParent()
+	p.Headers[TraceStateHeader] = t.State
+}
+
+// Trace returns the trace context stored with the message.
+func (m *Message) Trace() TraceContext {
+	t, _ := ParseTraceContext(m.TraceParent, m.TraceState)
+	return t
+}
+
+// Span is a timed operation of a trace: the ingest of a request, or the
+// persistence or the publish of a message.
+type Span struct {
+	TraceID      string            `json:"trace_id"`
+	SpanID       string            `json:"span_id"`
+	ParentSpanID string            `json:"parent_span_id,omitempty"`
+	Name         string            `json:"name"`
+	Start        time.Time         `json:"start"`
+	End          time.Time         `json:"end"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	Error        string            `json:"error,omitempty"`
+
+	flags string
+	state string
+	sess  *Session
+}
+
+// StartSpan starts a span named name as a child of parent, or as the root of
+// a new trace when parent is not valid.
+func (sess *Session) StartSpan(name string, parent TraceContext) *Span {
+	span := &Span{
+		SpanID:     randomHex(8),
+		Name:       name,
+		Start:      time.Now(),
+		Attributes: make(map[string]string),
+		flags:      "01",
+		sess:       sess,
+	}
+	if parent.Valid() {
+		span.TraceID = parent.TraceID
+		span.ParentSpanID = parent.SpanID
+		span.flags = parent.Flags
+		span.state = parent.State
+	} else {
+		span.TraceID = randomHex(16)
+	}
+	return span
+}
+
+// StartPayloadSpan starts a span for the message of payload. A payload that
+// carries no trace context starts a trace, which its message then belongs to.
+func (sess *Session) StartPayloadSpan(name string, payload *Payload) *Span {
+	trace := payload.Trace()
+	span := sess.StartSpan(name, trace)
+	if !trace.Valid() {
+		payload.SetTrace(span.Context())
+	}
+	return span
+}
+
+// StartRequestSpan starts the span of the ingest of payload from request,
+// continuing the trace of the `traceparent` header, or else of the payload.
+// The payload then carries the context of the span.
+func (sess *Session) StartRequestSpan(name string, request *http.Request, payload *Payload) *Span {
+	parent := RequestTrace(request)
+	if !parent.Valid() {
+		parent = payload.Trace()
+	}
+	span := sess.StartSpan(name, parent)
+	payload.SetTrace(span.Context())
+	return span
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Context returns the trace context to propagate to what the span causes.
+func (s *Span) Context() TraceContext {
+	return TraceContext{TraceID: s.TraceID, SpanID: s.SpanID, Flags: s.flags, State: s.state}
+}
+
+func (s *Span) SetAttribute(key string, value string) *Span {
+	s.Attributes[key] = value
+	return s
+}
+
+// Finish ends the span, failed when err is not nil, and exports it.
+func (s *Span) Finish(err error) {
+	s.End = time.Now()
+	if err != nil {
+		s.Error = err.Error()
+	}
+	exporter := s.sess.SpanExporter()
+	if exporter == nil {
+		return
+	}
+	if err := exporter.ExportSpan(s); err != nil {
+		s.sess.Logger().Errorln(WrapError("Span.Finish", err))
+	}
+}
+
+// SpanExporter sends finished spans to a tracing backend.
+type SpanExporter interface {
+	ExportSpan(span *Span) error
+}
+
+// SETSpanExporter makes the session export its spans with exporter. Spans
+// are still propagated, but not exported, when it is nil.
+func (sess *Session) SETSpanExporter(exporter SpanExporter) {
+	sess.spanMu.Lock()
+	sess.spanExporter = exporter
+	sess.spanMu.Unlock()
+}
+
+func (sess *Session) SpanExporter() SpanExporter {
+	sess.spanMu.RLock()
+	defer sess.spanMu.RUnlock()
+	return sess.spanExporter
+}
+
+// WriterSpanExporter writes spans to w as JSON, one per line.
+type WriterSpanExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterSpanExporter(w io.Writer) *WriterSpanExporter {
+	return &WriterSpanExporter{w: w}
+}
+
+func (e *WriterSpanExporter) ExportSpan(span *Span) error {
+	line, err := json.Marshal(span)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err = e.w.Write(append(line, '\n'))
+	return err
+}
+
+// NewSpanExporter returns the exporter of a `trace_exporter` parameter:
+// "stdout", "file:<path>" to append to a file, or "" and "none" for no
+// export.
+func NewSpanExporter(spec string) (SpanExporter, error) {
+	switch {
+	case spec == "" || spec == "none":
+		return nil, nil
+	case spec == "stdout":
+		return NewWriterSpanExporter(os.Stdout), nil
+	case strings.HasPrefix(spec, "file:"):
+		file, err := os.OpenFile(strings.TrimPrefix(spec, "file:"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		return NewWriterSpanExporter(file), nil
+	}
+	return nil, fmt.Errorf("trace_exporter `%s` should be `stdout`, `file:<path>` or `none`", spec)
+}
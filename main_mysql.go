@@ -0,0 +1,8 @@
+//go:build mysql
+// +build mysql
+
+package main
+
+// Registers the "mysql" driver for `db_driver_name`; build with
+// `go build -tags mysql`.
+import _ "github.com/go-sql-driver/mysql"
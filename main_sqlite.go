@@ -0,0 +1,8 @@
+//go:build sqlite
+// +build sqlite
+
+package main
+
+// Registers the "sqlite3" driver for `db_driver_name`. It needs cgo; build
+// with `go build -tags sqlite`.
+import _ "github.com/mattn/go-sqlite3"
@@ -0,0 +1,142 @@
+// Package outbox lets a service hand events and background jobs to matcha
+// through a table in its own database. The row is written inside the service's
+// business transaction, so the message exists exactly when the business change
+// does; the matcha Relay later drains the table into matcha.
+package outbox
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/standardcore/Matcha/essentials"
+)
+
+// Kind tells the relay how matcha should take a payload.
+type Kind string
+
+const (
+	// Event is published like /v1/event/publish.
+	Event = Kind("Event")
+	// Job is scheduled like /v1/job/create.
+	Job = Kind("Job")
+)
+
+const (
+	statePending  = 0
+	stateRelayed  = 1
+	stateRejected = 2
+)
+
+// Executor is satisfied by *sql.DB and *sql.Tx. Pass the transaction of the
+// business change so the outbox row commits or rolls back with it.
+type Executor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// Outbox writes payloads into one outbox table.
+type Outbox struct {
+	table   string
+	dialect essentials.Dialect
+}
+
+// New returns an Outbox writing to table, e.g. `"public"."matcha_outbox"`,
+// of a PostgreSQL database unless Dialect says otherwise. The table is
+// created with CreateTableScript.
+func New(table string) *Outbox {
+	return &Outbox{table: table, dialect: essentials.DialectPostgres}
+}
+
+// Dialect sets the SQL dialect of the database holding the table.
+func (o *Outbox) Dialect(dialect essentials.Dialect) *Outbox {
+	o.dialect = dialect
+	return o
+}
+
+// CreateTableScript returns the PostgreSQL DDL of an outbox table.
+func CreateTableScript(table string) string {
+	script, _ := CreateDialectTableScript(essentials.DialectPostgres, table)
+	return script
+}
+
+// CreateDialectTableScript returns the DDL of an outbox table in dialect.
+func CreateDialectTableScript(dialect essentials.Dialect, table string) (string, error) {
+	return essentials.CompileScript(dialect, "CreateOutboxTable", tableVariables(table))
+}
+
+// Publish stores an event payload; see Write.
+func (o *Outbox) Publish(payload *essentials.Payload, executor Executor) (string, error) {
+	return o.Write(Event, payload, executor)
+}
+
+// Schedule stores a background job payload; see Write.
+func (o *Outbox) Schedule(payload *essentials.Payload, executor Executor) (string, error) {
+	return o.Write(Job, payload, executor)
+}
+
+// Write stores payload in the outbox table through executor and returns the
+// ID matcha will give the message. The ID doubles as the idempotency key of
+// the relay, so a row is never turned into two messages. The trace context
+// the payload carries, see essentials.Payload.SetTrace, is stored with the
+// row and continued by the relay.
+func (o *Outbox) Write(kind Kind, payload *essentials.Payload, executor Executor) (string, error) {
+	if payload == nil {
+		return "", essentials.WrapError("Outbox.Write", errors.New("payload was required"))
+	}
+	if kind != Event && kind != Job {
+		return "", essentials.WrapError("Outbox.Write", fmt.Errorf("unknown kind '%s'", kind))
+	}
+	if payload.MessageID == "" {
+		payload.MessageID = essentials.NewOrderedUUID()
+	}
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return "", essentials.WrapError("Outbox.Write", err)
+	}
+	trace := payload.Trace()
+	traceState := ""
+	if trace.Valid() {
+		traceState = trace.State
+	}
+	query, args, err := compile(o.dialect, "InsertOutboxRow", o.table,
+		payload.MessageID, string(kind), string(content), statePending, time.Now().Unix(), trace.TraceParent(), traceState)
+	if err == nil {
+		_, err = executor.Exec(query, args...)
+	}
+	if err != nil {
+		return "", essentials.WrapError("Outbox.Write", err)
+	}
+	return payload.MessageID, nil
+}
+
+// compile returns the outbox script name for table in dialect, with args
+// bound to its placeholders.
+func compile(dialect essentials.Dialect, name string, table string, args ...interface{}) (string, []interface{}, error) {
+	query, err := essentials.CompileScript(dialect, name, tableVariables(table))
+	if err != nil {
+		return "", nil, err
+	}
+	query, args = dialect.Rebind(query, args)
+	return query, args, nil
+}
+
+func tableVariables(table string) map[string]string {
+	return map[string]string{"TABLE": table, "INDEX": indexSuffix(table)}
+}
+
+func indexSuffix(table string) string {
+	result := make([]byte, 0, len(table))
+	for i := 0; i < len(table); i++ {
+		c := table[i]
+		if c == '"' {
+			continue
+		}
+		if c == '.' {
+			c = '_'
+		}
+		result = append(result, c)
+	}
+	return string(result)
+}
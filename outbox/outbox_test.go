@@ -0,0 +1,271 @@
+//go:build cgo
+// +build cgo
+
+package outbox
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/standardcore/Matcha/essentials"
+)
+
+// newSQLiteSession returns a session on a fresh SQLite database with every
+// migration applied.
+func newSQLiteSession(t *testing.T) *essentials.Session {
+	t.Helper()
+	sess, err := essentials.NewSession(map[string]string{
+		"dbprefix":       "matcha",
+		"db_driver_name": "sqlite3",
+		"datasource":     "file:" + filepath.Join(t.TempDir(), "matcha.db"),
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sess.Close() })
+	migrator, err := essentials.NewMigrator(sess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = migrator.Up()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sess
+}
+
+// newSQLiteRelay returns a relay of sess, not started, draining one SQLite
+// outbox table that is created empty.
+func newSQLiteRelay(t *testing.T, sess *essentials.Session) (*Relay, *Source) {
+	t.Helper()
+	source := &Source{
+		Name:       "shop",
+		DriverName: "sqlite3",
+		DataSource: "file:" + filepath.Join(t.TempDir(), "shop.db"),
+		Table:      `"matcha_outbox"`,
+	}
+	relay := NewRelay(sess, []*Source{source})
+	err := relay.open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(relay.closeAll)
+	script, err := CreateDialectTableScript(essentials.DialectSQLite, source.Table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = relay.dbs[source.Name].Exec(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return relay, source
+}
+
+func TestSQLiteOutboxRelay(t *testing.T) {
+	sess := newSQLiteSession(t)
+	relay, source := newSQLiteRelay(t, sess)
+	db := relay.dbs[source.Name]
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without the x-event-* extensions the event cannot be published.
+	id, err := New(source.Table).Dialect(essentials.DialectSQLite).Publish(&essentials.Payload{Env: "dev", MessageType: "order.created", Content: "{}"}, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	row := func() (state int, attempts int, remark sql.NullString) {
+		t.Helper()
+		err := db.QueryRow(`SELECT "State", "Attempts", "Remark" FROM "matcha_outbox" WHERE "ID" = ?`, id).Scan(&state, &attempts, &remark)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	found, err := relay.relayNext(source)
+	if !found || err == nil {
+		t.Fatalf("unpublishable row: found %v, err %v", found, err)
+	}
+	state, attempts, remark := row()
+	if state != statePending || attempts != 1 || !strings.Contains(remark.String, "x-event-exchange") {
+		t.Errorf("after a failed attempt: state %d, attempts %d, remark %q", state, attempts, remark.String)
+	}
+
+	// A row whose message already exists is only marked relayed.
+	conn, err := sess.CreateConnectionFactory().Database()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mtx, err := conn.BeginTx(sql.LevelDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = essentials.AppendMessage(&essentials.Payload{MessageID: id, MessageType: "order.created", Content: "{}"}, nil, mtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = mtx.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err = relay.relayNext(source)
+	if !found || err != nil {
+		t.Fatalf("relayed row: found %v, err %v", found, err)
+	}
+	if state, _, _ = row(); state != stateRelayed {
+		t.Errorf("state %d, want relayed", state)
+	}
+
+	found, err = relay.relayNext(source)
+	if found || err != nil {
+		t.Fatalf("empty outbox: found %v, err %v", found, err)
+	}
+}
+
+func TestSQLiteOutboxRelayContinuesTrace(t *testing.T) {
+	sess := newSQLiteSession(t)
+	var spans bytes.Buffer
+	sess.SETSpanExporter(essentials.NewWriterSpanExporter(&spans))
+	relay, source := newSQLiteRelay(t, sess)
+	db := relay.dbs[source.Name]
+
+	trace, _ := essentials.ParseTraceContext("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "shop=1")
+	payload := &essentials.Payload{
+		Env:         "dev",
+		MessageType: "invoice.send",
+		Content:     "{}",
+		Extensions:  map[string]string{"expression": "", "delay": "60"},
+	}
+	payload.SetTrace(trace)
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := New(source.Table).Dialect(essentials.DialectSQLite).Schedule(payload, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var traceParent, traceState string
+	err = db.QueryRow(`SELECT "TraceParent", "TraceState" FROM "matcha_outbox" WHERE "ID" = ?`, id).Scan(&traceParent, &traceState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if traceParent != trace.TraceParent() || traceState != "shop=1" {
+		t.Errorf("stored trace %q %q", traceParent, traceState)
+	}
+
+	found, err := relay.relayNext(source)
+	if !found || err != nil {
+		t.Fatalf("found %v, err %v", found, err)
+	}
+	var relaySpan *essentials.Span
+	decoder := json.NewDecoder(&spans)
+	for decoder.More() {
+		var span essentials.Span
+		err = decoder.Decode(&span)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if span.Name == "relay outbox" {
+			relaySpan = &span
+		}
+	}
+	if relaySpan == nil || relaySpan.TraceID != trace.TraceID || relaySpan.ParentSpanID != trace.SpanID {
+		t.Fatalf("relay span %+v does not continue %s", relaySpan, trace.TraceParent())
+	}
+
+	conn, err := sess.CreateConnectionFactory().Database()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	msg, err := essentials.FindOneMessage(id, false, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := msg.Trace()
+	if stored.TraceID != trace.TraceID || stored.SpanID != relaySpan.SpanID || stored.State != "shop=1" {
+		t.Errorf("message trace %+v, want the relay span of %s", stored, trace.TraceParent())
+	}
+}
+
+func TestSQLiteOutboxRelayRequiresEnv(t *testing.T) {
+	sess := newSQLiteSession(t)
+	relay, source := newSQLiteRelay(t, sess)
+	db := relay.dbs[source.Name]
+
+	outbox := New(source.Table).Dialect(essentials.DialectSQLite)
+	for _, env := range []string{"", "qa"} {
+		_, err := outbox.Schedule(&essentials.Payload{
+			Env:         env,
+			MessageType: "invoice.send",
+			Content:     "{}",
+			Extensions:  map[string]string{"expression": "", "delay": "60"},
+		}, db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found, err := relay.relayNext(source)
+		if !found || err == nil {
+			t.Errorf("env %q: found %v, err %v", env, found, err)
+		}
+	}
+	var relayed int
+	err := db.QueryRow(`SELECT COUNT(*) FROM "matcha_outbox" WHERE "State" <> ?`, statePending).Scan(&relayed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if relayed != 0 {
+		t.Errorf("%d rows without a valid env left the outbox", relayed)
+	}
+}
+
+func TestSQLiteOutboxRelayPublishesAsSource(t *testing.T) {
+	sess := newSQLiteSession(t)
+	relay, source := newSQLiteRelay(t, sess)
+
+	payload := &essentials.Payload{
+		Env:         "dev",
+		MessageType: "invoice.send",
+		Content:     "{}",
+		Extensions:  map[string]string{"expression": "", "delay": "60"},
+	}
+	payload.SetPublisher("billing")
+	id, err := New(source.Table).Dialect(essentials.DialectSQLite).Schedule(payload, relay.dbs[source.Name])
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := relay.relayNext(source)
+	if !found || err != nil {
+		t.Fatalf("found %v, err %v", found, err)
+	}
+
+	conn, err := sess.CreateConnectionFactory().Database()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	msg, err := essentials.FindOneMessage(id, false, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Publisher != source.Name {
+		t.Errorf("publisher %s, want the source %s", msg.Publisher, source.Name)
+	}
+}
@@ -0,0 +1,246 @@
+package outbox
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/standardcore/Matcha/backgroundjob"
+	"github.com/standardcore/Matcha/essentials"
+	"github.com/standardcore/Matcha/rtevent"
+)
+
+// Source is an outbox table in a service database drained by the Relay. The
+// table is read with the SQL dialect of DriverName, PostgreSQL by default.
+// Name is the publisher of the messages relayed from it, whatever client the
+// payloads name.
+type Source struct {
+	Name       string `json:"name"`
+	DriverName string `json:"db_driver_name"`
+	DataSource string `json:"datasource"`
+	Table      string `json:"table"`
+}
+
+// Relay moves pending outbox rows into matcha. A row is marked relayed in the
+// same service transaction that locked it, and a row whose ID already exists
+// as a matcha message is only marked, so a crash between the two steps never
+// publishes a payload twice.
+type Relay struct {
+	sess        *essentials.Session
+	sources     []*Source
+	interval    time.Duration
+	maxAttempts int
+	dbs         map[string]*sql.DB
+	dialects    map[string]essentials.Dialect
+	wg          sync.WaitGroup
+	running     bool
+	mu          sync.Mutex
+}
+
+// NewRelay creates a relay polling every `outbox_interval` seconds (default
+// 1) while the sources are empty. A row failing `outbox_max_attempts` times
+// (default 10) is rejected.
+func NewRelay(sess *essentials.Session, sources []*Source) *Relay {
+	r := &Relay{
+		sess:        sess,
+		sources:     sources,
+		interval:    time.Second,
+		maxAttempts: 10,
+		dbs:         make(map[string]*sql.DB),
+		dialects:    make(map[string]essentials.Dialect),
+	}
+	if v := sess.LoadOrEmpty("outbox_interval"); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err == nil && seconds > 0 {
+			r.interval = time.Duration(seconds) * time.Second
+		}
+	}
+	if v := sess.LoadOrEmpty("outbox_max_attempts"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err == nil && n > 0 {
+			r.maxAttempts = n
+		}
+	}
+	return r
+}
+
+func (r *Relay) Start() error {
+	if len(r.sources) == 0 || r.getRunning() {
+		return nil
+	}
+	err := r.open()
+	if err != nil {
+		return err
+	}
+	r.setRunning(true)
+	r.wg.Add(1)
+	go r.poll()
+	return nil
+}
+
+// open connects to the database of every source.
+func (r *Relay) open() error {
+	for _, source := range r.sources {
+		driverName := source.DriverName
+		if driverName == "" {
+			driverName = "postgres"
+		}
+		dialect, err := essentials.DialectOf(driverName)
+		if err != nil {
+			r.closeAll()
+			return essentials.WrapError("Relay:"+source.Name, err)
+		}
+		db, err := sql.Open(driverName, dialect.DataSource(source.DataSource))
+		if err != nil {
+			r.closeAll()
+			return essentials.WrapError("Relay:"+source.Name, err)
+		}
+		r.dbs[source.Name] = db
+		r.dialects[source.Name] = dialect
+	}
+	return nil
+}
+
+func (r *Relay) Stop() {
+	r.setRunning(false)
+	r.wg.Wait()
+	r.closeAll()
+}
+
+func (r *Relay) closeAll() {
+	for name, db := range r.dbs {
+		db.Close()
+		delete(r.dbs, name)
+		delete(r.dialects, name)
+	}
+}
+
+func (r *Relay) setRunning(v bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.running = v
+}
+
+func (r *Relay) getRunning() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.running
+}
+
+func (r *Relay) poll() {
+	defer r.wg.Done()
+	for r.getRunning() {
+		relayed := false
+		for _, source := range r.sources {
+			ok, err := r.relayNext(source)
+			if err != nil {
+				r.sess.Logger().Errorln(essentials.WrapError("Relay:"+source.Name, err))
+			}
+			relayed = relayed || ok
+		}
+		if !relayed {
+			time.Sleep(r.interval)
+		}
+	}
+}
+
+// relayNext relays at most one pending row of source and reports whether one
+// was found.
+func (r *Relay) relayNext(source *Source) (bool, error) {
+	dialect := r.dialects[source.Name]
+	tx, err := r.dbs[source.Name].Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query, args, err := compile(dialect, "FindOnePendingOutboxRow", source.Table, statePending)
+	if err != nil {
+		return false, err
+	}
+	var id, kind, content, traceParent, traceState string
+	var attempts int
+	err = tx.QueryRow(query, args...).Scan(&id, &kind, &content, &attempts, &traceParent, &traceState)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	trace, _ := essentials.ParseTraceContext(traceParent, traceState)
+	relayErr := r.relay(source, id, Kind(kind), content, trace)
+	if relayErr != nil {
+		state := statePending
+		if attempts+1 >= r.maxAttempts {
+			state = stateRejected
+		}
+		query, args, err = compile(dialect, "OutboxRowFailed", source.Table, state, relayErr.Error(), id)
+		if err == nil {
+			_, err = tx.Exec(query, args...)
+		}
+		if err != nil {
+			return true, err
+		}
+		err = tx.Commit()
+		if err != nil {
+			return true, err
+		}
+		return true, essentials.WrapError(id, relayErr)
+	}
+
+	query, args, err = compile(dialect, "OutboxRowRelayed", source.Table, stateRelayed, time.Now().Unix(), id)
+	if err == nil {
+		_, err = tx.Exec(query, args...)
+	}
+	if err != nil {
+		return true, err
+	}
+	return true, tx.Commit()
+}
+
+// relay hands the payload of a row to matcha in a span continuing the trace
+// of the row, which the message then carries.
+func (r *Relay) relay(source *Source, id string, kind Kind, content string, trace essentials.TraceContext) (err error) {
+	span := r.sess.StartSpan("relay outbox", trace)
+	span.SetAttribute("message_id", id).SetAttribute("source", source.Name)
+	defer func() { span.Finish(err) }()
+
+	conn, err := r.sess.CreateConnectionFactory().Database()
+	if err != nil {
+		return err
+	}
+	_, err = essentials.FindOneMessage(id, false, conn)
+	conn.Close()
+	if err == nil {
+		essentials.LogWith(r.sess.Logger(), essentials.Fields{"message_id": id}).Infoln(fmt.Sprintf("outbox message %s was already relayed", id))
+		return nil
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+
+	var payload essentials.Payload
+	err = json.Unmarshal([]byte(content), &payload)
+	if err != nil {
+		return err
+	}
+	payload.Env, err = r.sess.RequireEnv(payload.Env)
+	if err != nil {
+		return err
+	}
+	payload.MessageID = id
+	payload.SetPublisher(source.Name)
+	payload.SetTrace(span.Context())
+
+	switch kind {
+	case Event:
+		_, err = rtevent.PublishEvent(r.sess, &payload)
+	case Job:
+		_, err = backgroundjob.AddJob(r.sess, &payload)
+	default:
+		err = fmt.Errorf("unknown kind '%s'", kind)
+	}
+	return err
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -18,14 +19,25 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	path := filepath.Join(curr, "resources")
+	resPath := filepath.Join(curr, "resources")
 	codePath := filepath.Join(curr, "essentials")
 
-	files, err := ioutil.ReadDir(filepath.Join(path, "scripts"))
+	bind(filepath.Join(resPath, "scripts"), filepath.Join(codePath, "resources.go"), "NewScriptResources", func(name string) string {
+		return strings.ToLower(strings.Replace(name, ".", "_", -1))
+	})
+	bind(filepath.Join(resPath, "migrations"), filepath.Join(codePath, "migration_resources.go"), "NewMigrationResources", func(name string) string {
+		return name
+	})
+}
+
+// bind embeds every file of dir, and of its sub directories, into filename
+// as a ScriptResources constructor named ctor. keyOf maps a file name to its
+// resource key; files of a sub directory are keyed "<sub directory>/<key>".
+func bind(dir string, filename string, ctor string, keyOf func(name string) string) {
+	files, err := listFiles(dir, "")
 	if err != nil {
 		panic(err)
 	}
-	filename := filepath.Join(codePath, "resources.go")
 	if _, err := os.Stat(filename); os.IsExist(err) {
 		err = os.Remove(filename)
 		if err != nil {
@@ -37,22 +49,23 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	defer target.Close()
 	writer := bufio.NewWriter(target)
 	writeHeader(writer)
 	writeFileList(files, writer)
-	writeCtorHeader(writer)
+	writeCtorHeader(ctor, writer)
 	for _, file := range files {
-		if file.IsDir() {
-			return
-		}
-		varName := strings.ToLower(strings.Replace(file.Name(), ".", "_", -1))
-		fn := filepath.Join(path, "scripts", file.Name())
+		fn := filepath.Join(dir, filepath.FromSlash(file))
 
 		data, err := ioutil.ReadFile(fn)
 		if err != nil {
 			panic(err)
 		}
-		varcode := formatData(varName, data)
+		key := keyOf(path.Base(file))
+		if sub := path.Dir(file); sub != "." {
+			key = sub + "/" + key
+		}
+		varcode := formatData(key, data)
 		writer.WriteString(varcode + newLine)
 		writer.WriteString(newLine)
 	}
@@ -61,7 +74,32 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	fmt.Printf("%d files embedded \r\n", len(files))
+	fmt.Printf("%d files embedded into %s \r\n", len(files), filepath.Base(filename))
+}
+
+// listFiles returns the files of dir and of its sub directories as slash
+// separated paths relative to the top directory.
+func listFiles(dir string, prefix string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(infos))
+	for _, info := range infos {
+		if !info.IsDir() {
+			files = append(files, prefix+info.Name())
+		}
+	}
+	for _, info := range infos {
+		if info.IsDir() {
+			sub, err := listFiles(filepath.Join(dir, info.Name()), prefix+info.Name()+"/")
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, sub...)
+		}
+	}
+	return files, nil
 }
 
 func formatData(name string, data []byte) string {
@@ -76,16 +114,16 @@ func writeHeader(writer *bufio.Writer) {
 	writer.WriteString(newLine)
 }
 
-func writeFileList(files []os.FileInfo, writer *bufio.Writer) {
+func writeFileList(files []string, writer *bufio.Writer) {
 	writer.WriteString(newLine)
 	for _, v := range files {
-		writer.WriteString(`//` + v.Name() + newLine)
+		writer.WriteString(`//` + v + newLine)
 	}
 	writer.WriteString(newLine)
 }
 
-func writeCtorHeader(writer *bufio.Writer) {
-	writer.WriteString("func NewScriptResources() *ScriptResources {" + newLine)
+func writeCtorHeader(ctor string, writer *bufio.Writer) {
+	writer.WriteString("func " + ctor + "() *ScriptResources {" + newLine)
 	writer.WriteString("	r := &ScriptResources{}" + newLine)
 }
 
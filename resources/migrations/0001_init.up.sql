@@ -0,0 +1,98 @@
+CREATE SCHEMA IF NOT EXISTS "${SCHEMA}";
+
+CREATE TABLE IF NOT EXISTS "${SCHEMA}"."citadel.messages" (
+  "ID" VARCHAR(36) NOT NULL,
+  "MessageType" VARCHAR(64) NOT NULL,
+  "Content" TEXT NOT NULL DEFAULT '',
+  "State" SMALLINT NOT NULL,
+  "StateName" VARCHAR(32) NOT NULL,
+  "Retry" INT NOT NULL DEFAULT 0,
+  "CreationTime" BIGINT NOT NULL,
+  "CreationTimeString" VARCHAR(32) NOT NULL,
+  "Publisher" VARCHAR(128) NOT NULL DEFAULT '',
+  "PublishTime" BIGINT NOT NULL DEFAULT 0,
+  "PublishTimeString" VARCHAR(32) NOT NULL DEFAULT '',
+  "Env" VARCHAR(32) NOT NULL DEFAULT '',
+  CONSTRAINT "PK_citadel.messages" PRIMARY KEY ("ID")
+);
+CREATE INDEX IF NOT EXISTS "IX_citadel.messages_MessageType_State" ON "${SCHEMA}"."citadel.messages" ("MessageType", "State", "CreationTime");
+
+CREATE TABLE IF NOT EXISTS "${SCHEMA}"."citadel.message_logs" (
+  "ID" VARCHAR(36) NOT NULL,
+  "MessageID" VARCHAR(36) NOT NULL,
+  "OrignalState" SMALLINT NOT NULL,
+  "OrignalStateName" VARCHAR(32) NOT NULL,
+  "State" SMALLINT NOT NULL,
+  "StateName" VARCHAR(32) NOT NULL,
+  "CreationTime" BIGINT NOT NULL,
+  "CreationTimeString" VARCHAR(32) NOT NULL,
+  CONSTRAINT "PK_citadel.message_logs" PRIMARY KEY ("ID")
+);
+CREATE INDEX IF NOT EXISTS "IX_citadel.message_logs_MessageID" ON "${SCHEMA}"."citadel.message_logs" ("MessageID", "CreationTime");
+
+CREATE TABLE IF NOT EXISTS "${SCHEMA}"."citadel.subscriptions" (
+  "ID" VARCHAR(36) NOT NULL,
+  "MessageID" VARCHAR(36) NOT NULL,
+  "ReceiverTag" VARCHAR(128) NOT NULL,
+  "Exchange" VARCHAR(255) NOT NULL,
+  "RouteKey" VARCHAR(255) NOT NULL,
+  "StateName" VARCHAR(32) NOT NULL,
+  "LastMotifyTime" BIGINT NOT NULL DEFAULT 0,
+  "LastMotifyTimeString" VARCHAR(32) NOT NULL DEFAULT '',
+  CONSTRAINT "PK_citadel.subscriptions" PRIMARY KEY ("ID")
+);
+CREATE INDEX IF NOT EXISTS "IX_citadel.subscriptions_MessageID" ON "${SCHEMA}"."citadel.subscriptions" ("MessageID", "ReceiverTag");
+
+CREATE TABLE IF NOT EXISTS "${SCHEMA}"."citadel.flows" (
+  "ID" VARCHAR(36) NOT NULL,
+  "SubscriptionID" VARCHAR(36) NOT NULL,
+  "StateName" VARCHAR(32) NOT NULL,
+  "Remark" TEXT NOT NULL DEFAULT '',
+  "CreationTime" BIGINT NOT NULL,
+  "CreationTimeString" VARCHAR(32) NOT NULL,
+  CONSTRAINT "PK_citadel.flows" PRIMARY KEY ("ID")
+);
+CREATE INDEX IF NOT EXISTS "IX_citadel.flows_SubscriptionID" ON "${SCHEMA}"."citadel.flows" ("SubscriptionID", "CreationTime");
+
+CREATE TABLE IF NOT EXISTS "${SCHEMA}"."citadel.events" (
+  "ID" VARCHAR(36) NOT NULL,
+  "MessageID" VARCHAR(36) NOT NULL,
+  "Exchange" VARCHAR(255) NOT NULL,
+  "RouteKey" VARCHAR(255) NOT NULL,
+  "Queue" VARCHAR(255) NULL,
+  CONSTRAINT "PK_citadel.events" PRIMARY KEY ("ID")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "UX_citadel.events_MessageID" ON "${SCHEMA}"."citadel.events" ("MessageID");
+
+CREATE TABLE IF NOT EXISTS "${SCHEMA}"."citadel.jobs" (
+  "ID" VARCHAR(36) NOT NULL,
+  "MessageID" VARCHAR(36) NOT NULL,
+  "Expression" VARCHAR(255) NOT NULL DEFAULT '',
+  "Kind" SMALLINT NOT NULL,
+  "KindName" VARCHAR(32) NOT NULL,
+  "DelaySeconds" INT NOT NULL DEFAULT 0,
+  CONSTRAINT "PK_citadel.jobs" PRIMARY KEY ("ID")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "UX_citadel.jobs_MessageID" ON "${SCHEMA}"."citadel.jobs" ("MessageID");
+
+CREATE TABLE IF NOT EXISTS "${SCHEMA}"."citadel.sub_templates" (
+  "ID" VARCHAR(36) NOT NULL,
+  "Name" VARCHAR(128) NOT NULL,
+  "Description" TEXT NOT NULL DEFAULT '',
+  "CreationTime" BIGINT NOT NULL,
+  "CreationTimeString" VARCHAR(32) NOT NULL,
+  CONSTRAINT "PK_citadel.sub_templates" PRIMARY KEY ("ID")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "UX_citadel.sub_templates_Name" ON "${SCHEMA}"."citadel.sub_templates" ("Name");
+
+CREATE TABLE IF NOT EXISTS "${SCHEMA}"."citadel.sub_template_details" (
+  "ID" VARCHAR(36) NOT NULL,
+  "TemplateID" VARCHAR(36) NOT NULL,
+  "ReceiverTag" VARCHAR(128) NOT NULL,
+  "Exchange" VARCHAR(255) NOT NULL,
+  "RouteKey" VARCHAR(255) NOT NULL,
+  "CreationTime" BIGINT NOT NULL,
+  "CreationTimeString" VARCHAR(32) NOT NULL,
+  CONSTRAINT "PK_citadel.sub_template_details" PRIMARY KEY ("ID")
+);
+CREATE INDEX IF NOT EXISTS "IX_citadel.sub_template_details_TemplateID" ON "${SCHEMA}"."citadel.sub_template_details" ("TemplateID");
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "${SCHEMA}"."IX_citadel.jobs_NextFireTime";
+ALTER TABLE "${SCHEMA}"."citadel.jobs" DROP COLUMN IF EXISTS "RetryPolicy";
+ALTER TABLE "${SCHEMA}"."citadel.jobs" DROP COLUMN IF EXISTS "NextFireTime";
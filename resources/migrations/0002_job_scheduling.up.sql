@@ -0,0 +1,3 @@
+ALTER TABLE "${SCHEMA}"."citadel.jobs" ADD COLUMN IF NOT EXISTS "NextFireTime" BIGINT NULL;
+ALTER TABLE "${SCHEMA}"."citadel.jobs" ADD COLUMN IF NOT EXISTS "RetryPolicy" TEXT NULL;
+CREATE INDEX IF NOT EXISTS "IX_citadel.jobs_NextFireTime" ON "${SCHEMA}"."citadel.jobs" ("NextFireTime") WHERE "NextFireTime" IS NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE "${SCHEMA}"."citadel.message_logs" DROP COLUMN IF EXISTS "Remark";
@@ -0,0 +1 @@
+ALTER TABLE "${SCHEMA}"."citadel.message_logs" ADD COLUMN IF NOT EXISTS "Remark" TEXT NULL;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "${SCHEMA}"."IX_citadel.messages_Env";
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS "IX_citadel.messages_Env" ON "${SCHEMA}"."citadel.messages" ("Env");
@@ -0,0 +1,2 @@
+ALTER TABLE "${SCHEMA}"."citadel.messages" DROP COLUMN IF EXISTS "TraceState";
+ALTER TABLE "${SCHEMA}"."citadel.messages" DROP COLUMN IF EXISTS "TraceParent";
@@ -0,0 +1,2 @@
+ALTER TABLE "${SCHEMA}"."citadel.messages" ADD COLUMN IF NOT EXISTS "TraceParent" VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE "${SCHEMA}"."citadel.messages" ADD COLUMN IF NOT EXISTS "TraceState" VARCHAR(512) NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE "${SCHEMA}"."citadel.jobs" ADD COLUMN IF NOT EXISTS "OccurrenceTime" BIGINT NULL;
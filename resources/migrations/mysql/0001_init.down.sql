@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS "${SCHEMA}"."citadel.sub_template_details";
+DROP TABLE IF EXISTS "${SCHEMA}"."citadel.sub_templates";
+DROP TABLE IF EXISTS "${SCHEMA}"."citadel.jobs";
+DROP TABLE IF EXISTS "${SCHEMA}"."citadel.events";
+DROP TABLE IF EXISTS "${SCHEMA}"."citadel.flows";
+DROP TABLE IF EXISTS "${SCHEMA}"."citadel.subscriptions";
+DROP TABLE IF EXISTS "${SCHEMA}"."citadel.message_logs";
+DROP TABLE IF EXISTS "${SCHEMA}"."citadel.messages";
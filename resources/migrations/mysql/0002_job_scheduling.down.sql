@@ -0,0 +1,3 @@
+DROP INDEX "IX_citadel.jobs_NextFireTime" ON "${SCHEMA}"."citadel.jobs";
+ALTER TABLE "${SCHEMA}"."citadel.jobs" DROP COLUMN "RetryPolicy";
+ALTER TABLE "${SCHEMA}"."citadel.jobs" DROP COLUMN "NextFireTime";
@@ -0,0 +1,3 @@
+ALTER TABLE "${SCHEMA}"."citadel.jobs" ADD COLUMN "NextFireTime" BIGINT NULL;
+ALTER TABLE "${SCHEMA}"."citadel.jobs" ADD COLUMN "RetryPolicy" TEXT NULL;
+CREATE INDEX "IX_citadel.jobs_NextFireTime" ON "${SCHEMA}"."citadel.jobs" ("NextFireTime");
@@ -0,0 +1 @@
+DROP INDEX "IX_citadel.messages_Env" ON "${SCHEMA}"."citadel.messages";
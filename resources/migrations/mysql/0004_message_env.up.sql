@@ -0,0 +1 @@
+CREATE INDEX "IX_citadel.messages_Env" ON "${SCHEMA}"."citadel.messages" ("Env");
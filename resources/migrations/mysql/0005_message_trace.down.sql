@@ -0,0 +1,2 @@
+ALTER TABLE "${SCHEMA}"."citadel.messages" DROP COLUMN "TraceState";
+ALTER TABLE "${SCHEMA}"."citadel.messages" DROP COLUMN "TraceParent";
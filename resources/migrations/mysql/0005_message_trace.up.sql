@@ -0,0 +1,2 @@
+ALTER TABLE "${SCHEMA}"."citadel.messages" ADD COLUMN "TraceParent" VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE "${SCHEMA}"."citadel.messages" ADD COLUMN "TraceState" VARCHAR(512) NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE "${SCHEMA}"."citadel.jobs" ADD COLUMN "OccurrenceTime" BIGINT NULL;
@@ -0,0 +1,96 @@
+CREATE TABLE IF NOT EXISTS "${SCHEMA}"."citadel.messages" (
+  "ID" VARCHAR(36) NOT NULL,
+  "MessageType" VARCHAR(64) NOT NULL,
+  "Content" TEXT NOT NULL DEFAULT '',
+  "State" INTEGER NOT NULL,
+  "StateName" VARCHAR(32) NOT NULL,
+  "Retry" INTEGER NOT NULL DEFAULT 0,
+  "CreationTime" INTEGER NOT NULL,
+  "CreationTimeString" VARCHAR(32) NOT NULL,
+  "Publisher" VARCHAR(128) NOT NULL DEFAULT '',
+  "PublishTime" INTEGER NOT NULL DEFAULT 0,
+  "PublishTimeString" VARCHAR(32) NOT NULL DEFAULT '',
+  "Env" VARCHAR(32) NOT NULL DEFAULT '',
+  CONSTRAINT "PK_citadel.messages" PRIMARY KEY ("ID")
+);
+CREATE INDEX IF NOT EXISTS "${SCHEMA}"."IX_citadel.messages_MessageType_State" ON "citadel.messages" ("MessageType", "State", "CreationTime");
+
+CREATE TABLE IF NOT EXISTS "${SCHEMA}"."citadel.message_logs" (
+  "ID" VARCHAR(36) NOT NULL,
+  "MessageID" VARCHAR(36) NOT NULL,
+  "OrignalState" INTEGER NOT NULL,
+  "OrignalStateName" VARCHAR(32) NOT NULL,
+  "State" INTEGER NOT NULL,
+  "StateName" VARCHAR(32) NOT NULL,
+  "CreationTime" INTEGER NOT NULL,
+  "CreationTimeString" VARCHAR(32) NOT NULL,
+  CONSTRAINT "PK_citadel.message_logs" PRIMARY KEY ("ID")
+);
+CREATE INDEX IF NOT EXISTS "${SCHEMA}"."IX_citadel.message_logs_MessageID" ON "citadel.message_logs" ("MessageID", "CreationTime");
+
+CREATE TABLE IF NOT EXISTS "${SCHEMA}"."citadel.subscriptions" (
+  "ID" VARCHAR(36) NOT NULL,
+  "MessageID" VARCHAR(36) NOT NULL,
+  "ReceiverTag" VARCHAR(128) NOT NULL,
+  "Exchange" VARCHAR(255) NOT NULL,
+  "RouteKey" VARCHAR(255) NOT NULL,
+  "StateName" VARCHAR(32) NOT NULL,
+  "LastMotifyTime" INTEGER NOT NULL DEFAULT 0,
+  "LastMotifyTimeString" VARCHAR(32) NOT NULL DEFAULT '',
+  CONSTRAINT "PK_citadel.subscriptions" PRIMARY KEY ("ID")
+);
+CREATE INDEX IF NOT EXISTS "${SCHEMA}"."IX_citadel.subscriptions_MessageID" ON "citadel.subscriptions" ("MessageID", "ReceiverTag");
+
+CREATE TABLE IF NOT EXISTS "${SCHEMA}"."citadel.flows" (
+  "ID" VARCHAR(36) NOT NULL,
+  "SubscriptionID" VARCHAR(36) NOT NULL,
+  "StateName" VARCHAR(32) NOT NULL,
+  "Remark" TEXT NOT NULL DEFAULT '',
+  "CreationTime" INTEGER NOT NULL,
+  "CreationTimeString" VARCHAR(32) NOT NULL,
+  CONSTRAINT "PK_citadel.flows" PRIMARY KEY ("ID")
+);
+CREATE INDEX IF NOT EXISTS "${SCHEMA}"."IX_citadel.flows_SubscriptionID" ON "citadel.flows" ("SubscriptionID", "CreationTime");
+
+CREATE TABLE IF NOT EXISTS "${SCHEMA}"."citadel.events" (
+  "ID" VARCHAR(36) NOT NULL,
+  "MessageID" VARCHAR(36) NOT NULL,
+  "Exchange" VARCHAR(255) NOT NULL,
+  "RouteKey" VARCHAR(255) NOT NULL,
+  "Queue" VARCHAR(255) NULL,
+  CONSTRAINT "PK_citadel.events" PRIMARY KEY ("ID")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "${SCHEMA}"."UX_citadel.events_MessageID" ON "citadel.events" ("MessageID");
+
+CREATE TABLE IF NOT EXISTS "${SCHEMA}"."citadel.jobs" (
+  "ID" VARCHAR(36) NOT NULL,
+  "MessageID" VARCHAR(36) NOT NULL,
+  "Expression" VARCHAR(255) NOT NULL DEFAULT '',
+  "Kind" INTEGER NOT NULL,
+  "KindName" VARCHAR(32) NOT NULL,
+  "DelaySeconds" INTEGER NOT NULL DEFAULT 0,
+  CONSTRAINT "PK_citadel.jobs" PRIMARY KEY ("ID")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "${SCHEMA}"."UX_citadel.jobs_MessageID" ON "citadel.jobs" ("MessageID");
+
+CREATE TABLE IF NOT EXISTS "${SCHEMA}"."citadel.sub_templates" (
+  "ID" VARCHAR(36) NOT NULL,
+  "Name" VARCHAR(128) NOT NULL,
+  "Description" TEXT NOT NULL DEFAULT '',
+  "CreationTime" INTEGER NOT NULL,
+  "CreationTimeString" VARCHAR(32) NOT NULL,
+  CONSTRAINT "PK_citadel.sub_templates" PRIMARY KEY ("ID")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "${SCHEMA}"."UX_citadel.sub_templates_Name" ON "citadel.sub_templates" ("Name");
+
+CREATE TABLE IF NOT EXISTS "${SCHEMA}"."citadel.sub_template_details" (
+  "ID" VARCHAR(36) NOT NULL,
+  "TemplateID" VARCHAR(36) NOT NULL,
+  "ReceiverTag" VARCHAR(128) NOT NULL,
+  "Exchange" VARCHAR(255) NOT NULL,
+  "RouteKey" VARCHAR(255) NOT NULL,
+  "CreationTime" INTEGER NOT NULL,
+  "CreationTimeString" VARCHAR(32) NOT NULL,
+  CONSTRAINT "PK_citadel.sub_template_details" PRIMARY KEY ("ID")
+);
+CREATE INDEX IF NOT EXISTS "${SCHEMA}"."IX_citadel.sub_template_details_TemplateID" ON "citadel.sub_template_details" ("TemplateID");
@@ -0,0 +1,3 @@
+ALTER TABLE "${SCHEMA}"."citadel.jobs" ADD COLUMN "NextFireTime" INTEGER NULL;
+ALTER TABLE "${SCHEMA}"."citadel.jobs" ADD COLUMN "RetryPolicy" TEXT NULL;
+CREATE INDEX IF NOT EXISTS "${SCHEMA}"."IX_citadel.jobs_NextFireTime" ON "citadel.jobs" ("NextFireTime") WHERE "NextFireTime" IS NOT NULL;
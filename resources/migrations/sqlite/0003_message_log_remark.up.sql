@@ -0,0 +1 @@
+ALTER TABLE "${SCHEMA}"."citadel.message_logs" ADD COLUMN "Remark" TEXT NULL;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS "${SCHEMA}"."IX_citadel.messages_Env" ON "citadel.messages" ("Env");
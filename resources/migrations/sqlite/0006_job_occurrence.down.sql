@@ -0,0 +1 @@
+ALTER TABLE "${SCHEMA}"."citadel.jobs" DROP COLUMN "OccurrenceTime";
@@ -38,21 +38,41 @@ func ExecutePublishEvent(content []byte, request *http.Request, sess *essentials
 	// 	err = fmt.Errorf("HTTP header `%s` should be one of `%s`, `%s`, `%s`", "X-matcha-Env", "dev", "staging", "pro")
 	// 	return err
 	// }
+	if client := essentials.RequestClient(request); client != "" {
+		payload.SetPublisher(client)
+	}
+	payload.Env, err = sess.RequireEnv(essentials.RequestEnv(request, payload.Env))
+	if err != nil {
+		return err
+	}
 
+	span := sess.StartRequestSpan("ingest event", request, &payload)
+	msgid, err := PublishEvent(sess, &payload)
+	span.SetAttribute("message_id", msgid)
+	span.Finish(err)
+	return err
+}
+
+// PublishEvent stores payload as an event message and publishes it to the
+// `x-event-exchange` extension.
+func PublishEvent(sess *essentials.Session, payload *essentials.Payload) (string, error) {
 	dbConn, err := sess.CreateConnectionFactory().Database()
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer dbConn.Close()
 	transact, err := dbConn.BeginTx(sql.LevelReadCommitted)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer transact.Rollback()
 
-	msgid, err := publishEventWriteDb(sess, &payload, transact)
+	persist := sess.StartPayloadSpan("persist event", payload)
+	msgid, err := publishEventWriteDb(sess, payload, transact)
+	persist.SetAttribute("message_id", msgid)
+	persist.Finish(err)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	deliveryMsg := &essentials.DeliveryMessage{
@@ -63,17 +83,18 @@ func ExecutePublishEvent(content []byte, request *http.Request, sess *essentials
 		Extensions:  make(map[string]string),
 	}
 	deliveryMsg.Extensions["x-matcha-tag"] = payload.ClientTag
+	deliveryMsg.Extensions["x-matcha-env"] = payload.Env
 
 	exchange, ok := payload.Extensions["x-event-exchange"]
 	if !ok {
 		err = errors.New("key `x-event-exchange` not found in extensions")
-		return err
+		return "", err
 	}
 
 	routeKey, ok := payload.Extensions["x-event-routekey"]
 	if !ok {
 		err = errors.New("key `x-event-routekey` not found in extensions")
-		return err
+		return "", err
 	}
 	deliveryMsg.Extensions["x-matcha-routekey"] = routeKey
 
@@ -83,33 +104,39 @@ func ExecutePublishEvent(content []byte, request *http.Request, sess *essentials
 		routeKey = queue
 	}
 
-	err = publishEvent(sess, deliveryMsg, exchange, routeKey)
+	exchange = sess.EnvExchange(payload.Env, exchange)
+	publish := sess.StartSpan("publish event", payload.Trace())
+	publish.SetAttribute("message_id", msgid).SetAttribute("exchange", exchange).SetAttribute("route_key", routeKey)
+	headers := make(amqp.Table)
+	publish.Context().Inject(deliveryMsg.Extensions, headers)
+	err = publishEvent(sess, deliveryMsg, exchange, routeKey, headers)
+	publish.Finish(err)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	err = transact.Commit()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	transact2, err := dbConn.BeginTx(sql.LevelReadCommitted)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer transact2.Rollback()
 
 	err = publishEventPublished(msgid, transact2)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	err = transact2.Commit()
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	return msgid, nil
 }
 
 func publishEventWriteDb(sess *essentials.Session, payload *essentials.Payload, executor essentials.DbExecutor) (string, error) {
@@ -159,13 +186,8 @@ func publishWriteEvent(args *essentials.ExtensionsEventArgs, executor essentials
 	return nil
 }
 
-func publishEvent(sess *essentials.Session, payload *essentials.DeliveryMessage, exchange string, routeKey string) error {
-	conn, err := sess.CreateConnectionFactory().RabbitMQ()
-	if err != nil {
-		return err
-	}
-	defer conn.Close()
-	channel, err := conn.Channel()
+func publishEvent(sess *essentials.Session, payload *essentials.DeliveryMessage, exchange string, routeKey string, headers amqp.Table) error {
+	channel, err := sess.AMQP().AcquireChannel()
 	if err != nil {
 		return err
 	}
@@ -174,8 +196,9 @@ func publishEvent(sess *essentials.Session, payload *essentials.DeliveryMessage,
 	if err != nil {
 		return err
 	}
-	err = channel.Publish(exchange, routeKey, false, false, amqp.Publishing{
-		Body: body,
+	err = channel.Publish(exchange, routeKey, amqp.Publishing{
+		Headers: headers,
+		Body:    body,
 	})
 	if err != nil {
 		return err